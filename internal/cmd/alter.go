@@ -41,6 +41,9 @@ var alterCmd = &cobra.Command{
   # 复杂修改
   sql-diff alter -i -d "添加实名认证字段：真实姓名、身份证号、认证状态、认证时间"
   
+  # 从文件读取表结构
+  sql-diff alter -t schema/users.sql -d "添加邮箱字段"
+
   # 输出到文件
   sql-diff alter -i -d "添加商品状态字段" -o alter_product.sql`,
 	RunE: runAlter,
@@ -48,7 +51,7 @@ var alterCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(alterCmd)
-	alterCmd.Flags().StringVarP(&alterTable, "table", "t", "", "现有表的 CREATE TABLE 语句")
+	alterCmd.Flags().StringVarP(&alterTable, "table", "t", "", "现有表结构：SQL 语句、文件、目录、通配符或 -（标准输入）")
 	alterCmd.Flags().StringVarP(&alterDesc, "description", "d", "", "修改需求的自然语言描述（必需）")
 	alterCmd.Flags().StringVarP(&alterOutput, "output", "o", "", "输出文件路径（可选）")
 	alterCmd.Flags().BoolVarP(&alterInteractive, "interactive", "i", false, "交互式输入表结构")
@@ -113,7 +116,11 @@ func runAlter(cmd *cobra.Command, args []string) error {
 			errorColor.Println("✗ 必须指定 -t 参数或使用 -i 交互式输入表结构")
 			return fmt.Errorf("缺少表结构")
 		}
-		currentDDL = alterTable
+		currentDDL, err = loadSchemaInput(alterTable)
+		if err != nil {
+			errorColor.Printf("✗ 读取表结构失败: %v\n", err)
+			return err
+		}
 	}
 
 	color.New(color.FgCyan).Printf("📝 修改需求: %s\n", alterDesc)
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Bacchusgift/sql-diff/internal/config"
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"github.com/Bacchusgift/sql-diff/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// check 命令的退出码
+const (
+	ExitNoDrift     = 0 // 没有差异（或差异低于 --fail-on 阈值）
+	ExitChanges     = 1 // 存在差异，但没有破坏性变更
+	ExitDestructive = 2 // 存在破坏性变更（删除列、删除表等）
+	ExitError       = 3 // 解析失败、参数或配置错误、--verify 验证失败
+)
+
+// --fail-on 可选的阈值
+const (
+	failOnAny         = "any"
+	failOnDestructive = "destructive"
+)
+
+var checkFailOn string
+
+// checkCmd CI 检查命令
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "检查表结构差异并以退出码报告结果（用于 CI）",
+	Long: `比对源结构和目标结构，只输出指定格式的报告，并通过退出码报告结果：
+
+  0  没有差异（或差异低于 --fail-on 阈值）
+  1  存在差异，但没有破坏性变更
+  2  存在破坏性变更（删除列、删除表等）
+  3  解析失败、参数或配置错误，或 --verify 验证失败
+
+--fail-on 指定视为失败的最低级别：
+  any          存在任何差异即返回非零退出码（默认）
+  destructive  只有破坏性变更返回非零退出码`,
+	Example: `  # 存在任何差异时失败
+  sql-diff check -s schema.sql -t prod.sql
+
+  # 只在存在破坏性变更时失败，输出 JSON 报告
+  sql-diff check -s "..." -t "..." --fail-on=destructive --format json`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().StringVarP(&sourceSQL, "source", "s", "", "源结构：SQL 语句、文件、目录、通配符、mysql:// 连接串或 -（标准输入）")
+	checkCmd.Flags().StringVarP(&targetSQL, "target", "t", "", "目标结构：SQL 语句、文件、目录、通配符、mysql:// 连接串或 -（标准输入）")
+	checkCmd.Flags().StringVar(&configPath, "config", ".sql-diff-config.yaml", "配置文件路径")
+	checkCmd.Flags().StringVar(&format, "format", report.FormatText, "输出格式：text、json、yaml")
+	checkCmd.Flags().StringVar(&checkFailOn, "fail-on", failOnAny, "视为失败的最低级别：any、destructive")
+	checkCmd.Flags().BoolVar(&ignoreOrder, "ignore-column-order", false, "忽略列顺序（不生成 AFTER/FIRST）")
+	checkCmd.Flags().BoolVar(&compareAutoInc, "compare-auto-increment", false, "比较表的 AUTO_INCREMENT 计数器（默认忽略）")
+	checkCmd.Flags().StringArrayVar(&renameHints, "rename", nil, "指定列或索引的重命名，格式为 [表名.]原名称=新名称（可重复）")
+	checkCmd.Flags().StringVar(&dialectName, "dialect", "", "SQL 方言：mysql（默认）、postgres、sqlite")
+	checkCmd.Flags().BoolVar(&verifyDDL, "verify", false, verifyUsage)
+}
+
+// exitError 带退出码的错误（err 为空时只设置退出码，不输出错误信息）
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("退出码 %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if checkFailOn != failOnAny && checkFailOn != failOnDestructive {
+		return &exitError{code: ExitError, err: fmt.Errorf("无效的 --fail-on 值: %s（可选 any、destructive）", checkFailOn)}
+	}
+	switch format {
+	case report.FormatText, report.FormatJSON, report.FormatYAML:
+	default:
+		return &exitError{code: ExitError, err: fmt.Errorf("不支持的输出格式: %s（可选 text、json、yaml）", format)}
+	}
+	if sourceSQL == "" || targetSQL == "" {
+		return &exitError{code: ExitError, err: fmt.Errorf("必须指定 -s 和 -t 参数")}
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return &exitError{code: ExitError, err: fmt.Errorf("加载配置失败: %w", err)}
+	}
+	options, err := diffOptions(cfg)
+	if err != nil {
+		return &exitError{code: ExitError, err: err}
+	}
+
+	source, target, err := loadSchemaInputs(sourceSQL, targetSQL)
+	if err != nil {
+		return &exitError{code: ExitError, err: err}
+	}
+
+	p := parser.NewParserWithDialect(options.Dialect)
+	sourceDB, err := source.parse(p)
+	if err != nil {
+		return &exitError{code: ExitError, err: fmt.Errorf("解析源表失败: %w", err)}
+	}
+	targetDB, err := target.parse(p)
+	if err != nil {
+		return &exitError{code: ExitError, err: fmt.Errorf("解析目标表失败: %w", err)}
+	}
+
+	diff := compareSchemas(sourceDB, targetDB, options)
+	if verifyDDL {
+		if err := verifyDiff(sourceDB, targetDB, diff, options); err != nil {
+			return &exitError{code: ExitError, err: err}
+		}
+	}
+	if err := printCheckReport(diff); err != nil {
+		return &exitError{code: ExitError, err: err}
+	}
+
+	if code := checkExitCode(diff.Risk(), checkFailOn); code != ExitNoDrift {
+		return &exitError{code: code}
+	}
+	return nil
+}
+
+// printCheckReport 按 --format 输出报告（文本格式为无颜色的摘要和 DDL）
+func printCheckReport(diff *differ.DatabaseDiff) error {
+	if format != report.FormatText {
+		data, err := report.New(diff).Marshal(format)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	printPlainDiff(diff)
+	return nil
+}
+
+// printPlainDiff 输出无颜色的差异摘要和 DDL
+func printPlainDiff(diff *differ.DatabaseDiff) {
+	fmt.Println(diff.Summary())
+	for _, ddl := range diff.GenerateDDL() {
+		fmt.Println(ddl + ";")
+	}
+}
+
+// checkExitCode 根据最高风险等级和失败阈值计算退出码
+func checkExitCode(risk differ.RiskLevel, failOn string) int {
+	switch {
+	case risk == differ.RiskNone:
+		return ExitNoDrift
+	case risk == differ.RiskDestructive:
+		return ExitDestructive
+	case failOn == failOnDestructive:
+		return ExitNoDrift
+	default:
+		return ExitChanges
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+)
+
+func TestCheckExitCode(t *testing.T) {
+	tests := []struct {
+		risk     differ.RiskLevel
+		failOn   string
+		expected int
+	}{
+		{differ.RiskNone, failOnAny, ExitNoDrift},
+		{differ.RiskSafe, failOnAny, ExitChanges},
+		{differ.RiskWarning, failOnAny, ExitChanges},
+		{differ.RiskDestructive, failOnAny, ExitDestructive},
+		{differ.RiskNone, failOnDestructive, ExitNoDrift},
+		{differ.RiskSafe, failOnDestructive, ExitNoDrift},
+		{differ.RiskWarning, failOnDestructive, ExitNoDrift},
+		{differ.RiskDestructive, failOnDestructive, ExitDestructive},
+	}
+
+	for _, tt := range tests {
+		if code := checkExitCode(tt.risk, tt.failOn); code != tt.expected {
+			t.Errorf("风险等级 %s、--fail-on=%s: 期望退出码 %d，实际为 %d", tt.risk, tt.failOn, tt.expected, code)
+		}
+	}
+}
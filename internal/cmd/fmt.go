@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtWrite bool // 将格式化结果写回文件
+	fmtList  bool // 只列出格式不规范的文件
+)
+
+// fmtCmd 表结构格式化命令
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [文件或目录...]",
+	Short: "将建表脚本格式化为规范形式",
+	Long: `解析建表脚本并按规范形式重新输出：关键字和类型大写、每列一行、
+属性按固定顺序排列、省略与默认值相同的设置，表保持脚本中的出现顺序。
+同一结构总是得到相同的输出，适合提交到版本库后用 git diff 审查结构变更。
+
+只保留表结构定义，ALTER TABLE、DROP TABLE 等语句应用到结构后再输出，SET 等其他语句和注释不会输出。
+脚本中含有 INSERT、视图、触发器、存储过程等无法重新生成的语句时会给出警告，-w 拒绝改写这样的文件。
+未指定文件或指定 - 时从标准输入读取，结果写到标准输出；目录会读取其中（含子目录）所有 .sql 文件。`,
+	Example: `  # 格式化并输出到标准输出
+  sql-diff fmt schema.sql
+
+  # 直接改写目录下的所有 .sql 文件
+  sql-diff fmt -w schema/
+
+  # 在 CI 中检查文件是否已经格式化（存在未格式化的文件时退出码为 1）
+  sql-diff fmt -l schema/
+
+  # 格式化 mysqldump 导出的表结构
+  mysqldump --no-data mydb | sql-diff fmt
+
+  # 格式化 PostgreSQL 脚本
+  sql-diff fmt --dialect postgres schema.sql`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runFmt,
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "将结果写回原文件，而不是输出到标准输出")
+	fmtCmd.Flags().BoolVarP(&fmtList, "list", "l", false, "只列出格式与规范形式不同的文件")
+	fmtCmd.Flags().StringVar(&dialectName, "dialect", "", "SQL 方言：mysql（默认）、postgres、sqlite")
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	dialect, err := parser.ParseDialect(dialectName)
+	if err != nil {
+		return err
+	}
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+
+	if len(args) == 0 || (len(args) == 1 && args[0] == stdinInput) {
+		if fmtWrite || fmtList {
+			return fmt.Errorf("从标准输入读取时不能使用 -w 或 -l")
+		}
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		formatted, skipped, err := formatSchemaSQL(string(data), dialect)
+		if err != nil {
+			return err
+		}
+		warnSkippedStatements(errOut, "", skipped)
+		_, err = io.WriteString(out, formatted)
+		return err
+	}
+
+	files, err := fmtFiles(args)
+	if err != nil {
+		return err
+	}
+	unformatted := 0
+	for _, path := range files {
+		changed, err := formatFile(path, dialect, out, errOut)
+		if err != nil {
+			return err
+		}
+		if changed {
+			unformatted++
+		}
+	}
+	if fmtList && unformatted > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// fmtFiles 展开参数中的目录，返回需要格式化的文件列表
+func fmtFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("文件不存在: %s", arg)
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		found, err := findSQLFiles(arg)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// formatFile 格式化单个文件，返回文件内容是否与规范形式不同
+// -l 时输出文件名，-w 时写回文件（文件中有无法重新生成的语句时返回错误），
+// 否则将结果写到 out，并在 errOut 中警告不会输出的语句
+func formatFile(path string, dialect parser.Dialect, out, errOut io.Writer) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("读取文件 %s 失败: %w", path, err)
+	}
+	formatted, skipped, err := formatSchemaSQL(string(data), dialect)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	changed := formatted != string(data)
+	switch {
+	case fmtList:
+		if changed {
+			fmt.Fprintln(out, path)
+		}
+	case fmtWrite:
+		if len(skipped) > 0 {
+			return false, fmt.Errorf("%s: 以下语句不是表结构定义，改写后会丢失，未写回文件:\n%s", path, summarizeStatements(skipped))
+		}
+		if changed {
+			if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+				return false, fmt.Errorf("写入文件 %s 失败: %w", path, err)
+			}
+		}
+	default:
+		warnSkippedStatements(errOut, path+": ", skipped)
+		if _, err := io.WriteString(out, formatted); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// warnSkippedStatements 警告格式化结果中不会输出的语句，prefix 为文件名前缀
+func warnSkippedStatements(w io.Writer, prefix string, skipped []string) {
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "%s警告: 以下语句不是表结构定义，不会输出:\n%s\n", prefix, summarizeStatements(skipped))
+	}
+}
+
+// summarizeStatements 每条语句取第一行（过长时截断），缩进后逐行列出
+func summarizeStatements(statements []string) string {
+	lines := make([]string, len(statements))
+	for i, statement := range statements {
+		line, _, _ := strings.Cut(statement, "\n")
+		if runes := []rune(line); len(runes) > 60 {
+			line = string(runes[:60]) + "..."
+		}
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatSchemaSQL 解析建表脚本并输出规范形式，同时返回脚本中不会输出的语句（见 SkippedStatements）
+func formatSchemaSQL(sql string, dialect parser.Dialect) (string, []string, error) {
+	p := parser.NewParserWithDialect(dialect).(*parser.DDLParser)
+	db, err := parseSchemaScript(p, sql)
+	if err != nil {
+		return "", nil, fmt.Errorf("解析失败: %w", err)
+	}
+	skipped, err := p.SkippedStatements(sql)
+	if err != nil {
+		return "", nil, fmt.Errorf("解析失败: %w", err)
+	}
+	return differ.FormatSchema(db, dialect), skipped, nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestFormatFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.sql")
+	writeFile(t, path, "create table users (id int not null, name varchar(20));")
+	expected := "CREATE TABLE users (\n  id INT NOT NULL,\n  name VARCHAR(20)\n);\n"
+
+	defer func() { fmtList, fmtWrite = false, false }()
+
+	var out bytes.Buffer
+	fmtList = true
+	changed, err := formatFile(path, parser.MySQL, &out, &out)
+	if err != nil || !changed || out.String() != path+"\n" {
+		t.Fatalf("-l 应列出未格式化的文件: changed=%v err=%v 输出 %q", changed, err, out.String())
+	}
+
+	out.Reset()
+	fmtList, fmtWrite = false, true
+	if _, err := formatFile(path, parser.MySQL, &out, &out); err != nil {
+		t.Fatalf("格式化失败: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != expected || out.Len() != 0 {
+		t.Errorf("-w 应将结果写回文件，实际内容:\n%s", data)
+	}
+
+	fmtWrite = false
+	changed, err = formatFile(path, parser.MySQL, &out, &out)
+	if err != nil || changed || out.String() != expected {
+		t.Errorf("已格式化的文件不应再有变化: changed=%v err=%v 输出:\n%s", changed, err, out.String())
+	}
+}
+
+func TestFormatSchemaSQLError(t *testing.T) {
+	if _, _, err := formatSchemaSQL("SET NAMES utf8mb4;", parser.MySQL); err == nil {
+		t.Errorf("没有建表语句时应返回错误")
+	}
+}
+
+func TestFormatFileSkippedStatements(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dump.sql")
+	original := "SET NAMES utf8mb4;\ncreate table a (id int);\nINSERT INTO a VALUES (1);\n"
+	writeFile(t, path, original)
+
+	defer func() { fmtList, fmtWrite = false, false }()
+
+	// -w 拒绝改写含有数据等无法重新生成的语句的文件
+	var out, errOut bytes.Buffer
+	fmtWrite = true
+	if _, err := formatFile(path, parser.MySQL, &out, &errOut); err == nil || !strings.Contains(err.Error(), "INSERT INTO a VALUES (1)") {
+		t.Errorf("期望 -w 拒绝改写并列出被丢弃的语句，实际为 %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("文件不应被改写，实际内容:\n%s", data)
+	}
+
+	// 输出到标准输出时给出警告，SET 等会话语句不计入
+	fmtWrite = false
+	if _, err := formatFile(path, parser.MySQL, &out, &errOut); err != nil {
+		t.Fatalf("格式化失败: %v", err)
+	}
+	if warning := errOut.String(); !strings.Contains(warning, "INSERT INTO a VALUES (1)") || strings.Contains(warning, "SET NAMES") {
+		t.Errorf("警告信息不正确: %q", warning)
+	}
+	if out.String() != "CREATE TABLE a (\n  id INT\n);\n" {
+		t.Errorf("格式化结果不正确:\n%s", out.String())
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/config"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"github.com/Bacchusgift/sql-diff/internal/report"
+	"github.com/Bacchusgift/sql-diff/internal/source"
+	"github.com/spf13/cobra"
+)
+
+// gitNullFile git 外部驱动中表示文件不存在（新增或删除）的路径
+const gitNullFile = "/dev/null"
+
+// gitCmd 比对 git 仓库中两个版本的表结构
+var gitCmd = &cobra.Command{
+	Use:   "git <版本1> <版本2> [-- 路径...]",
+	Short: "比对 git 仓库中两个版本的表结构",
+	Long: `从本地 git 仓库的对象库读取两个版本中的 .sql 文件并比对，无需检出到临时目录。
+
+路径可以是文件或目录（读取目录下所有 .sql 文件），未指定时读取整个仓库。
+版本可以是提交哈希、分支、标签或 HEAD~1 等任意 git 版本表达式。
+
+也可以作为 git diff 的外部驱动，输出语义差异而不是逐行差异：
+
+  git config diff.sql-diff.command "sql-diff git driver"
+  echo "*.sql diff=sql-diff" >> .gitattributes`,
+	Example: `  # 比对两个标签之间的表结构
+  sql-diff git v1.0 v2.0 -- db/schema/
+
+  # 比对上一次提交和当前提交，输出 JSON
+  sql-diff git HEAD~1 HEAD --format json -- db/schema/users.sql`,
+	RunE: runGit,
+}
+
+// gitDriverCmd git diff 外部驱动
+var gitDriverCmd = &cobra.Command{
+	Use:   "driver <路径> <旧文件> <旧哈希> <旧权限> <新文件> <新哈希> <新权限>",
+	Short: "作为 git diff 外部驱动输出表结构的语义差异",
+	Long: `由 git 调用（diff.<驱动>.command 或 GIT_EXTERNAL_DIFF），参数格式见 git 文档中的 GIT_EXTERNAL_DIFF。
+文件重命名时 git 会额外传入新路径和相似度信息。`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 7 && len(args) != 9 {
+			return fmt.Errorf("git 外部驱动需要 7 个或 9 个参数，实际为 %d 个", len(args))
+		}
+		return nil
+	},
+	SilenceUsage: true,
+	RunE:         runGitDriver,
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitDriverCmd)
+
+	gitCmd.Flags().BoolVar(&enableAI, "ai", false, "启用 AI 智能分析")
+	gitCmd.Flags().StringVar(&configPath, "config", ".sql-diff-config.yaml", "配置文件路径")
+	gitCmd.Flags().StringVarP(&outputFile, "output", "o", "", "输出文件路径（默认输出到控制台）")
+	gitCmd.Flags().StringVar(&format, "format", report.FormatText, "输出格式：text、json、yaml")
+	gitCmd.Flags().StringVar(&rollbackOut, "rollback-output", "", "回滚脚本输出文件路径")
+	gitCmd.Flags().BoolVar(&ignoreOrder, "ignore-column-order", false, "忽略列顺序（不生成 AFTER/FIRST）")
+	gitCmd.Flags().BoolVar(&compareAutoInc, "compare-auto-increment", false, "比较表的 AUTO_INCREMENT 计数器（默认忽略）")
+	gitCmd.Flags().StringArrayVar(&renameHints, "rename", nil, "指定列或索引的重命名，格式为 [表名.]原名称=新名称（可重复）")
+	gitCmd.Flags().StringVar(&dialectName, "dialect", "", "SQL 方言：mysql（默认）、postgres、sqlite")
+	gitCmd.Flags().BoolVar(&verifyDDL, "verify", false, verifyUsage)
+}
+
+func runGit(cmd *cobra.Command, args []string) error {
+	// 参数解析已完成，之后的错误（如无效的版本）不再输出用法说明
+	cmd.SilenceUsage = true
+	revs, paths := args, []string(nil)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		revs, paths = args[:dash], args[dash:]
+	}
+	if len(revs) != 2 {
+		return fmt.Errorf("需要指定两个版本，例如: sql-diff git v1.0 v2.0 -- db/schema/")
+	}
+	// -- 之后的参数都视为路径，误放在路径之后的选项不会生效
+	for _, path := range paths {
+		if strings.HasPrefix(path, "-") {
+			return fmt.Errorf("路径 %s 以 - 开头，选项需要放在 -- 之前，例如: sql-diff git HEAD~1 HEAD --format json -- db/schema/", path)
+		}
+	}
+	switch format {
+	case report.FormatText, report.FormatJSON, report.FormatYAML:
+	default:
+		return fmt.Errorf("不支持的输出格式: %s（可选 text、json、yaml）", format)
+	}
+
+	cfg, err := loadRunConfig()
+	if err != nil {
+		return err
+	}
+
+	repo := source.NewGit("")
+	sourceSQL, err := readGitSchema(repo, revs[0], paths)
+	if err != nil {
+		return err
+	}
+	targetSQL, err := readGitSchema(repo, revs[1], paths)
+	if err != nil {
+		return err
+	}
+	if sourceSQL == "" && targetSQL == "" {
+		return fmt.Errorf("版本 %s 和 %s 中都没有找到 .sql 文件", revs[0], revs[1])
+	}
+
+	if format == report.FormatText {
+		infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		infoColor.Printf("       SQL 表结构比对: %s → %s\n", revs[0], revs[1])
+		infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Println()
+	}
+
+	return processComparison(gitInput(sourceSQL), gitInput(targetSQL), cfg)
+}
+
+// readGitSchema 读取指定版本的 .sql 文件并合并为一个脚本，没有 .sql 文件时返回空字符串
+func readGitSchema(repo *source.Git, rev string, paths []string) (string, error) {
+	files, err := repo.ReadSQLFiles(rev, paths)
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+	return source.JoinFiles(files), nil
+}
+
+// gitInput 将版本中的脚本作为比对输入
+// 版本中没有 .sql 文件（文件在另一个版本中新增或删除）时视为空结构，与外部驱动中的 /dev/null 一致
+func gitInput(sql string) schemaInput {
+	if sql == "" {
+		return schemaInput{schema: parser.NewDatabaseSchema()}
+	}
+	return sqlInput(sql)
+}
+
+func runGitDriver(cmd *cobra.Command, args []string) error {
+	path, oldFile, newFile := args[0], args[1], args[4]
+	newPath := path
+	if len(args) == 9 {
+		newPath = args[7]
+	}
+
+	fmt.Printf("diff --sql-diff a/%s b/%s\n", path, newPath)
+
+	oldSQL, err := readDriverFile(oldFile)
+	if err != nil {
+		return err
+	}
+	newSQL, err := readDriverFile(newFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+	options, err := diffOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	// 解析失败时只提示，不中断 git diff 对其他文件的输出
+	p := parser.NewParserWithDialect(options.Dialect)
+	oldDB, err := p.ParseScript(oldSQL)
+	if err != nil {
+		fmt.Printf("无法解析 a/%s: %v\n", path, err)
+		return nil
+	}
+	newDB, err := p.ParseScript(newSQL)
+	if err != nil {
+		fmt.Printf("无法解析 b/%s: %v\n", newPath, err)
+		return nil
+	}
+
+	printPlainDiff(compareSchemas(oldDB, newDB, options))
+	return nil
+}
+
+// readDriverFile 读取 git 传入的临时文件（/dev/null 表示文件不存在）
+func readDriverFile(path string) (string, error) {
+	if path == gitNullFile {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取文件 %s 失败: %w", path, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/report"
+)
+
+func TestRunGitRejectsOptionsAfterDash(t *testing.T) {
+	flags := gitCmd.Flags()
+	if err := flags.Parse([]string{"HEAD~1", "HEAD", "--", "db/schema/users.sql", "--format", "json"}); err != nil {
+		t.Fatalf("解析参数失败: %v", err)
+	}
+	err := runGit(gitCmd, flags.Args())
+	if err == nil || !strings.Contains(err.Error(), "--format") {
+		t.Errorf("-- 之后的选项应报错，实际为 %v", err)
+	}
+}
+
+func TestRunGitFileInOneRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("未安装 git")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s 失败: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "test")
+	writeFile(t, filepath.Join(dir, "db", "a.sql"), "CREATE TABLE a (id INT);")
+	git("add", "-A")
+	git("commit", "-q", "-m", "1")
+	writeFile(t, filepath.Join(dir, "db", "b.sql"), "CREATE TABLE b (id INT);")
+	git("add", "-A")
+	git("commit", "-q", "-m", "2")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	defer func(f string) { format = f }(format)
+	format = report.FormatJSON
+
+	run := func(path string) error {
+		flags := gitCmd.Flags()
+		if err := flags.Parse([]string{"HEAD~1", "HEAD", "--", path}); err != nil {
+			t.Fatalf("解析参数失败: %v", err)
+		}
+		return runGit(gitCmd, flags.Args())
+	}
+
+	// b.sql 只存在于 HEAD 中，HEAD~1 一侧视为空结构
+	if err := run("db/b.sql"); err != nil {
+		t.Errorf("文件只存在于一个版本中时应视为新增，实际为 %v", err)
+	}
+	if err := run("db/missing.sql"); err == nil || !strings.Contains(err.Error(), "都没有找到") {
+		t.Errorf("两个版本中都没有文件时应报错，实际为 %v", err)
+	}
+}
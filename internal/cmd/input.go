@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"github.com/Bacchusgift/sql-diff/internal/source"
+)
+
+// stdinInput 表示从标准输入读取的参数值
+const stdinInput = "-"
+
+// stdin 标准输入（测试时可替换）
+var stdin io.Reader = os.Stdin
+
+// loadSchemaInput 解析 -s/-t 等参数的值，返回 SQL 文本
+// 支持以下形式：
+//   - "-"：从标准输入读取
+//   - 文件路径：读取文件内容
+//   - 目录：读取目录（含子目录）下所有 .sql 文件
+//   - 通配符（如 schema/*.sql）：读取所有匹配的文件
+//   - mysql://用户名:密码@主机:端口/数据库：通过 information_schema 读取线上数据库的表结构
+//   - 其他：视为 SQL 语句本身
+//
+// 多个文件按路径排序（数字按数值比较，V2 排在 V10 之前）后合并为一个脚本，因此迁移脚本目录会按版本顺序回放
+func loadSchemaInput(value string) (string, error) {
+	if value == stdinInput {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		return string(data), nil
+	}
+	if source.IsMySQLDSN(value) {
+		return loadMySQLSchema(value)
+	}
+	if looksLikeSQL(value) {
+		return value, nil
+	}
+
+	if info, err := os.Stat(value); err == nil {
+		if info.IsDir() {
+			files, err := findSQLFiles(value)
+			if err != nil {
+				return "", err
+			}
+			if len(files) == 0 {
+				return "", fmt.Errorf("目录 %s 中没有 .sql 文件", value)
+			}
+			return readSchemaFiles(files)
+		}
+		return readSchemaFiles([]string{value})
+	}
+
+	if strings.ContainsAny(value, "*?[") {
+		files, err := filepath.Glob(value)
+		if err != nil {
+			return "", fmt.Errorf("无效的通配符 %s: %w", value, err)
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("没有与 %s 匹配的文件", value)
+		}
+		source.SortPaths(files)
+		return readSchemaFiles(files)
+	}
+
+	if source.IsSQLFile(value) || strings.ContainsRune(value, filepath.Separator) {
+		return "", fmt.Errorf("文件不存在: %s", value)
+	}
+	return value, nil
+}
+
+// looksLikeSQL 判断参数值是否为 SQL 语句（而不是路径）
+func looksLikeSQL(value string) bool {
+	return strings.ContainsAny(value, "\n;(")
+}
+
+// findSQLFiles 返回目录（含子目录）下所有 .sql 文件，按路径排序
+func findSQLFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && source.IsSQLFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取目录 %s 失败: %w", dir, err)
+	}
+	source.SortPaths(files)
+	return files, nil
+}
+
+// readSchemaFiles 读取并合并多个 SQL 文件
+func readSchemaFiles(paths []string) (string, error) {
+	files := make([]source.File, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("读取文件 %s 失败: %w", path, err)
+		}
+		files = append(files, source.File{Name: path, Content: string(data)})
+	}
+	return source.JoinFiles(files), nil
+}
+
+// loadMySQLSchema 通过 information_schema 读取线上数据库的表结构，转换为建表语句
+// （用于需要 SQL 文本的场景，比对时使用 loadSchemaSide 直接得到表结构）
+func loadMySQLSchema(dsn string) (string, error) {
+	db, err := readMySQLSchema(dsn)
+	if err != nil {
+		return "", err
+	}
+	return differ.FormatSchema(db, parser.MySQL), nil
+}
+
+// readMySQLSchema 通过 information_schema 读取线上数据库的表结构
+func readMySQLSchema(dsn string) (*parser.DatabaseSchema, error) {
+	db, err := source.ReadMySQL(context.Background(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	if len(db.Tables) == 0 {
+		return nil, fmt.Errorf("数据库中没有表")
+	}
+	return db, nil
+}
+
+// schemaInput 比对的一侧输入：SQL 脚本，或从线上数据库读取的表结构（无需再解析）
+type schemaInput struct {
+	sql    string
+	schema *parser.DatabaseSchema
+}
+
+// sqlInput 以 SQL 脚本作为比对输入
+func sqlInput(sql string) schemaInput {
+	return schemaInput{sql: sql}
+}
+
+// text 返回输入的 SQL 文本（AI 分析等需要原文的场景使用），数据库结构输出为建表语句
+func (in schemaInput) text() string {
+	if in.schema != nil {
+		return differ.FormatSchema(in.schema, parser.MySQL)
+	}
+	return in.sql
+}
+
+// parse 解析输入，从数据库读取的表结构直接返回
+func (in schemaInput) parse(p parser.Parser) (*parser.DatabaseSchema, error) {
+	if in.schema != nil {
+		return in.schema, nil
+	}
+	return parseSchemaScript(p, in.sql)
+}
+
+// loadSchemaSide 读取比对的一侧：mysql:// 连接串直接读取表结构，其余形式同 loadSchemaInput
+func loadSchemaSide(value string) (schemaInput, error) {
+	if source.IsMySQLDSN(value) {
+		db, err := readMySQLSchema(value)
+		return schemaInput{schema: db}, err
+	}
+	sql, err := loadSchemaInput(value)
+	return sqlInput(sql), err
+}
+
+// loadSchemaInputs 解析源和目标输入（两者不能同时从标准输入读取）
+func loadSchemaInputs(sourceArg, targetArg string) (schemaInput, schemaInput, error) {
+	if sourceArg == stdinInput && targetArg == stdinInput {
+		return schemaInput{}, schemaInput{}, fmt.Errorf("-s 和 -t 不能同时从标准输入读取")
+	}
+	source, err := loadSchemaSide(sourceArg)
+	if err != nil {
+		return schemaInput{}, schemaInput{}, fmt.Errorf("读取源结构失败: %w", err)
+	}
+	target, err := loadSchemaSide(targetArg)
+	if err != nil {
+		return schemaInput{}, schemaInput{}, fmt.Errorf("读取目标结构失败: %w", err)
+	}
+	return source, target, nil
+}
+
+// resolveInteractiveInput 处理交互式输入：单行且不是 SQL 时按文件路径、目录、通配符或连接串读取
+func resolveInteractiveInput(input string) (schemaInput, error) {
+	input = strings.TrimSpace(input)
+	if input == stdinInput || looksLikeSQL(input) || strings.ContainsRune(input, '\n') {
+		return sqlInput(input), nil
+	}
+	return loadSchemaSide(input)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// writeFile 在临时目录中写入测试文件
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tableNames 解析脚本并返回其中的表名
+func tableNames(t *testing.T, sql string) []string {
+	t.Helper()
+	db, err := parser.NewParser().ParseScript(sql)
+	if err != nil {
+		t.Fatalf("解析合并后的脚本失败: %v", err)
+	}
+	var names []string
+	for _, table := range db.Tables {
+		names = append(names, table.Name)
+	}
+	return names
+}
+
+func TestLoadSchemaInput(t *testing.T) {
+	dir := t.TempDir()
+	// 末尾不带分号的文件也能与下一个文件正确合并
+	writeFile(t, filepath.Join(dir, "schema", "a_users.sql"), "CREATE TABLE users (id INT)")
+	writeFile(t, filepath.Join(dir, "schema", "b_orders.sql"), "CREATE TABLE orders (id INT);")
+	writeFile(t, filepath.Join(dir, "schema", "nested", "logs.sql"), "CREATE TABLE logs (id INT);")
+	writeFile(t, filepath.Join(dir, "schema", "README.md"), "not sql")
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"SQL 语句", "CREATE TABLE t (id INT)", "t"},
+		{"单个文件", filepath.Join(dir, "schema", "a_users.sql"), "users"},
+		{"目录", filepath.Join(dir, "schema"), "users,orders,logs"},
+		{"通配符", filepath.Join(dir, "schema", "*.sql"), "users,orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := loadSchemaInput(tt.value)
+			if err != nil {
+				t.Fatalf("读取失败: %v", err)
+			}
+			if got := strings.Join(tableNames(t, sql), ","); got != tt.expected {
+				t.Errorf("期望表 %s，实际为 %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadSchemaInputErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "empty", "notes.txt"), "")
+
+	for _, value := range []string{
+		filepath.Join(dir, "missing.sql"),
+		filepath.Join(dir, "*.sql"),
+		filepath.Join(dir, "empty"),
+	} {
+		if _, err := loadSchemaInput(value); err == nil {
+			t.Errorf("期望 %s 返回错误", value)
+		}
+	}
+
+	if _, _, err := loadSchemaInputs(stdinInput, stdinInput); err == nil {
+		t.Error("期望 -s 和 -t 同时使用标准输入时返回错误")
+	}
+}
+
+func TestLoadSchemaInputStdin(t *testing.T) {
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("CREATE TABLE users (id INT);")
+
+	sql, err := loadSchemaInput(stdinInput)
+	if err != nil {
+		t.Fatalf("读取标准输入失败: %v", err)
+	}
+	if got := strings.Join(tableNames(t, sql), ","); got != "users" {
+		t.Errorf("期望表 users，实际为 %s", got)
+	}
+}
+
+func TestSchemaInputKeepsDatabaseSchema(t *testing.T) {
+	// 从数据库读取的结构不经过 输出→解析 的往返，默认值等信息原样保留
+	db := parser.NewDatabaseSchema()
+	db.AddTable(&parser.TableSchema{Name: "users", Columns: []*parser.Column{{Name: "note", Type: "VARCHAR", Length: "10", DefaultValue: "NULL", DefaultQuoted: true}}})
+
+	parsed, err := schemaInput{schema: db}.parse(parser.NewParser())
+	if err != nil || parsed != db {
+		t.Fatalf("期望直接返回读取的结构，实际为 %v（%v）", parsed, err)
+	}
+	if text := (schemaInput{schema: db}).text(); !strings.Contains(text, "DEFAULT 'NULL'") {
+		t.Errorf("输出的建表语句不正确:\n%s", text)
+	}
+}
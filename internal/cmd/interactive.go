@@ -105,7 +105,7 @@ func runCompareMode(cfg *config.Config) error {
 	// 读取源表 SQL
 	color.New(color.FgYellow, color.Bold).Println("📋 请粘贴源表的 CREATE TABLE 语句：")
 	color.New(color.FgWhite).Println("（直接粘贴完整 SQL，粘贴完成后输入 'END' 或连续按两次 Enter）")
-	color.New(color.FgCyan).Println("（提示：建议在文本编辑器中准备好 SQL，然后直接粘贴）")
+	color.New(color.FgCyan).Println("（提示：也可以输入文件路径、目录或通配符，如 schema/*.sql）")
 	fmt.Println()
 
 	sourceSQL, err := readMultilineInput()
@@ -116,14 +116,18 @@ func runCompareMode(cfg *config.Config) error {
 	if strings.TrimSpace(sourceSQL) == "" {
 		return fmt.Errorf("源表 SQL 不能为空")
 	}
+	source, err := resolveInteractiveInput(sourceSQL)
+	if err != nil {
+		return fmt.Errorf("读取源表 SQL 失败: %v", err)
+	}
 
-	successColor.Printf("✓ 已读取 %d 个字符\n", len(sourceSQL))
+	successColor.Printf("✓ 已读取 %d 个字符\n", len(source.text()))
 	fmt.Println()
 
 	// 读取目标表 SQL
 	color.New(color.FgYellow, color.Bold).Println("📋 请粘贴目标表的 CREATE TABLE 语句：")
 	color.New(color.FgWhite).Println("（直接粘贴完整 SQL，粘贴完成后输入 'END' 或连续按两次 Enter）")
-	color.New(color.FgCyan).Println("（提示：建议在文本编辑器中准备好 SQL，然后直接粘贴）")
+	color.New(color.FgCyan).Println("（提示：也可以输入文件路径、目录或通配符，如 schema/*.sql）")
 	fmt.Println()
 
 	targetSQL, err := readMultilineInput()
@@ -134,12 +138,16 @@ func runCompareMode(cfg *config.Config) error {
 	if strings.TrimSpace(targetSQL) == "" {
 		return fmt.Errorf("目标表 SQL 不能为空")
 	}
+	target, err := resolveInteractiveInput(targetSQL)
+	if err != nil {
+		return fmt.Errorf("读取目标表 SQL 失败: %v", err)
+	}
 
-	successColor.Printf("✓ 已读取 %d 个字符\n", len(targetSQL))
+	successColor.Printf("✓ 已读取 %d 个字符\n", len(target.text()))
 	fmt.Println()
 
 	// 调用核心比对逻辑
-	return processComparison(sourceSQL, targetSQL, cfg)
+	return processComparison(source, target, cfg)
 }
 
 // readSingleLineInput 读取单行输入（支持完整的一行文本，包含空格）
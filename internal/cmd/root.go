@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/Bacchusgift/sql-diff/internal/ai"
 	"github.com/Bacchusgift/sql-diff/internal/config"
 	"github.com/Bacchusgift/sql-diff/internal/differ"
 	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"github.com/Bacchusgift/sql-diff/internal/report"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -22,12 +25,19 @@ var (
 	gitCommit = "unknown"
 
 	// 命令行参数
-	sourceSQL   string
-	targetSQL   string
-	enableAI    bool
-	configPath  string
-	outputFile  string
-	interactive bool
+	sourceSQL      string
+	targetSQL      string
+	enableAI       bool
+	configPath     string
+	outputFile     string
+	rollbackOut    string
+	format         string
+	interactive    bool
+	renameHints    []string
+	ignoreOrder    bool
+	compareAutoInc bool
+	dialectName    string
+	verifyDDL      bool
 
 	// 颜色输出
 	successColor = color.New(color.FgGreen, color.Bold)
@@ -72,6 +82,8 @@ var rootCmd = &cobra.Command{
   
   # 4️⃣  命令行模式比对
   sql-diff -s "CREATE TABLE users (id INT)" -t "CREATE TABLE users (id INT, name VARCHAR(100))"
+  sql-diff -s schema/old.sql -t schema/new/
+  mysqldump --no-data db | sql-diff -s - -t "schema/*.sql"
   
   # 5️⃣  启用 AI 分析
   sql-diff -i --ai
@@ -81,14 +93,31 @@ var rootCmd = &cobra.Command{
   sql-diff alter --help`,
 	Version: version, // 设置版本号，支持 --version 和 -v
 	RunE:    run,
+	// 错误信息统一由 Execute 输出，避免重复
+	SilenceErrors: true,
 }
 
 // Execute 执行命令
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
 	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		if exitErr.err != nil {
+			fmt.Fprintln(os.Stderr, exitErr.err)
+		}
+		os.Exit(exitErr.code)
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	if cmd == checkCmd {
+		// check 命令的退出码 1 表示存在差异，参数错误需使用单独的退出码
+		os.Exit(ExitError)
+	}
+	os.Exit(1)
 }
 
 func init() {
@@ -96,12 +125,19 @@ func init() {
 	rootCmd.SetVersionTemplate(`{{.Version}}
 `)
 
-	rootCmd.Flags().StringVarP(&sourceSQL, "source", "s", "", "源表的 CREATE TABLE 语句")
-	rootCmd.Flags().StringVarP(&targetSQL, "target", "t", "", "目标表的 CREATE TABLE 语句")
+	rootCmd.Flags().StringVarP(&sourceSQL, "source", "s", "", "源结构：SQL 语句、文件、目录、通配符、mysql:// 连接串或 -（标准输入）")
+	rootCmd.Flags().StringVarP(&targetSQL, "target", "t", "", "目标结构：SQL 语句、文件、目录、通配符、mysql:// 连接串或 -（标准输入）")
 	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "交互式模式（支持多行粘贴）")
 	rootCmd.Flags().BoolVar(&enableAI, "ai", false, "启用 AI 智能分析")
 	rootCmd.Flags().StringVar(&configPath, "config", ".sql-diff-config.yaml", "配置文件路径")
 	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "", "输出文件路径（默认输出到控制台）")
+	rootCmd.Flags().StringVar(&format, "format", report.FormatText, "输出格式：text、json、yaml")
+	rootCmd.Flags().StringVar(&rollbackOut, "rollback-output", "", "回滚脚本输出文件路径")
+	rootCmd.Flags().BoolVar(&ignoreOrder, "ignore-column-order", false, "忽略列顺序（不生成 AFTER/FIRST）")
+	rootCmd.Flags().BoolVar(&compareAutoInc, "compare-auto-increment", false, "比较表的 AUTO_INCREMENT 计数器（默认忽略）")
+	rootCmd.Flags().StringArrayVar(&renameHints, "rename", nil, "指定列或索引的重命名，格式为 [表名.]原名称=新名称（可重复）")
+	rootCmd.Flags().StringVar(&dialectName, "dialect", "", "SQL 方言：mysql（默认）、postgres、sqlite")
+	rootCmd.Flags().BoolVar(&verifyDDL, "verify", false, verifyUsage)
 
 	// 添加 version 命令（详细版）
 	rootCmd.AddCommand(versionCmd)
@@ -109,6 +145,14 @@ func init() {
 
 // run 执行主逻辑
 func run(cmd *cobra.Command, args []string) error {
+	// 参数解析已完成，之后的错误（如验证失败）不再输出用法说明
+	cmd.SilenceUsage = true
+	switch format {
+	case report.FormatText, report.FormatJSON, report.FormatYAML:
+	default:
+		return fmt.Errorf("不支持的输出格式: %s（可选 text、json、yaml）", format)
+	}
+
 	// 交互式模式
 	if interactive {
 		if err := runInteractive(); err != nil {
@@ -127,22 +171,20 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("缺少必需参数")
 	}
 
-	// 加载配置
-	cfg, err := config.LoadConfig(configPath)
+	// 读取文件、目录、通配符或标准输入
+	source, target, err := loadSchemaInputs(sourceSQL, targetSQL)
 	if err != nil {
-		errorColor.Printf("✗ 加载配置失败: %v\n", err)
+		errorColor.Printf("✗ %v\n", err)
 		return err
 	}
 
-	// 如果命令行指定了 --ai，覆盖配置文件
-	if enableAI {
-		cfg.AI.Enabled = true
+	cfg, err := loadRunConfig()
+	if err != nil {
+		return err
 	}
 
-	// 验证配置
-	if err := cfg.Validate(); err != nil {
-		errorColor.Printf("✗ 配置验证失败: %v\n", err)
-		return err
+	if format != report.FormatText {
+		return processComparison(source, target, cfg)
 	}
 
 	infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -150,202 +192,28 @@ func run(cmd *cobra.Command, args []string) error {
 	infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
-	// 解析源表结构
-	infoColor.Println("📖 正在解析源表结构...")
-	p := parser.NewParser()
-	sourceSchema, err := p.Parse(sourceSQL)
-	if err != nil {
-		errorColor.Printf("✗ 解析源表失败: %v\n", err)
-		return err
-	}
-	successColor.Printf("✓ 源表: %s (%d 列)\n", sourceSchema.Name, len(sourceSchema.Columns))
-	fmt.Println()
+	return processComparison(source, target, cfg)
+}
 
-	// 解析目标表结构
-	infoColor.Println("📖 正在解析目标表结构...")
-	targetSchema, err := p.Parse(targetSQL)
+// loadRunConfig 加载并验证配置（命令行指定 --ai 时覆盖配置文件）
+func loadRunConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
-		errorColor.Printf("✗ 解析目标表失败: %v\n", err)
-		return err
-	}
-	successColor.Printf("✓ 目标表: %s (%d 列)\n", targetSchema.Name, len(targetSchema.Columns))
-	fmt.Println()
-
-	// 比对差异
-	infoColor.Println("🔍 正在比对表结构...")
-	d := differ.NewDiffer(sourceSchema, targetSchema)
-	diff := d.Compare()
-
-	if !diff.HasChanges() {
-		successColor.Println("✓ 两个表结构完全相同，无需修改！")
-		return nil
-	}
-
-	// 显示差异摘要
-	fmt.Println()
-	warnColor.Println("📊 差异摘要:")
-	warnColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Print(diff.Summary())
-	fmt.Println()
-
-	// 生成 DDL
-	infoColor.Println("🔧 生成 DDL 语句...")
-	ddls := diff.GenerateDDL(sourceSchema.Name)
-
-	fmt.Println()
-	successColor.Println("✓ 生成的 DDL 语句:")
-	successColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
-
-	var output strings.Builder
-
-	// 分类显示 DDL 语句
-	addColumns := make([]string, 0)
-	modifyColumns := make([]string, 0)
-	dropColumns := make([]string, 0)
-	addIndexes := make([]string, 0)
-	dropIndexes := make([]string, 0)
-
-	for _, ddl := range ddls {
-		ddlUpper := strings.ToUpper(ddl)
-		if strings.Contains(ddlUpper, "ADD COLUMN") {
-			addColumns = append(addColumns, ddl)
-		} else if strings.Contains(ddlUpper, "MODIFY COLUMN") {
-			modifyColumns = append(modifyColumns, ddl)
-		} else if strings.Contains(ddlUpper, "DROP COLUMN") {
-			dropColumns = append(dropColumns, ddl)
-		} else if strings.Contains(ddlUpper, "ADD INDEX") || strings.Contains(ddlUpper, "ADD UNIQUE") {
-			addIndexes = append(addIndexes, ddl)
-		} else if strings.Contains(ddlUpper, "DROP INDEX") {
-			dropIndexes = append(dropIndexes, ddl)
-		}
-		output.WriteString(ddl + ";\n")
-	}
-
-	// 显示新增列
-	if len(addColumns) > 0 {
-		color.New(color.FgGreen, color.Bold).Printf("➕ 新增列 (%d):\n", len(addColumns))
-		for i, ddl := range addColumns {
-			color.New(color.FgGreen).Printf("  %d. %s;\n", i+1, ddl)
-		}
-		fmt.Println()
-	}
-
-	// 显示修改列
-	if len(modifyColumns) > 0 {
-		color.New(color.FgYellow, color.Bold).Printf("🔄 修改列 (%d):\n", len(modifyColumns))
-		for i, ddl := range modifyColumns {
-			color.New(color.FgYellow).Printf("  %d. %s;\n", i+1, ddl)
-		}
-		fmt.Println()
-	}
-
-	// 显示删除列（注释）
-	if len(dropColumns) > 0 {
-		color.New(color.FgRed, color.Bold).Printf("🗑️  删除列 (%d) [已注释]:\n", len(dropColumns))
-		for i, ddl := range dropColumns {
-			color.New(color.FgRed).Printf("  %d. %s;\n", i+1, ddl)
-		}
-		fmt.Println()
-	}
-
-	// 显示新增索引
-	if len(addIndexes) > 0 {
-		color.New(color.FgCyan, color.Bold).Printf("📇 新增索引 (%d):\n", len(addIndexes))
-		for i, ddl := range addIndexes {
-			color.New(color.FgCyan).Printf("  %d. %s;\n", i+1, ddl)
-		}
-		fmt.Println()
-	}
-
-	// 显示删除索引（注释）
-	if len(dropIndexes) > 0 {
-		color.New(color.FgMagenta, color.Bold).Printf("🗂️  删除索引 (%d) [已注释]:\n", len(dropIndexes))
-		for i, ddl := range dropIndexes {
-			color.New(color.FgMagenta).Printf("  %d. %s;\n", i+1, ddl)
-		}
-		fmt.Println()
-	}
-
-	// 显示完整的可执行 SQL
-	if len(ddls) > 0 {
-		color.New(color.FgWhite, color.Bold).Println("📋 完整执行脚本:")
-		color.New(color.FgWhite, color.Bold).Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		for _, ddl := range ddls {
-			fmt.Println(ddl + ";")
-		}
-		fmt.Println()
+		errorColor.Printf("✗ 加载配置失败: %v\n", err)
+		return nil, err
 	}
 
-	// AI 分析
-	if cfg.AI.Enabled {
-		fmt.Println()
-		infoColor.Println("🤖 正在进行 AI 智能分析...")
-
-		provider, err := ai.NewProvider(&cfg.AI)
-		if err != nil {
-			warnColor.Printf("⚠ AI 初始化失败: %v\n", err)
-		} else {
-			result, err := provider.Analyze(sourceSQL, targetSQL, diff.Summary())
-			if err != nil {
-				warnColor.Printf("⚠ AI 分析失败: %v\n", err)
-			} else {
-				fmt.Println()
-				infoColor.Println("💡 AI 分析结果:")
-				infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-				// 显示摘要
-				if result.Summary != "" {
-					fmt.Println()
-					color.New(color.FgWhite, color.Bold).Println("📊 差异分析:")
-					fmt.Println(result.Summary)
-				}
-
-				// 显示优化建议
-				if len(result.Suggestions) > 0 {
-					fmt.Println()
-					color.New(color.FgGreen, color.Bold).Println("✨ 优化建议:")
-					for i, suggestion := range result.Suggestions {
-						fmt.Printf("  %d. %s\n", i+1, suggestion)
-					}
-				}
-
-				// 显示潜在风险
-				if len(result.Risks) > 0 {
-					fmt.Println()
-					color.New(color.FgRed, color.Bold).Println("⚠️  潜在风险:")
-					for i, risk := range result.Risks {
-						fmt.Printf("  %d. %s\n", i+1, risk)
-					}
-				}
-
-				// 显示最佳实践
-				if len(result.BestPractice) > 0 {
-					fmt.Println()
-					color.New(color.FgBlue, color.Bold).Println("📖 最佳实践:")
-					for i, practice := range result.BestPractice {
-						fmt.Printf("  %d. %s\n", i+1, practice)
-					}
-				}
-			}
-		}
+	// 如果命令行指定了 --ai，覆盖配置文件
+	if enableAI {
+		cfg.AI.Enabled = true
 	}
 
-	// 输出到文件
-	if outputFile != "" {
-		if err := os.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
-			errorColor.Printf("✗ 写入文件失败: %v\n", err)
-			return err
-		}
-		successColor.Printf("✓ DDL 已保存到: %s\n", outputFile)
+	// 验证配置
+	if err := cfg.Validate(); err != nil {
+		errorColor.Printf("✗ 配置验证失败: %v\n", err)
+		return nil, err
 	}
-
-	fmt.Println()
-	successColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	successColor.Println("           完成！")
-	successColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	return nil
+	return cfg, nil
 }
 
 // runInteractive 交互式模式
@@ -449,38 +317,56 @@ func readMultilineInput() (string, error) {
 	return strings.TrimSpace(result), nil
 }
 
+// tableOptionPattern 匹配修改表选项的语句（可能带有回滚警告注释行）
+var tableOptionPattern = regexp.MustCompile(`(?m)^(ALTER TABLE \S+ (CONVERT TO |DEFAULT CHARSET=|[A-Z_]+=|SET TABLESPACE |SET \(|RESET \()|COMMENT ON TABLE )`)
+
+// partitionPattern 匹配分区变更的语句（可能带有警告注释行）
+var partitionPattern = regexp.MustCompile(`(?m)^ALTER TABLE \S+ (PARTITION BY |REMOVE PARTITIONING|(ADD|DROP|REORGANIZE|COALESCE) PARTITION)`)
+
+// checkPattern 匹配新增、修改、删除 CHECK 约束的语句
+var checkPattern = regexp.MustCompile(`(ADD (CONSTRAINT \S+ )?CHECK \(|DROP CHECK |ALTER CHECK )`)
+
 // processComparison 执行 SQL 比对逻辑
-func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
+func processComparison(source, target schemaInput, cfg *config.Config) error {
+	if format != report.FormatText {
+		return processStructuredComparison(source, target, cfg)
+	}
+
 	infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	infoColor.Println("       开始比对")
 	infoColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
+	options, err := diffOptions(cfg)
+	if err != nil {
+		errorColor.Printf("✗ %v\n", err)
+		return err
+	}
+
 	// 解析源表结构
 	infoColor.Println("📖 正在解析源表结构...")
-	p := parser.NewParser()
-	sourceSchema, err := p.Parse(sourceSQL)
+	p := parser.NewParserWithDialect(options.Dialect)
+	sourceDB, err := source.parse(p)
 	if err != nil {
 		errorColor.Printf("✗ 解析源表失败: %v\n", err)
 		return err
 	}
-	successColor.Printf("✓ 源表: %s (%d 列)\n", sourceSchema.Name, len(sourceSchema.Columns))
+	printSchemaInfo("源表", sourceDB)
 	fmt.Println()
 
 	// 解析目标表结构
 	infoColor.Println("📖 正在解析目标表结构...")
-	targetSchema, err := p.Parse(targetSQL)
+	targetDB, err := target.parse(p)
 	if err != nil {
 		errorColor.Printf("✗ 解析目标表失败: %v\n", err)
 		return err
 	}
-	successColor.Printf("✓ 目标表: %s (%d 列)\n", targetSchema.Name, len(targetSchema.Columns))
+	printSchemaInfo("目标表", targetDB)
 	fmt.Println()
 
 	// 比对差异
 	infoColor.Println("🔍 正在比对表结构...")
-	d := differ.NewDiffer(sourceSchema, targetSchema)
-	diff := d.Compare()
+	diff := compareSchemas(sourceDB, targetDB, options)
 
 	if !diff.HasChanges() {
 		successColor.Println("✓ 两个表结构完全相同，无需修改！")
@@ -496,36 +382,110 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 
 	// 生成 DDL
 	infoColor.Println("🔧 生成 DDL 语句...")
-	ddls := diff.GenerateDDL(sourceSchema.Name)
+	ddls := diff.GenerateDDL()
 
 	fmt.Println()
 	successColor.Println("✓ 生成的 DDL 语句:")
 	successColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
-	var output strings.Builder
-
 	// 分类显示 DDL 语句
+	createTables := make([]string, 0)
+	dropTables := make([]string, 0)
 	addColumns := make([]string, 0)
 	modifyColumns := make([]string, 0)
 	dropColumns := make([]string, 0)
 	addIndexes := make([]string, 0)
+	modifyIndexes := make([]string, 0)
 	dropIndexes := make([]string, 0)
-
+	foreignKeys := make([]string, 0)
+	checks := make([]string, 0)
+	partitions := make([]string, 0)
+	dropPartitions := make([]string, 0)
+	primaryKeys := make([]string, 0)
+	renames := make([]string, 0)
+	rebuilds := make([]string, 0)
+	tableOptions := make([]string, 0)
+	others := make([]string, 0)
+
+	rebuilding := false
 	for _, ddl := range ddls {
 		ddlUpper := strings.ToUpper(ddl)
-		if strings.Contains(ddlUpper, "ADD COLUMN") {
+		// SQLite 重建表的语句需要按顺序整体执行，不拆分到各个类别中
+		if strings.Contains(ddlUpper, "PRAGMA FOREIGN_KEYS = OFF") {
+			rebuilding = true
+		}
+		if rebuilding {
+			rebuilds = append(rebuilds, ddl)
+			rebuilding = !strings.Contains(ddlUpper, "PRAGMA FOREIGN_KEYS = ON")
+			continue
+		}
+		if strings.HasPrefix(ddlUpper, "CREATE TABLE") {
+			createTables = append(createTables, ddl)
+		} else if strings.HasPrefix(ddlUpper, "-- DROP TABLE") {
+			dropTables = append(dropTables, ddl)
+		} else if checkPattern.MatchString(ddlUpper) {
+			checks = append(checks, ddl)
+		} else if strings.HasPrefix(ddlUpper, "-- ") && partitionPattern.MatchString(strings.TrimPrefix(ddlUpper, "-- ")) {
+			dropPartitions = append(dropPartitions, ddl)
+		} else if partitionPattern.MatchString(ddlUpper) {
+			partitions = append(partitions, ddl)
+		} else if strings.Contains(ddlUpper, "FOREIGN KEY") {
+			foreignKeys = append(foreignKeys, ddl)
+		} else if strings.Contains(ddlUpper, "RENAME COLUMN") || strings.Contains(ddlUpper, "CHANGE COLUMN") ||
+			strings.Contains(ddlUpper, "RENAME INDEX") || strings.Contains(ddlUpper, " RENAME TO ") {
+			renames = append(renames, ddl)
+		} else if strings.Contains(ddlUpper, "PRIMARY KEY") || strings.Contains(ddlUpper, "_PKEY") {
+			primaryKeys = append(primaryKeys, ddl)
+		} else if strings.Contains(ddlUpper, "DROP CONSTRAINT") {
+			foreignKeys = append(foreignKeys, ddl)
+		} else if tableOptionPattern.MatchString(ddlUpper) {
+			tableOptions = append(tableOptions, ddl)
+		} else if strings.Contains(ddlUpper, "ADD COLUMN") {
 			addColumns = append(addColumns, ddl)
-		} else if strings.Contains(ddlUpper, "MODIFY COLUMN") {
+		} else if strings.Contains(ddlUpper, "MODIFY COLUMN") || strings.Contains(ddlUpper, "ALTER COLUMN") ||
+			strings.HasPrefix(ddlUpper, "COMMENT ON") {
 			modifyColumns = append(modifyColumns, ddl)
 		} else if strings.Contains(ddlUpper, "DROP COLUMN") {
 			dropColumns = append(dropColumns, ddl)
-		} else if strings.Contains(ddlUpper, "ADD INDEX") || strings.Contains(ddlUpper, "ADD UNIQUE") {
+		} else if strings.Contains(ddlUpper, "ALTER INDEX") || (strings.Contains(ddlUpper, "DROP INDEX") && !strings.HasPrefix(ddlUpper, "--")) {
+			modifyIndexes = append(modifyIndexes, ddl)
+		} else if strings.Contains(ddlUpper, "ADD INDEX") || strings.Contains(ddlUpper, "ADD UNIQUE") ||
+			strings.Contains(ddlUpper, "ADD FULLTEXT") || strings.Contains(ddlUpper, "ADD SPATIAL") ||
+			strings.Contains(ddlUpper, "CREATE INDEX") || strings.Contains(ddlUpper, "CREATE UNIQUE INDEX") {
 			addIndexes = append(addIndexes, ddl)
 		} else if strings.Contains(ddlUpper, "DROP INDEX") {
 			dropIndexes = append(dropIndexes, ddl)
+		} else {
+			others = append(others, ddl)
+		}
+	}
+
+	// 显示新建表
+	if len(createTables) > 0 {
+		color.New(color.FgGreen, color.Bold).Printf("🆕 新建表 (%d):\n", len(createTables))
+		for i, ddl := range createTables {
+			color.New(color.FgGreen).Printf("  %d. %s;\n", i+1, strings.ReplaceAll(ddl, "\n", "\n     "))
 		}
-		output.WriteString(ddl + ";\n")
+		fmt.Println()
+	}
+
+	// 显示重建表
+	if len(rebuilds) > 0 {
+		color.New(color.FgYellow, color.Bold).Printf("🧱 重建表 (%d 条语句，需按顺序执行):\n", len(rebuilds))
+		for i, ddl := range rebuilds {
+			color.New(color.FgYellow).Printf("  %d. %s;\n", i+1, strings.ReplaceAll(ddl, "\n", "\n     "))
+		}
+		fmt.Println()
+	}
+
+	// 显示重命名
+	if len(renames) > 0 {
+		color.New(color.FgYellow, color.Bold).Printf("✏️  重命名 (%d):\n", len(renames))
+		for i, ddl := range renames {
+			color.New(color.FgYellow).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
 	}
 
 	// 显示新增列
@@ -546,6 +506,15 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 		fmt.Println()
 	}
 
+	// 显示主键变更
+	if len(primaryKeys) > 0 {
+		color.New(color.FgYellow, color.Bold).Printf("🔑 主键变更 (%d):\n", len(primaryKeys))
+		for i, ddl := range primaryKeys {
+			color.New(color.FgYellow).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
 	// 显示删除列（注释）
 	if len(dropColumns) > 0 {
 		color.New(color.FgRed, color.Bold).Printf("🗑️  删除列 (%d) [已注释]:\n", len(dropColumns))
@@ -564,6 +533,15 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 		fmt.Println()
 	}
 
+	// 显示修改索引
+	if len(modifyIndexes) > 0 {
+		color.New(color.FgCyan, color.Bold).Printf("🔄 修改索引 (%d):\n", len(modifyIndexes))
+		for i, ddl := range modifyIndexes {
+			color.New(color.FgCyan).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
 	// 显示删除索引（注释）
 	if len(dropIndexes) > 0 {
 		color.New(color.FgMagenta, color.Bold).Printf("🗂️  删除索引 (%d) [已注释]:\n", len(dropIndexes))
@@ -573,6 +551,69 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 		fmt.Println()
 	}
 
+	// 显示外键变更
+	if len(foreignKeys) > 0 {
+		color.New(color.FgBlue, color.Bold).Printf("🔗 外键变更 (%d):\n", len(foreignKeys))
+		for i, ddl := range foreignKeys {
+			color.New(color.FgBlue).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
+	// 显示 CHECK 约束变更
+	if len(checks) > 0 {
+		color.New(color.FgBlue, color.Bold).Printf("✅ CHECK 约束 (%d):\n", len(checks))
+		for i, ddl := range checks {
+			color.New(color.FgBlue).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
+	// 显示分区变更
+	if len(partitions) > 0 {
+		color.New(color.FgMagenta, color.Bold).Printf("🧩 分区变更 (%d):\n", len(partitions))
+		for i, ddl := range partitions {
+			color.New(color.FgMagenta).Printf("  %d. %s;\n", i+1, strings.ReplaceAll(ddl, "\n", "\n     "))
+		}
+		fmt.Println()
+	}
+
+	// 显示删除分区（注释）
+	if len(dropPartitions) > 0 {
+		color.New(color.FgRed, color.Bold).Printf("🗑️  删除分区 (%d) [已注释]:\n", len(dropPartitions))
+		for i, ddl := range dropPartitions {
+			color.New(color.FgRed).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
+	// 显示表选项变更
+	if len(tableOptions) > 0 {
+		color.New(color.FgYellow, color.Bold).Printf("⚙️  表选项 (%d):\n", len(tableOptions))
+		for i, ddl := range tableOptions {
+			color.New(color.FgYellow).Printf("  %d. %s;\n", i+1, strings.ReplaceAll(ddl, "\n", "\n     "))
+		}
+		fmt.Println()
+	}
+
+	// 显示删除表（注释）
+	if len(dropTables) > 0 {
+		color.New(color.FgRed, color.Bold).Printf("🗑️  删除表 (%d) [已注释]:\n", len(dropTables))
+		for i, ddl := range dropTables {
+			color.New(color.FgRed).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
+	// 显示其他语句
+	if len(others) > 0 {
+		color.New(color.FgWhite, color.Bold).Printf("🔹 其他 (%d):\n", len(others))
+		for i, ddl := range others {
+			color.New(color.FgWhite).Printf("  %d. %s;\n", i+1, ddl)
+		}
+		fmt.Println()
+	}
+
 	// 显示完整的可执行 SQL
 	if len(ddls) > 0 {
 		color.New(color.FgWhite, color.Bold).Println("📋 完整执行脚本:")
@@ -583,6 +624,18 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 		fmt.Println()
 	}
 
+	// 验证 DDL
+	if verifyDDL {
+		infoColor.Println("🔎 正在验证 DDL...")
+		if err := verifyDiff(sourceDB, targetDB, diff, options); err != nil {
+			// 错误信息已经输出，只设置退出码
+			errorColor.Printf("✗ %v\n", err)
+			return &exitError{code: 1}
+		}
+		successColor.Println("✓ 验证通过：在源结构上执行以上 DDL 后与目标结构一致")
+		fmt.Println()
+	}
+
 	// AI 分析
 	if cfg.AI.Enabled {
 		fmt.Println()
@@ -592,7 +645,7 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 		if err != nil {
 			warnColor.Printf("⚠ AI 初始化失败: %v\n", err)
 		} else {
-			result, err := provider.Analyze(sourceSQL, targetSQL, diff.Summary())
+			result, err := provider.Analyze(source.text(), target.text(), diff.Summary())
 			if err != nil {
 				warnColor.Printf("⚠ AI 分析失败: %v\n", err)
 			} else {
@@ -639,13 +692,22 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 
 	// 输出到文件
 	if outputFile != "" {
-		if err := os.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
+		if err := writeScript(outputFile, ddls); err != nil {
 			errorColor.Printf("✗ 写入文件失败: %v\n", err)
 			return err
 		}
 		successColor.Printf("✓ DDL 已保存到: %s\n", outputFile)
 	}
 
+	// 输出回滚脚本
+	if rollbackOut != "" {
+		if err := writeScript(rollbackOut, diff.GenerateRollbackDDL()); err != nil {
+			errorColor.Printf("✗ 写入回滚脚本失败: %v\n", err)
+			return err
+		}
+		successColor.Printf("✓ 回滚脚本已保存到: %s\n", rollbackOut)
+	}
+
 	fmt.Println()
 	successColor.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	successColor.Println("           完成！")
@@ -653,3 +715,144 @@ func processComparison(sourceSQL, targetSQL string, cfg *config.Config) error {
 
 	return nil
 }
+
+// processStructuredComparison 以 JSON/YAML 格式输出比对结果
+// 标准输出只包含报告本身（不输出进度信息），便于其他程序处理
+func processStructuredComparison(source, target schemaInput, cfg *config.Config) error {
+	options, err := diffOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	p := parser.NewParserWithDialect(options.Dialect)
+	sourceDB, err := source.parse(p)
+	if err != nil {
+		return fmt.Errorf("解析源表失败: %w", err)
+	}
+	targetDB, err := target.parse(p)
+	if err != nil {
+		return fmt.Errorf("解析目标表失败: %w", err)
+	}
+
+	diff := compareSchemas(sourceDB, targetDB, options)
+	if verifyDDL {
+		if err := verifyDiff(sourceDB, targetDB, diff, options); err != nil {
+			return err
+		}
+	}
+
+	r := report.New(diff)
+	if cfg.AI.Enabled && diff.HasChanges() {
+		provider, err := ai.NewProvider(&cfg.AI)
+		if err != nil {
+			r.SetAIAnalysis(nil, err)
+		} else {
+			r.SetAIAnalysis(provider.Analyze(source.text(), target.text(), diff.Summary()))
+		}
+	}
+
+	data, err := r.Marshal(format)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stdout.Write(data); err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		if err := writeScript(outputFile, r.Statements); err != nil {
+			return fmt.Errorf("写入文件失败: %w", err)
+		}
+	}
+	if rollbackOut != "" {
+		if err := writeScript(rollbackOut, r.RollbackStatements); err != nil {
+			return fmt.Errorf("写入回滚脚本失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeScript 将 DDL 语句写入文件（每条语句以分号结尾）
+func writeScript(path string, ddls []string) error {
+	var script strings.Builder
+	for _, ddl := range ddls {
+		script.WriteString(ddl + ";\n")
+	}
+	return os.WriteFile(path, []byte(script.String()), 0644)
+}
+
+// parseSchemaScript 解析包含一条或多条 CREATE TABLE 语句的 SQL
+func parseSchemaScript(p parser.Parser, sql string) (*parser.DatabaseSchema, error) {
+	db, err := p.ParseScript(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(db.Tables) == 0 {
+		return nil, fmt.Errorf("未找到 CREATE TABLE 语句")
+	}
+	return db, nil
+}
+
+// printSchemaInfo 显示解析结果概要
+func printSchemaInfo(label string, db *parser.DatabaseSchema) {
+	if len(db.Tables) == 1 {
+		successColor.Printf("✓ %s: %s (%d 列)\n", label, db.Tables[0].Name, len(db.Tables[0].Columns))
+		return
+	}
+	successColor.Printf("✓ %s: %d 张表\n", label, len(db.Tables))
+}
+
+// diffOptions 根据配置文件和命令行参数生成比对选项
+func diffOptions(cfg *config.Config) (differ.Options, error) {
+	options := differ.DefaultOptions()
+	options.DetectRenames = cfg.Diff.DetectRenames
+	options.IgnoreOrder = cfg.Diff.IgnoreOrder || ignoreOrder
+	options.CompareAutoIncrement = cfg.Diff.CompareAutoIncrement || compareAutoInc
+
+	// 命令行参数优先于配置文件
+	name := cfg.Diff.Dialect
+	if dialectName != "" {
+		name = dialectName
+	}
+	dialect, err := parser.ParseDialect(name)
+	if err != nil {
+		return options, err
+	}
+	options.Dialect = dialect
+
+	hints := append(append([]string{}, cfg.Diff.Renames...), renameHints...)
+	for _, h := range hints {
+		hint, err := differ.ParseRenameHint(h)
+		if err != nil {
+			return options, err
+		}
+		options.RenameHints = append(options.RenameHints, hint)
+	}
+	return options, nil
+}
+
+// verifyUsage --verify 参数的说明
+const verifyUsage = "验证生成的 DDL：在源结构上执行后与目标结构比对，仍有差异时报错"
+
+// verifyDiff 验证生成的 DDL 能将源结构变为目标结构
+// 单表比对时两张表可以不同名（DDL 使用源表名），验证前将目标表视为与源表同名
+func verifyDiff(source, target *parser.DatabaseSchema, diff *differ.DatabaseDiff, options differ.Options) error {
+	if len(source.Tables) == 1 && len(target.Tables) == 1 && source.Tables[0].Name != target.Tables[0].Name {
+		target = target.Clone()
+		target.Tables[0].Name = source.Tables[0].Name
+	}
+	return differ.NewDatabaseDifferWithOptions(source, target, options).Verify(diff)
+}
+
+// compareSchemas 比对源和目标结构
+// 两侧各只有一张表时按同一张表比对（兼容单表用法，表名可以不同），否则按表名匹配整个数据库
+func compareSchemas(source, target *parser.DatabaseSchema, options differ.Options) *differ.DatabaseDiff {
+	if len(source.Tables) == 1 && len(target.Tables) == 1 {
+		diff := &differ.DatabaseDiff{Dialect: options.Dialect}
+		if tableDiff := differ.NewTableDiffWithOptions(source.Tables[0], target.Tables[0], options); tableDiff.Diff.HasChanges() {
+			diff.ModifiedTables = append(diff.ModifiedTables, tableDiff)
+		}
+		return diff
+	}
+	return differ.NewDatabaseDifferWithOptions(source, target, options).Compare()
+}
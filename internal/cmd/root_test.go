@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestVerifyDiff(t *testing.T) {
+	p := parser.NewParser()
+	source, err := p.ParseScript("CREATE TABLE users_old (id INT PRIMARY KEY, name VARCHAR(20) COMMENT 'it''s');")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	target, err := p.ParseScript("CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50) DEFAULT 'C:\\\\', email VARCHAR(100));")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	// 单表比对时表名不同也能验证
+	options := differ.DefaultOptions()
+	diff := compareSchemas(source, target, options)
+	if err := verifyDiff(source, target, diff, options); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+	if target.Tables[0].Name != "users" {
+		t.Errorf("验证不应修改目标结构")
+	}
+
+	diff.ModifiedTables[0].Diff.AddedColumns = nil
+	var verifyErr *differ.VerifyError
+	if err := verifyDiff(source, target, diff, options); !errors.As(err, &verifyErr) {
+		t.Errorf("DDL 不完整时应验证失败，实际: %v", err)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	AI AIConfig `yaml:"ai"`
+	AI   AIConfig   `yaml:"ai"`
+	Diff DiffConfig `yaml:"diff"`
 }
 
 // AIConfig AI 相关配置
@@ -23,6 +24,15 @@ type AIConfig struct {
 	Timeout     int    `yaml:"timeout"`      // 请求超时时间（秒）
 }
 
+// DiffConfig 比对相关配置
+type DiffConfig struct {
+	DetectRenames        bool     `yaml:"detect_renames"`         // 是否自动识别列、索引的重命名
+	Renames              []string `yaml:"renames"`                // 重命名提示，格式为 [表名.]原名称=新名称
+	IgnoreOrder          bool     `yaml:"ignore_column_order"`    // 是否忽略列顺序
+	CompareAutoIncrement bool     `yaml:"compare_auto_increment"` // 是否比较 AUTO_INCREMENT 计数器
+	Dialect              string   `yaml:"dialect"`                // SQL 方言：mysql（默认）、postgres、sqlite
+}
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
@@ -33,6 +43,9 @@ func DefaultConfig() *Config {
 			Model:       "deepseek-chat",
 			Timeout:     30,
 		},
+		Diff: DiffConfig{
+			DetectRenames: true,
+		},
 	}
 }
 
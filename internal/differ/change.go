@@ -0,0 +1,393 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// ChangeKind 变更类型
+type ChangeKind string
+
+const (
+	ChangeCreateTable         ChangeKind = "create_table"
+	ChangeDropTable           ChangeKind = "drop_table"
+	ChangeAddColumn           ChangeKind = "add_column"
+	ChangeDropColumn          ChangeKind = "drop_column"
+	ChangeModifyColumn        ChangeKind = "modify_column"
+	ChangeRenameColumn        ChangeKind = "rename_column"
+	ChangeAddIndex            ChangeKind = "add_index"
+	ChangeDropIndex           ChangeKind = "drop_index"
+	ChangeModifyIndex         ChangeKind = "modify_index"
+	ChangeRenameIndex         ChangeKind = "rename_index"
+	ChangeAddPrimaryKey       ChangeKind = "add_primary_key"
+	ChangeDropPrimaryKey      ChangeKind = "drop_primary_key"
+	ChangeModifyPrimaryKey    ChangeKind = "modify_primary_key"
+	ChangeAddForeignKey       ChangeKind = "add_foreign_key"
+	ChangeDropForeignKey      ChangeKind = "drop_foreign_key"
+	ChangeModifyForeignKey    ChangeKind = "modify_foreign_key"
+	ChangeAddCheck            ChangeKind = "add_check"
+	ChangeDropCheck           ChangeKind = "drop_check"
+	ChangeModifyCheck         ChangeKind = "modify_check"
+	ChangeModifyTableOptions  ChangeKind = "modify_table_options"
+	ChangeAddPartition        ChangeKind = "add_partition"
+	ChangeDropPartition       ChangeKind = "drop_partition"
+	ChangeReorganizePartition ChangeKind = "reorganize_partition"
+	ChangeModifyPartitioning  ChangeKind = "modify_partitioning"
+)
+
+// RiskLevel 变更的风险等级
+type RiskLevel int
+
+const (
+	RiskNone        RiskLevel = iota // 没有变更
+	RiskSafe                         // 不会丢失数据
+	RiskWarning                      // 可能执行失败、截断或转换数据
+	RiskDestructive                  // 会丢失数据
+)
+
+// String 返回风险等级名称
+func (r RiskLevel) String() string {
+	switch r {
+	case RiskNone:
+		return "none"
+	case RiskSafe:
+		return "safe"
+	case RiskWarning:
+		return "warning"
+	case RiskDestructive:
+		return "destructive"
+	default:
+		return "unknown"
+	}
+}
+
+// Change 单项结构变更（用于结构化输出和风险评估）
+type Change struct {
+	Kind      ChangeKind
+	Table     string
+	Name      string
+	From      string      // 重命名前的名称
+	Details   []string    // 变更描述
+	Risk      RiskLevel   // 风险等级
+	Source    interface{} // 变更前的定义：*parser.Column、*parser.Index、*parser.Constraint、[]string（主键）、*parser.TableSchema、*parser.Partitioning 或 []*parser.Partition（表选项变更时为空）
+	Target    interface{} // 变更后的定义，类型同 Source
+	SourceSQL string      // 变更前的定义（SQL 片段）
+	TargetSQL string      // 变更后的定义（SQL 片段）
+}
+
+// Changes 将表内差异展开为变更列表
+func (d *Diff) Changes(table string) []*Change {
+	changes := make([]*Change, 0)
+	dialect := d.dialect()
+	add := func(c *Change) {
+		c.Table = table
+		changes = append(changes, c)
+	}
+
+	for _, rename := range d.RenamedColumns {
+		add(&Change{
+			Kind:      ChangeRenameColumn,
+			Name:      rename.To,
+			From:      rename.From,
+			Details:   rename.Changes,
+			Risk:      columnChangeRisk(rename.Source, rename.Target),
+			Source:    rename.Source,
+			Target:    rename.Target,
+			SourceSQL: dialect.columnDefinition(rename.Source),
+			TargetSQL: dialect.columnDefinition(rename.Target),
+		})
+	}
+	for _, col := range d.AddedColumns {
+		add(&Change{
+			Kind:      ChangeAddColumn,
+			Name:      col.Name,
+			Risk:      RiskSafe,
+			Target:    col,
+			TargetSQL: dialect.columnDefinition(col),
+		})
+	}
+	for _, colDiff := range d.ModifiedColumns {
+		add(&Change{
+			Kind:      ChangeModifyColumn,
+			Name:      colDiff.Name,
+			Details:   colDiff.Changes,
+			Risk:      columnChangeRisk(colDiff.Source, colDiff.Target),
+			Source:    colDiff.Source,
+			Target:    colDiff.Target,
+			SourceSQL: dialect.columnDefinition(colDiff.Source),
+			TargetSQL: dialect.columnDefinition(colDiff.Target),
+		})
+	}
+	for _, col := range d.RemovedColumns {
+		add(&Change{
+			Kind:      ChangeDropColumn,
+			Name:      col.Name,
+			Risk:      RiskDestructive,
+			Source:    col,
+			SourceSQL: dialect.columnDefinition(col),
+		})
+	}
+
+	if pk := d.PrimaryKey; pk != nil {
+		c := &Change{Kind: ChangeModifyPrimaryKey, Name: "PRIMARY", Risk: RiskWarning, Source: pk.Source, Target: pk.Target}
+		switch {
+		case len(pk.Source) == 0:
+			c.Kind = ChangeAddPrimaryKey
+		case len(pk.Target) == 0:
+			c.Kind = ChangeDropPrimaryKey
+			c.Risk = RiskSafe
+		}
+		if len(pk.Source) > 0 {
+			c.SourceSQL = fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pk.Source, ", "))
+		}
+		if len(pk.Target) > 0 {
+			c.TargetSQL = fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pk.Target, ", "))
+		}
+		add(c)
+	}
+
+	for _, rename := range d.RenamedIndexes {
+		add(&Change{
+			Kind:      ChangeRenameIndex,
+			Name:      rename.To,
+			From:      rename.From,
+			Risk:      RiskSafe,
+			Source:    rename.Source,
+			Target:    rename.Target,
+			SourceSQL: dialect.indexDefinition(table, rename.Source),
+			TargetSQL: dialect.indexDefinition(table, rename.Target),
+		})
+	}
+	for _, idx := range d.AddedIndexes {
+		add(&Change{
+			Kind:      ChangeAddIndex,
+			Name:      idx.Name,
+			Risk:      indexRisk(idx),
+			Target:    idx,
+			TargetSQL: dialect.indexDefinition(table, idx),
+		})
+	}
+	for _, idxDiff := range d.ModifiedIndexes {
+		add(&Change{
+			Kind:      ChangeModifyIndex,
+			Name:      idxDiff.Name,
+			Details:   idxDiff.Changes,
+			Risk:      indexRisk(idxDiff.Target),
+			Source:    idxDiff.Source,
+			Target:    idxDiff.Target,
+			SourceSQL: dialect.indexDefinition(table, idxDiff.Source),
+			TargetSQL: dialect.indexDefinition(table, idxDiff.Target),
+		})
+	}
+	for _, idx := range d.RemovedIndexes {
+		add(&Change{
+			Kind:      ChangeDropIndex,
+			Name:      idx.Name,
+			Risk:      RiskSafe,
+			Source:    idx,
+			SourceSQL: dialect.indexDefinition(table, idx),
+		})
+	}
+
+	for _, fk := range d.AddedForeignKeys {
+		add(&Change{
+			Kind:      ChangeAddForeignKey,
+			Name:      fk.Name,
+			Risk:      RiskWarning,
+			Target:    fk,
+			TargetSQL: dialect.foreignKeyDefinition(fk),
+		})
+	}
+	for _, fkDiff := range d.ModifiedForeignKeys {
+		add(&Change{
+			Kind:      ChangeModifyForeignKey,
+			Name:      fkDiff.Name,
+			Details:   fkDiff.Changes,
+			Risk:      RiskWarning,
+			Source:    fkDiff.Source,
+			Target:    fkDiff.Target,
+			SourceSQL: dialect.foreignKeyDefinition(fkDiff.Source),
+			TargetSQL: dialect.foreignKeyDefinition(fkDiff.Target),
+		})
+	}
+	for _, fk := range d.RemovedForeignKeys {
+		add(&Change{
+			Kind:      ChangeDropForeignKey,
+			Name:      fk.Name,
+			Risk:      RiskSafe,
+			Source:    fk,
+			SourceSQL: dialect.foreignKeyDefinition(fk),
+		})
+	}
+
+	for _, check := range d.AddedChecks {
+		add(&Change{
+			Kind:      ChangeAddCheck,
+			Name:      check.Name,
+			Risk:      checkRisk(check),
+			Target:    check,
+			TargetSQL: dialect.checkDefinition(check),
+		})
+	}
+	for _, checkDiff := range d.ModifiedChecks {
+		add(&Change{
+			Kind:      ChangeModifyCheck,
+			Name:      checkDiff.Name,
+			Details:   checkDiff.Changes,
+			Risk:      checkRisk(checkDiff.Target),
+			Source:    checkDiff.Source,
+			Target:    checkDiff.Target,
+			SourceSQL: dialect.checkDefinition(checkDiff.Source),
+			TargetSQL: dialect.checkDefinition(checkDiff.Target),
+		})
+	}
+	for _, check := range d.RemovedChecks {
+		add(&Change{
+			Kind:      ChangeDropCheck,
+			Name:      check.Name,
+			Risk:      RiskSafe,
+			Source:    check,
+			SourceSQL: dialect.checkDefinition(check),
+		})
+	}
+
+	if len(d.TableOptions) > 0 {
+		c := &Change{
+			Kind:      ChangeModifyTableOptions,
+			Name:      table,
+			Risk:      d.tableOptionsRisk(),
+			SourceSQL: formatTableOptionDiffs(d.TableOptions, false),
+			TargetSQL: formatTableOptionDiffs(d.TableOptions, true),
+		}
+		for _, option := range d.TableOptions {
+			c.Details = append(c.Details, option.Change)
+		}
+		add(c)
+	}
+
+	if p := d.Partitions; p != nil {
+		if p.Repartition || p.Count != 0 {
+			c := &Change{Kind: ChangeModifyPartitioning, Name: table, Details: p.Changes, Risk: RiskWarning}
+			if p.Source != nil {
+				c.Source, c.SourceSQL = p.Source, formatPartitioning(p.Source)
+			}
+			if p.Target != nil {
+				c.Target, c.TargetSQL = p.Target, formatPartitioning(p.Target)
+			}
+			add(c)
+		}
+		for _, partition := range p.Removed {
+			add(&Change{
+				Kind:      ChangeDropPartition,
+				Name:      partition.Name,
+				Risk:      RiskDestructive,
+				Source:    []*parser.Partition{partition},
+				SourceSQL: formatPartition(partition),
+			})
+		}
+		for _, r := range p.Reorganized {
+			add(&Change{
+				Kind:      ChangeReorganizePartition,
+				Name:      partitionNames(r.Source),
+				Details:   []string{fmt.Sprintf("重组为 %s", partitionNames(r.Target))},
+				Risk:      RiskWarning,
+				Source:    r.Source,
+				Target:    r.Target,
+				SourceSQL: formatPartitionList(r.Source),
+				TargetSQL: formatPartitionList(r.Target),
+			})
+		}
+		for _, partition := range p.Added {
+			add(&Change{
+				Kind:      ChangeAddPartition,
+				Name:      partition.Name,
+				Risk:      RiskSafe,
+				Target:    []*parser.Partition{partition},
+				TargetSQL: formatPartition(partition),
+			})
+		}
+	}
+
+	return changes
+}
+
+// Risk 返回表内差异的最高风险等级
+func (d *Diff) Risk() RiskLevel {
+	return maxRisk(d.Changes(""))
+}
+
+// Changes 将数据库差异展开为变更列表
+func (d *DatabaseDiff) Changes() []*Change {
+	changes := make([]*Change, 0)
+	dialect := dialectFor(d.Dialect)
+
+	for _, table := range d.AddedTables {
+		changes = append(changes, &Change{
+			Kind:      ChangeCreateTable,
+			Table:     table.Name,
+			Name:      table.Name,
+			Risk:      RiskSafe,
+			Target:    table,
+			TargetSQL: strings.Join(dialect.createTable(table), ";\n"),
+		})
+	}
+	for _, tableDiff := range d.ModifiedTables {
+		changes = append(changes, tableDiff.Diff.Changes(tableDiff.Name)...)
+	}
+	for _, table := range d.RemovedTables {
+		changes = append(changes, &Change{
+			Kind:      ChangeDropTable,
+			Table:     table.Name,
+			Name:      table.Name,
+			Risk:      RiskDestructive,
+			Source:    table,
+			SourceSQL: strings.Join(dialect.createTable(table), ";\n"),
+		})
+	}
+
+	return changes
+}
+
+// Risk 返回数据库差异的最高风险等级
+func (d *DatabaseDiff) Risk() RiskLevel {
+	return maxRisk(d.Changes())
+}
+
+// maxRisk 返回变更列表中的最高风险等级
+func maxRisk(changes []*Change) RiskLevel {
+	risk := RiskNone
+	for _, c := range changes {
+		if c.Risk > risk {
+			risk = c.Risk
+		}
+	}
+	return risk
+}
+
+// columnChangeRisk 评估列定义修改的风险
+// 类型、长度、字符集、排序规则变化可能截断、转换数据或引发唯一性冲突（ENUM / SET 在末尾追加成员除外），
+// 新增 NOT NULL 约束在存在 NULL 值时会失败，修改 SRID 在存在其他坐标系的数据时会失败；
+// 普通列改为生成列会用表达式的计算结果替换已有数据，修改生成列可能因计算结果不满足约束而失败
+func columnChangeRisk(source, target *parser.Column) RiskLevel {
+	if source.Generated == "" && target.Generated != "" {
+		return RiskDestructive
+	}
+	if source.Generated != target.Generated || source.Stored != target.Stored ||
+		lossyTypeChange(source, target) || source.Unsigned != target.Unsigned ||
+		(target.NotNull && !source.NotNull) ||
+		!strings.EqualFold(source.Charset, target.Charset) || !strings.EqualFold(source.Collation, target.Collation) ||
+		source.Binary != target.Binary || source.SRID != target.SRID {
+		return RiskWarning
+	}
+	return RiskSafe
+}
+
+// indexRisk 评估创建索引的风险（唯一索引在存在重复数据时会失败）
+func indexRisk(idx *parser.Index) RiskLevel {
+	if idx.Type == "UNIQUE" {
+		return RiskWarning
+	}
+	return RiskSafe
+}
@@ -0,0 +1,101 @@
+package differ
+
+import "testing"
+
+func TestChangeRisk(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		target   string
+		expected RiskLevel
+	}{
+		{
+			name:     "无变更",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY)`,
+			expected: RiskNone,
+		},
+		{
+			name:     "新增列和普通索引",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a INT, INDEX idx_a (a))`,
+			expected: RiskSafe,
+		},
+		{
+			name:     "只修改注释",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a INT COMMENT 'x')`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a INT COMMENT 'y')`,
+			expected: RiskSafe,
+		},
+		{
+			name:     "修改列类型",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a INT)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a BIGINT)`,
+			expected: RiskWarning,
+		},
+		{
+			name:     "ENUM 末尾追加成员",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a ENUM('x','y'))`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a ENUM('x','y','z'))`,
+			expected: RiskSafe,
+		},
+		{
+			name:     "ENUM 成员重排",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a ENUM('x','y'))`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a ENUM('y','x'))`,
+			expected: RiskWarning,
+		},
+		{
+			name:     "SET 删除成员",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a SET('x','y'))`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a SET('x'))`,
+			expected: RiskWarning,
+		},
+		{
+			name:     "新增 NOT NULL",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a INT)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a INT NOT NULL)`,
+			expected: RiskWarning,
+		},
+		{
+			name:     "新增唯一索引",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a INT)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a INT, UNIQUE KEY uk_a (a))`,
+			expected: RiskWarning,
+		},
+		{
+			name:     "删除列",
+			source:   `CREATE TABLE t (id INT PRIMARY KEY, a INT, b INT)`,
+			target:   `CREATE TABLE t (id INT PRIMARY KEY, a BIGINT)`,
+			expected: RiskDestructive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := compareSQL(t, tt.source, tt.target, DefaultOptions())
+			if risk := diff.Risk(); risk != tt.expected {
+				t.Errorf("期望风险等级为 %s，实际为 %s", tt.expected, risk)
+			}
+		})
+	}
+}
+
+func TestChangesRename(t *testing.T) {
+	diff := compareSQL(t,
+		`CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(50))`,
+		`CREATE TABLE t (id INT PRIMARY KEY, full_name VARCHAR(50))`,
+		DefaultOptions())
+
+	changes := diff.Changes("t")
+	if len(changes) != 1 {
+		t.Fatalf("期望 1 项变更，实际为 %d", len(changes))
+	}
+	c := changes[0]
+	if c.Kind != ChangeRenameColumn || c.From != "name" || c.Name != "full_name" || c.Table != "t" {
+		t.Errorf("重命名变更不正确: %+v", c)
+	}
+	if c.Risk != RiskSafe {
+		t.Errorf("期望重命名的风险等级为 safe，实际为 %s", c.Risk)
+	}
+}
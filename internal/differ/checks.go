@@ -0,0 +1,139 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// EnforcementOnly 判断 CHECK 约束是否仅强制检查状态发生变化（MySQL 可直接 ALTER CHECK，无需重建）
+func (d *ConstraintDiff) EnforcementOnly() bool {
+	return len(d.Changes) == 1 && d.Source.NotEnforced != d.Target.NotEnforced
+}
+
+// compareChecks 比对 CHECK 约束
+// 自动生成的名称（或 SQLite 中没有名称）会随表名、约束的序号变化，因此依次按以下规则匹配：
+//  1. 显式指定的约束名相同
+//  2. 至少一方为自动生成的名称，且规范化后的表达式相同
+//  3. 剩余的自动生成的名称相同（表达式已修改）
+func (d *Differ) compareChecks(diff *Diff) {
+	dialect := d.options.Dialect
+	sourceChecks := d.source.Checks()
+	targetChecks := d.target.Checks()
+
+	matched := make(map[*parser.Constraint]*parser.Constraint)
+	used := make(map[*parser.Constraint]bool)
+	match := func(same func(source, target *parser.Constraint) bool) {
+		for _, target := range targetChecks {
+			if matched[target] != nil {
+				continue
+			}
+			for _, source := range sourceChecks {
+				if !used[source] && same(source, target) {
+					matched[target] = source
+					used[source] = true
+					break
+				}
+			}
+		}
+	}
+	sameName := func(source, target *parser.Constraint) bool {
+		return target.Name != "" && strings.EqualFold(source.Name, target.Name)
+	}
+
+	match(func(source, target *parser.Constraint) bool {
+		return sameName(source, target) && !isAutoCheckName(d.target.Name, target.Name)
+	})
+	match(func(source, target *parser.Constraint) bool {
+		return (isAutoCheckName(d.source.Name, source.Name) || isAutoCheckName(d.target.Name, target.Name)) &&
+			parser.NormalizeExpression(source.Definition, dialect) == parser.NormalizeExpression(target.Definition, dialect)
+	})
+	match(sameName)
+
+	for _, target := range targetChecks {
+		source := matched[target]
+		if source == nil {
+			diff.AddedChecks = append(diff.AddedChecks, target)
+			continue
+		}
+		if changes := compareCheck(source, target, dialect); len(changes) > 0 {
+			diff.ModifiedChecks = append(diff.ModifiedChecks, &ConstraintDiff{
+				Name:    target.Name,
+				Source:  source,
+				Target:  target,
+				Changes: changes,
+			})
+		}
+	}
+
+	for _, source := range sourceChecks {
+		if !used[source] {
+			diff.RemovedChecks = append(diff.RemovedChecks, source)
+		}
+	}
+}
+
+// compareCheck 比较两个 CHECK 约束的差异（表达式按规范化后的形式比较）
+func compareCheck(source, target *parser.Constraint, dialect parser.Dialect) []string {
+	changes := make([]string, 0)
+
+	if parser.NormalizeExpression(source.Definition, dialect) != parser.NormalizeExpression(target.Definition, dialect) {
+		changes = append(changes, fmt.Sprintf("表达式从 (%s) 改为 (%s)", source.Definition, target.Definition))
+	}
+
+	if source.NotEnforced != target.NotEnforced {
+		if target.NotEnforced {
+			changes = append(changes, "改为不强制检查 (NOT ENFORCED)")
+		} else {
+			changes = append(changes, "改为强制检查 (ENFORCED)")
+		}
+	}
+
+	return changes
+}
+
+// isAutoCheckName 判断 CHECK 约束名是否为数据库自动生成的名称
+// MySQL：<表名>_chk_<序号>；PostgreSQL：<表名>_[<列名>_]check[序号]；SQLite：没有名称
+func isAutoCheckName(table, name string) bool {
+	if name == "" {
+		return true
+	}
+	lower, prefix := strings.ToLower(name), strings.ToLower(table)+"_"
+	if !strings.HasPrefix(lower, prefix) {
+		return false
+	}
+	rest := lower[len(prefix):]
+	if strings.HasPrefix(rest, "chk_") && strings.Trim(rest[len("chk_"):], "0123456789") == "" {
+		return true
+	}
+	return strings.HasSuffix(strings.TrimRight(rest, "0123456789"), "check")
+}
+
+// checkRisk 评估新增或修改 CHECK 约束的风险（已有数据不满足约束时会失败；NOT ENFORCED 的约束不检查已有数据）
+func checkRisk(check *parser.Constraint) RiskLevel {
+	if check.NotEnforced {
+		return RiskSafe
+	}
+	return RiskWarning
+}
+
+// formatCheck 以 CONSTRAINT name CHECK (expr) 形式格式化 CHECK 约束（没有名称时省略 CONSTRAINT 子句）
+func formatCheck(check *parser.Constraint, quote func(string) string) string {
+	def := fmt.Sprintf("CHECK (%s)", check.Definition)
+	if check.Name != "" {
+		def = fmt.Sprintf("CONSTRAINT %s %s", quote(check.Name), def)
+	}
+	if check.NotEnforced {
+		def += " NOT ENFORCED"
+	}
+	return def
+}
+
+// orUnnamed 约束没有名称时显示为“（未命名）”
+func orUnnamed(name string) string {
+	if name == "" {
+		return "（未命名）"
+	}
+	return name
+}
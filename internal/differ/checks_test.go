@@ -0,0 +1,120 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestDiffChecks(t *testing.T) {
+	sourceSQL := `CREATE TABLE orders (
+		price INT,
+		qty INT,
+		CONSTRAINT qty_positive CHECK (qty > 0),
+		CONSTRAINT qty_small CHECK (qty < 100),
+		CONSTRAINT legacy CHECK (price < 1000),
+		CONSTRAINT orders_chk_3 CHECK (price > 0)
+	)`
+	// 自动命名的约束序号不同但表达式相同（MySQL 保存时会改写表达式），视为同一约束
+	targetSQL := "CREATE TABLE orders (\n" +
+		"		price INT CHECK ((`price` > 0)),\n" +
+		"		qty INT,\n" +
+		"		CONSTRAINT qty_positive CHECK (qty >= 0),\n" +
+		"		CONSTRAINT qty_small CHECK (qty < 100) NOT ENFORCED,\n" +
+		"		CHECK (price * qty < 10000)\n" +
+		"	)"
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	expected := []string{
+		"ALTER TABLE orders DROP CHECK legacy",
+		"ALTER TABLE orders ADD CONSTRAINT orders_chk_2 CHECK (price * qty < 10000)",
+		"ALTER TABLE orders DROP CHECK qty_positive, ADD CONSTRAINT qty_positive CHECK (qty >= 0)",
+		"ALTER TABLE orders ALTER CHECK qty_small NOT ENFORCED",
+	}
+	if ddls := diff.GenerateDDL("orders"); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+	if risk := diff.Risk(); risk != RiskWarning {
+		t.Errorf("新增 CHECK 约束的风险应为 warning，实际为 %s", risk)
+	}
+
+	expectedRollback := []string{
+		"ALTER TABLE orders DROP CHECK orders_chk_2",
+		"ALTER TABLE orders ADD CONSTRAINT legacy CHECK (price < 1000)",
+		"ALTER TABLE orders DROP CHECK qty_positive, ADD CONSTRAINT qty_positive CHECK (qty > 0)",
+		"ALTER TABLE orders ALTER CHECK qty_small ENFORCED",
+	}
+	if ddls := diff.GenerateRollbackDDL("orders"); strings.Join(ddls, "\n") != strings.Join(expectedRollback, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	kinds := make(map[ChangeKind]int)
+	for _, c := range diff.Changes("orders") {
+		kinds[c.Kind]++
+	}
+	if kinds[ChangeAddCheck] != 1 || kinds[ChangeModifyCheck] != 2 || kinds[ChangeDropCheck] != 1 {
+		t.Errorf("变更类型不正确: %v", kinds)
+	}
+}
+
+func TestDiffChecksUnchanged(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a INT CHECK (a > 0), b INT, CONSTRAINT b_check CHECK (b IN ('x', 'y')))`
+	targetSQL := "CREATE TABLE `t` (`a` int, `b` int, CONSTRAINT `t_chk_1` CHECK ((`a` > 0)), CONSTRAINT `b_check` CHECK ((`b` in (_utf8mb4'x',_utf8mb4'y'))))"
+
+	if diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions()); diff.HasChanges() {
+		t.Errorf("不应有差异:\n%s", diff.Summary())
+	}
+}
+
+func TestFormatCreateTableChecks(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a INT CHECK (a > 0), CONSTRAINT big CHECK (a < 100) NOT ENFORCED)`
+	diff := compareSQL(t, sourceSQL, sourceSQL, DefaultOptions())
+
+	ddl := FormatCreateTable(diff.source)
+	for _, def := range []string{"CONSTRAINT t_chk_1 CHECK (a > 0)", "CONSTRAINT big CHECK (a < 100) NOT ENFORCED"} {
+		if !strings.Contains(ddl, def) {
+			t.Errorf("缺少约束定义 %q:\n%s", def, ddl)
+		}
+	}
+}
+
+func TestPostgresChecks(t *testing.T) {
+	sourceSQL := `CREATE TABLE orders (price integer CHECK (price > 0), qty integer);`
+	targetSQL := `CREATE TABLE orders (price integer CHECK (price >= 0), qty integer, CHECK (qty < price));`
+
+	expected := []string{
+		`ALTER TABLE orders ADD CONSTRAINT orders_check CHECK (qty < price)`,
+		`ALTER TABLE orders DROP CONSTRAINT orders_price_check, ADD CONSTRAINT orders_price_check CHECK (price >= 0)`,
+	}
+	if ddls := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL).GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestSQLiteChecksRebuild(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, price INTEGER CHECK (price > 0), qty INTEGER);`
+	targetSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, price INTEGER CHECK (price > 0), qty INTEGER, CONSTRAINT qty_small CHECK (qty < 100));`
+
+	diff := compareDialect(t, parser.SQLite, sourceSQL, targetSQL)
+	ddls := diff.GenerateDDL()
+	if !strings.Contains(strings.Join(ddls, "\n"), "CONSTRAINT qty_small CHECK (qty < 100)") {
+		t.Fatalf("新增 CHECK 约束应重建表:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	db := openSQLite(t, sourceSQL)
+	execSQLite(t, db, []string{"INSERT INTO t (price, qty) VALUES (1, 10)"})
+	execSQLite(t, db, ddls)
+	if _, err := db.Exec("INSERT INTO t (price, qty) VALUES (1, 200)"); err == nil {
+		t.Error("重建后的 CHECK 约束没有生效")
+	}
+	if migrated := compareDialect(t, parser.SQLite, dumpSQLite(t, db), targetSQL); migrated.HasChanges() {
+		t.Errorf("迁移后的结构与目标不一致:\n%s", strings.Join(migrated.GenerateDDL(), "\n"))
+	}
+
+	execSQLite(t, db, diff.GenerateRollbackDDL())
+	if rolledBack := compareDialect(t, parser.SQLite, dumpSQLite(t, db), sourceSQL); rolledBack.HasChanges() {
+		t.Errorf("回滚后的结构与源不一致:\n%s", strings.Join(rolledBack.GenerateDDL(), "\n"))
+	}
+}
@@ -0,0 +1,352 @@
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// DatabaseDiffer 数据库结构差异比对器（按表名匹配多张表）
+type DatabaseDiffer struct {
+	source  *parser.DatabaseSchema // 源数据库结构
+	target  *parser.DatabaseSchema // 目标数据库结构
+	options Options                // 比对选项
+}
+
+// DatabaseDiff 数据库结构差异
+type DatabaseDiff struct {
+	AddedTables    []*parser.TableSchema // 新增的表
+	RemovedTables  []*parser.TableSchema // 删除的表
+	ModifiedTables []*TableDiff          // 修改的表
+	Dialect        parser.Dialect        // 生成 DDL 使用的方言（为空表示 MySQL）
+}
+
+// TableDiff 单张表的差异详情
+type TableDiff struct {
+	Name   string              // 表名（生成 DDL 时使用）
+	Source *parser.TableSchema // 源表结构
+	Target *parser.TableSchema // 目标表结构
+	Diff   *Diff               // 表内差异
+}
+
+// NewDatabaseDiffer 使用默认选项创建数据库级差异比对器
+func NewDatabaseDiffer(source, target *parser.DatabaseSchema) *DatabaseDiffer {
+	return NewDatabaseDifferWithOptions(source, target, DefaultOptions())
+}
+
+// NewDatabaseDifferWithOptions 使用指定选项创建数据库级差异比对器
+func NewDatabaseDifferWithOptions(source, target *parser.DatabaseSchema, options Options) *DatabaseDiffer {
+	return &DatabaseDiffer{
+		source:  source,
+		target:  target,
+		options: options,
+	}
+}
+
+// NewTableDiff 使用默认选项将两张表作为同一张表比对（表名以源表为准）
+func NewTableDiff(source, target *parser.TableSchema) *TableDiff {
+	return NewTableDiffWithOptions(source, target, DefaultOptions())
+}
+
+// NewTableDiffWithOptions 使用指定选项将两张表作为同一张表比对（表名以源表为准）
+func NewTableDiffWithOptions(source, target *parser.TableSchema, options Options) *TableDiff {
+	return &TableDiff{
+		Name:   source.Name,
+		Source: source,
+		Target: target,
+		Diff:   NewDifferWithOptions(source, target, options).Compare(),
+	}
+}
+
+// Compare 按表名比对两个数据库结构并返回差异
+func (d *DatabaseDiffer) Compare() *DatabaseDiff {
+	diff := &DatabaseDiff{
+		AddedTables:    make([]*parser.TableSchema, 0),
+		RemovedTables:  make([]*parser.TableSchema, 0),
+		ModifiedTables: make([]*TableDiff, 0),
+		Dialect:        d.options.Dialect,
+	}
+
+	// 查找新增和修改的表（按目标结构中的顺序）
+	for _, targetTable := range d.target.Tables {
+		sourceTable := d.source.Table(targetTable.Name)
+		if sourceTable == nil {
+			diff.AddedTables = append(diff.AddedTables, targetTable)
+			continue
+		}
+		if tableDiff := NewTableDiffWithOptions(sourceTable, targetTable, d.options); tableDiff.Diff.HasChanges() {
+			diff.ModifiedTables = append(diff.ModifiedTables, tableDiff)
+		}
+	}
+
+	// 查找删除的表
+	for _, sourceTable := range d.source.Tables {
+		if d.target.Table(sourceTable.Name) == nil {
+			diff.RemovedTables = append(diff.RemovedTables, sourceTable)
+		}
+	}
+
+	return diff
+}
+
+// HasChanges 判断是否有变更
+func (d *DatabaseDiff) HasChanges() bool {
+	return len(d.AddedTables) > 0 ||
+		len(d.RemovedTables) > 0 ||
+		len(d.ModifiedTables) > 0
+}
+
+// GenerateDDL 生成整个数据库的迁移脚本
+// 顺序：新建表（被外键引用的表在前）→ 修改表 → 新建表中推迟的外键 → 删除表（引用其他表的表在前，删除语句注释掉，因为删除操作比较危险）
+func (d *DatabaseDiff) GenerateDDL() []string {
+	ddls := make([]string, 0)
+	dialect := dialectFor(d.Dialect)
+
+	tables, deferred := d.sortAddedTables(dialect)
+	for _, table := range tables {
+		ddls = append(ddls, dialect.createTable(withoutForeignKeys(table, deferred))...)
+	}
+
+	for _, tableDiff := range d.ModifiedTables {
+		ddls = append(ddls, tableDiff.Diff.GenerateDDL(tableDiff.Name)...)
+	}
+
+	// 循环依赖的外键和引用已有表的外键在所有表创建、修改之后再添加
+	for _, item := range deferred {
+		ddls = append(ddls, dialect.addForeignKey(item.table, item.fk))
+	}
+
+	// 引用其他表的表先删除（与建表顺序相反），循环依赖的外键需要先删除
+	removed, cyclic := sortTablesByForeignKeys(d.RemovedTables)
+	if !dialect.deferForeignKeys() {
+		cyclic = nil
+	}
+	for _, item := range cyclic {
+		ddls = append(ddls, "-- "+dialect.dropForeignKey(item.table, item.fk))
+	}
+	for i := len(removed) - 1; i >= 0; i-- {
+		ddls = append(ddls, fmt.Sprintf("-- DROP TABLE IF EXISTS %s", dialect.quote(removed[i].Name)))
+	}
+
+	return ddls
+}
+
+// Summary 返回数据库差异摘要
+func (d *DatabaseDiff) Summary() string {
+	var summary strings.Builder
+
+	if len(d.AddedTables) > 0 {
+		summary.WriteString(fmt.Sprintf("新增表: %d 个\n", len(d.AddedTables)))
+		for _, table := range d.AddedTables {
+			summary.WriteString(fmt.Sprintf("  + %s (%d 列)\n", table.Name, len(table.Columns)))
+		}
+	}
+
+	if len(d.RemovedTables) > 0 {
+		summary.WriteString(fmt.Sprintf("删除表: %d 个\n", len(d.RemovedTables)))
+		for _, table := range d.RemovedTables {
+			summary.WriteString(fmt.Sprintf("  - %s\n", table.Name))
+		}
+	}
+
+	for _, tableDiff := range d.ModifiedTables {
+		summary.WriteString(fmt.Sprintf("修改表: %s\n", tableDiff.Name))
+		for _, line := range strings.Split(strings.TrimRight(tableDiff.Diff.Summary(), "\n"), "\n") {
+			summary.WriteString("  " + line + "\n")
+		}
+	}
+
+	if summary.Len() == 0 {
+		return "没有发现差异"
+	}
+
+	return summary.String()
+}
+
+// tableOptionOrder 生成 CREATE TABLE 时表选项的输出顺序
+var tableOptionOrder = []string{"ENGINE", "CHARSET", "COLLATE", "ROW_FORMAT", "COMMENT"}
+
+// FormatCreateTable 根据表结构生成 CREATE TABLE 语句（不含结尾分号）
+func FormatCreateTable(table *parser.TableSchema) string {
+	var defs []string
+
+	for _, col := range table.Columns {
+		defs = append(defs, fmt.Sprintf("%s %s", quoteMySQLIdent(col.Name), formatColumnDefinition(col)))
+	}
+
+	if len(table.PrimaryKeys) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(table.PrimaryKeys, quoteMySQLIdent)))
+	}
+
+	for _, idx := range table.Indexes {
+		defs = append(defs, formatIndexDefinition(idx))
+	}
+
+	for _, fk := range table.ForeignKeys() {
+		defs = append(defs, formatForeignKey(fk))
+	}
+
+	for _, check := range table.Checks() {
+		defs = append(defs, mysqlDialect{}.checkDefinition(check))
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", quoteMySQLIdent(table.Name), strings.Join(defs, ",\n  "))
+	if options := formatTableOptions(table.Options); options != "" {
+		ddl += " " + options
+	}
+	if table.Partitioning != nil {
+		ddl += "\n" + formatPartitioning(table.Partitioning)
+	}
+	return ddl
+}
+
+// FormatSchema 以指定方言将整个数据库结构输出为规范化的建表脚本
+// 表保持脚本中的出现顺序，每条语句以分号结尾，表之间以空行分隔；
+// 同一结构总是得到相同的输出，再次解析后与原结构没有差异，适合提交到版本库
+func FormatSchema(db *parser.DatabaseSchema, dialect parser.Dialect) string {
+	ddl := dialectFor(dialect)
+	blocks := make([]string, 0, len(db.Tables))
+	for _, table := range db.Tables {
+		blocks = append(blocks, strings.Join(ddl.createTable(table), ";\n")+";")
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// formatTableOptions 格式化表选项（AUTO_INCREMENT 计数器不属于结构定义，不输出）
+func formatTableOptions(options map[string]string) string {
+	var parts []string
+	seen := map[string]bool{"AUTO_INCREMENT": true}
+
+	for _, name := range tableOptionOrder {
+		if value, ok := options[name]; ok {
+			parts = append(parts, formatTableOption(name, value))
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range options {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		parts = append(parts, formatTableOption(name, options[name]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatTableOption 格式化单个表选项，如 ENGINE=InnoDB、COMMENT='用户表'
+func formatTableOption(name, value string) string {
+	switch name {
+	case "CHARSET":
+		return fmt.Sprintf("DEFAULT CHARSET=%s", value)
+	case "COMMENT", "CONNECTION", "PASSWORD", "DATA DIRECTORY", "INDEX DIRECTORY", "COMPRESSION", "ENCRYPTION":
+		return fmt.Sprintf("%s=%s", name, quoteMySQLString(value))
+	default:
+		return fmt.Sprintf("%s=%s", name, value)
+	}
+}
+
+// deferredForeignKey 需要在建表之后单独添加的外键
+type deferredForeignKey struct {
+	table string
+	fk    *parser.Constraint
+}
+
+// sortTablesByForeignKeys 按外键依赖对新建表排序，被引用的表排在前面
+// 存在循环依赖时，打破循环的外键会被返回，需要在所有表创建之后再单独添加
+func sortTablesByForeignKeys(tables []*parser.TableSchema) ([]*parser.TableSchema, []deferredForeignKey) {
+	pending := make(map[string]bool)
+	for _, table := range tables {
+		pending[table.Name] = true
+	}
+
+	ordered := make([]*parser.TableSchema, 0, len(tables))
+	var deferred []deferredForeignKey
+
+	// ready 判断表引用的其他新建表是否都已创建
+	ready := func(table *parser.TableSchema) bool {
+		for _, fk := range table.ForeignKeys() {
+			if fk.RefTable != table.Name && pending[fk.RefTable] {
+				return false
+			}
+		}
+		return true
+	}
+
+	remaining := tables
+	for len(remaining) > 0 {
+		next := -1
+		for i, table := range remaining {
+			if ready(table) {
+				next = i
+				break
+			}
+		}
+
+		// 循环依赖：取第一张表，推迟其引用未创建表的外键
+		if next == -1 {
+			next = 0
+			table := remaining[0]
+			for _, fk := range table.ForeignKeys() {
+				if fk.RefTable != table.Name && pending[fk.RefTable] {
+					deferred = append(deferred, deferredForeignKey{table: table.Name, fk: fk})
+				}
+			}
+		}
+
+		table := remaining[next]
+		ordered = append(ordered, table)
+		delete(pending, table.Name)
+		remaining = append(remaining[:next:next], remaining[next+1:]...)
+	}
+
+	return ordered, deferred
+}
+
+// sortAddedTables 按外键依赖对新建表排序，并返回需要在建表之后单独添加的外键：
+// 循环依赖的外键，以及引用已有表的外键（被引用的列或索引可能由修改表的语句新增）
+// 建表时不检查被引用表的方言（SQLite）外键始终随建表语句定义
+func (d *DatabaseDiff) sortAddedTables(dialect ddlDialect) ([]*parser.TableSchema, []deferredForeignKey) {
+	tables, deferred := sortTablesByForeignKeys(d.AddedTables)
+	if !dialect.deferForeignKeys() {
+		return tables, nil
+	}
+	added := make(map[string]bool)
+	for _, table := range d.AddedTables {
+		added[table.Name] = true
+	}
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys() {
+			if !added[fk.RefTable] {
+				deferred = append(deferred, deferredForeignKey{table: table.Name, fk: fk})
+			}
+		}
+	}
+	return tables, deferred
+}
+
+// withoutForeignKeys 返回去除指定外键后的表结构副本
+func withoutForeignKeys(table *parser.TableSchema, excluded []deferredForeignKey) *parser.TableSchema {
+	skip := make(map[*parser.Constraint]bool)
+	for _, item := range excluded {
+		skip[item.fk] = true
+	}
+
+	copied := *table
+	copied.Constraints = make([]*parser.Constraint, 0, len(table.Constraints))
+	for _, c := range table.Constraints {
+		if !skip[c] {
+			copied.Constraints = append(copied.Constraints, c)
+		}
+	}
+	return &copied
+}
@@ -0,0 +1,244 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// compareDialect 以指定方言解析并比对两段脚本
+func compareDialect(t *testing.T, dialect parser.Dialect, sourceSQL, targetSQL string) *DatabaseDiff {
+	t.Helper()
+	p := parser.NewParserWithDialect(dialect)
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源脚本失败: %v", err)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标脚本失败: %v", err)
+	}
+	options := DefaultOptions()
+	options.Dialect = dialect
+	return NewDatabaseDifferWithOptions(source, target, options).Compare()
+}
+
+func TestDatabaseDiff(t *testing.T) {
+	sourceSQL := `
+	CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(100));
+	CREATE TABLE legacy (id INT);
+	CREATE TABLE logs (id INT);`
+
+	targetSQL := `
+	CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		user_id INT NOT NULL,
+		INDEX idx_user (user_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+	CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(200));
+	CREATE TABLE logs (id INT);`
+
+	p := parser.NewParser()
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v", err)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v", err)
+	}
+
+	diff := NewDatabaseDiffer(source, target).Compare()
+
+	if len(diff.AddedTables) != 1 || diff.AddedTables[0].Name != "orders" {
+		t.Errorf("期望新增表 orders，实际 %v", diff.AddedTables)
+	}
+
+	if len(diff.RemovedTables) != 1 || diff.RemovedTables[0].Name != "legacy" {
+		t.Errorf("期望删除表 legacy，实际 %v", diff.RemovedTables)
+	}
+
+	if len(diff.ModifiedTables) != 1 || diff.ModifiedTables[0].Name != "users" {
+		t.Fatalf("期望修改表 users，实际 %v", diff.ModifiedTables)
+	}
+
+	ddls := diff.GenerateDDL()
+	if len(ddls) != 3 {
+		t.Fatalf("期望生成 3 条 DDL，实际 %d 条: %v", len(ddls), ddls)
+	}
+
+	if !strings.HasPrefix(ddls[0], "CREATE TABLE orders (") ||
+		!strings.Contains(ddls[0], "INDEX idx_user (user_id)") ||
+		!strings.HasSuffix(ddls[0], "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4") {
+		t.Errorf("CREATE TABLE 语句错误: %s", ddls[0])
+	}
+
+	if !strings.Contains(ddls[1], "ALTER TABLE users MODIFY COLUMN name") {
+		t.Errorf("修改表语句错误: %s", ddls[1])
+	}
+
+	if ddls[2] != "-- DROP TABLE IF EXISTS legacy" {
+		t.Errorf("删除表语句错误: %s", ddls[2])
+	}
+}
+
+func TestDatabaseNoChanges(t *testing.T) {
+	sql := `CREATE TABLE a (id INT); CREATE TABLE b (id INT);`
+
+	p := parser.NewParser()
+	source, _ := p.ParseScript(sql)
+	target, _ := p.ParseScript(sql)
+
+	diff := NewDatabaseDiffer(source, target).Compare()
+	if diff.HasChanges() {
+		t.Error("相同的数据库结构不应有变更")
+	}
+
+	if diff.Summary() != "没有发现差异" {
+		t.Errorf("摘要错误: %s", diff.Summary())
+	}
+}
+
+func TestDatabaseDiffForeignKeyOrder(t *testing.T) {
+	targetSQL := `
+	CREATE TABLE order_items (
+		id INT PRIMARY KEY,
+		order_id INT,
+		CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id)
+	);
+	CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		last_item_id INT,
+		CONSTRAINT fk_last_item FOREIGN KEY (last_item_id) REFERENCES order_items (id)
+	);
+	CREATE TABLE users (id INT PRIMARY KEY);
+	CREATE TABLE profiles (
+		user_id INT PRIMARY KEY,
+		CONSTRAINT fk_profile_user FOREIGN KEY (user_id) REFERENCES users (id)
+	);`
+
+	p := parser.NewParser()
+	source := parser.NewDatabaseSchema()
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v", err)
+	}
+
+	ddls := NewDatabaseDiffer(source, target).Compare().GenerateDDL()
+	if len(ddls) != 5 {
+		t.Fatalf("期望生成 5 条 DDL，实际 %d 条: %v", len(ddls), ddls)
+	}
+
+	// users 无依赖最先创建，profiles 紧随其后
+	if !strings.HasPrefix(ddls[0], "CREATE TABLE users") || !strings.HasPrefix(ddls[1], "CREATE TABLE profiles") {
+		t.Errorf("被引用的 users 应在 profiles 之前创建: %v", ddls[:2])
+	}
+	// 循环依赖：order_items 先创建（不含外键），外键在最后补充
+	if !strings.HasPrefix(ddls[2], "CREATE TABLE order_items") || strings.Contains(ddls[2], "fk_order") {
+		t.Errorf("第 3 条应为不含外键的 order_items: %s", ddls[2])
+	}
+	if !strings.HasPrefix(ddls[3], "CREATE TABLE orders") || !strings.Contains(ddls[3], "fk_last_item") {
+		t.Errorf("第 4 条应为 orders: %s", ddls[3])
+	}
+	if ddls[4] != "ALTER TABLE order_items ADD CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id)" {
+		t.Errorf("推迟的外键语句错误: %s", ddls[4])
+	}
+}
+
+func TestDatabaseDiffDropOrder(t *testing.T) {
+	sourceSQL := `
+	CREATE TABLE users (id INT PRIMARY KEY);
+	CREATE TABLE profiles (
+		user_id INT PRIMARY KEY,
+		CONSTRAINT fk_profile_user FOREIGN KEY (user_id) REFERENCES users (id)
+	);
+	CREATE TABLE order_items (id INT PRIMARY KEY, order_id INT);
+	CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		last_item_id INT,
+		CONSTRAINT fk_last_item FOREIGN KEY (last_item_id) REFERENCES order_items (id)
+	);
+	ALTER TABLE order_items ADD CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id);`
+
+	source, err := parser.NewParser().ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v", err)
+	}
+	target := parser.NewDatabaseSchema()
+
+	d := NewDatabaseDiffer(source, target)
+	diff := d.Compare()
+
+	// 引用其他表的表先删除，循环依赖的外键最先删除
+	expected := []string{
+		"-- ALTER TABLE order_items DROP FOREIGN KEY fk_order",
+		"-- DROP TABLE IF EXISTS orders",
+		"-- DROP TABLE IF EXISTS order_items",
+		"-- DROP TABLE IF EXISTS profiles",
+		"-- DROP TABLE IF EXISTS users",
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("删除表的顺序不正确:\n%s", strings.Join(ddls, "\n"))
+	}
+	if err := d.Verify(diff); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+
+	// 回滚时被引用的表先恢复
+	rollback := strings.Join(diff.GenerateRollbackDDL(), "\n")
+	if strings.Index(rollback, "-- CREATE TABLE users") > strings.Index(rollback, "-- CREATE TABLE profiles") ||
+		!strings.HasSuffix(rollback, "-- ALTER TABLE order_items ADD CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id)") {
+		t.Errorf("恢复表的顺序不正确:\n%s", rollback)
+	}
+}
+
+func TestDatabaseDiffDefersForeignKeysToExistingTables(t *testing.T) {
+	sourceSQL := `CREATE TABLE users (id INT PRIMARY KEY);`
+	targetSQL := `
+	CREATE TABLE users (id INT PRIMARY KEY, code VARCHAR(20), UNIQUE KEY uk_code (code));
+	CREATE TABLE cards (
+		id INT PRIMARY KEY,
+		user_code VARCHAR(20),
+		CONSTRAINT fk_card_user FOREIGN KEY (user_code) REFERENCES users (code)
+	);`
+
+	p := parser.NewParser()
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v", err)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v", err)
+	}
+
+	d := NewDatabaseDiffer(source, target)
+	diff := d.Compare()
+
+	// 被引用的列和唯一索引由修改表的语句新增，外键需要在其之后添加
+	ddls := strings.Join(diff.GenerateDDL(), "\n")
+	if strings.Contains(ddls, "CONSTRAINT fk_card_user FOREIGN KEY (user_code) REFERENCES users (code)\n)") ||
+		!strings.HasSuffix(ddls, "ALTER TABLE cards ADD CONSTRAINT fk_card_user FOREIGN KEY (user_code) REFERENCES users (code)") {
+		t.Errorf("外键应在修改表之后添加:\n%s", ddls)
+	}
+	if err := d.Verify(diff); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+
+	// 回滚时先删除外键，再回滚被引用的列
+	rollback := diff.GenerateRollbackDDL()
+	if len(rollback) == 0 || rollback[0] != "ALTER TABLE cards DROP FOREIGN KEY fk_card_user" {
+		t.Errorf("回滚时应先删除外键:\n%s", strings.Join(rollback, "\n"))
+	}
+
+	// 验证会检查外键引用的列在执行时是否存在
+	_, err = applyDDL(source, []string{
+		"CREATE TABLE cards (id INT PRIMARY KEY, user_code VARCHAR(20), " +
+			"CONSTRAINT fk_card_user FOREIGN KEY (user_code) REFERENCES users (code))",
+		"ALTER TABLE users ADD COLUMN code VARCHAR(20)",
+	}, parser.MySQL, target)
+	if err == nil || !strings.Contains(err.Error(), "外键 fk_card_user 引用的列 users.code 不存在") {
+		t.Errorf("期望外键引用不存在的列时验证失败，实际为 %v", err)
+	}
+}
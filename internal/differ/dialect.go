@@ -0,0 +1,381 @@
+package differ
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// ddlDialect 数据库方言的 DDL 生成规则
+// 表名、列名等参数均为未加引号的原始名称，由方言自行决定是否加引号；
+// 返回多条语句的方法，各语句需按顺序执行
+type ddlDialect interface {
+	// quote 返回可在语句中直接使用的标识符
+	quote(name string) string
+	// supportsColumnOrder 是否支持调整列顺序（AFTER / FIRST）
+	supportsColumnOrder() bool
+
+	// columnDefinition 格式化列定义（不含列名）
+	columnDefinition(col *parser.Column) string
+	// indexDefinition 格式化索引定义
+	indexDefinition(table string, idx *parser.Index) string
+	// foreignKeyDefinition 格式化外键定义（不含 ADD 前缀）
+	foreignKeyDefinition(fk *parser.Constraint) string
+	// createTable 生成建表语句（可能附带建索引、注释等语句）
+	createTable(table *parser.TableSchema) []string
+
+	// addColumn 生成新增列的语句，placement 为位置子句（带前导空格）
+	addColumn(table string, col *parser.Column, placement string) []string
+	// modifyColumn 生成将列从 source 修改为 target 的语句
+	modifyColumn(table string, source, target *parser.Column, placement string) []string
+	// renameColumn 生成重命名列（可能同时修改定义）的语句
+	renameColumn(table string, rename *ColumnRename) []string
+	// dropColumn 生成删除列的语句
+	dropColumn(table, column string) string
+
+	// addIndex 生成新增索引的语句
+	addIndex(table string, idx *parser.Index) string
+	// modifyIndex 生成重建索引或调整可见性的语句
+	modifyIndex(table string, diff *IndexDiff) []string
+	// renameIndex 生成重命名索引的语句
+	renameIndex(table string, rename *IndexRename) []string
+	// dropIndex 生成删除索引的语句
+	dropIndex(table string, idx *parser.Index) string
+
+	// addForeignKey 生成新增外键的语句
+	addForeignKey(table string, fk *parser.Constraint) string
+	// dropForeignKey 生成删除外键的语句
+	dropForeignKey(table string, fk *parser.Constraint) string
+
+	// checkDefinition 格式化 CHECK 约束定义（不含 ADD 前缀）
+	checkDefinition(check *parser.Constraint) string
+	// addCheck 生成新增 CHECK 约束的语句
+	addCheck(table string, check *parser.Constraint) string
+	// modifyCheck 生成重建 CHECK 约束或调整强制检查状态的语句
+	modifyCheck(table string, diff *ConstraintDiff) []string
+	// dropCheck 生成删除 CHECK 约束的语句
+	dropCheck(table string, check *parser.Constraint) string
+
+	// alterTableOptions 生成修改表选项的语句
+	alterTableOptions(d *Diff, table string) []string
+	// alterPartitions 生成分区变更的语句（删除分区的语句位于首位）
+	alterPartitions(table string, d *PartitionDiff) []string
+
+	// deferForeignKeys 新建表之间存在循环引用时，是否需要在建表后单独添加外键
+	deferForeignKeys() bool
+
+	// primaryKey 生成主键变更语句
+	// 返回值 handled 中的列已在主键语句中处理；restore 需在索引调整完成后执行（可为空）
+	primaryKey(d *Diff, table string) (ddl string, handled map[string]bool, restore string)
+}
+
+// tableRebuilder 部分变更需要通过重建表完成的方言（SQLite）
+type tableRebuilder interface {
+	// needsRebuild 判断表的差异中是否存在无法直接 ALTER 的变更
+	needsRebuild(d *Diff, rollback bool) bool
+	// rebuildTable 生成重建表的语句，替代该表的全部 ALTER 语句
+	rebuildTable(d *Diff, table string, rollback bool) []string
+}
+
+// dialectFor 返回方言对应的 DDL 生成规则（未指定时为 MySQL）
+func dialectFor(dialect parser.Dialect) ddlDialect {
+	switch dialect {
+	case parser.PostgreSQL:
+		return postgresDialect{}
+	case parser.SQLite:
+		return sqliteDialect{}
+	}
+	return mysqlDialect{}
+}
+
+// dialect 返回差异比对时使用的方言
+func (d *Diff) dialect() ddlDialect {
+	return dialectFor(d.options.Dialect)
+}
+
+// mysqlDialect MySQL 方言
+// 标识符仅在必要时（保留字、含特殊字符）加反引号，普通名称保持原样，便于阅读
+type mysqlDialect struct{}
+
+// mysqlPlainIdent 无需加引号的 MySQL 标识符
+var mysqlPlainIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// mysqlReservedWords 作为标识符时必须加引号的 MySQL 8.0 保留字
+var mysqlReservedWords = map[string]bool{
+	"ACCESSIBLE": true, "ADD": true, "ALL": true, "ALTER": true, "ANALYZE": true, "AND": true,
+	"AS": true, "ASC": true, "ASENSITIVE": true, "BEFORE": true, "BETWEEN": true, "BIGINT": true,
+	"BINARY": true, "BLOB": true, "BOTH": true, "BY": true, "CALL": true, "CASCADE": true,
+	"CASE": true, "CHANGE": true, "CHAR": true, "CHARACTER": true, "CHECK": true, "COLLATE": true,
+	"COLUMN": true, "CONDITION": true, "CONSTRAINT": true, "CONTINUE": true, "CONVERT": true,
+	"CREATE": true, "CROSS": true, "CUBE": true, "CUME_DIST": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "CURSOR": true,
+	"DATABASE": true, "DATABASES": true, "DAY_HOUR": true, "DAY_MICROSECOND": true,
+	"DAY_MINUTE": true, "DAY_SECOND": true, "DEC": true, "DECIMAL": true, "DECLARE": true,
+	"DEFAULT": true, "DELAYED": true, "DELETE": true, "DENSE_RANK": true, "DESC": true,
+	"DESCRIBE": true, "DETERMINISTIC": true, "DISTINCT": true, "DISTINCTROW": true, "DIV": true,
+	"DOUBLE": true, "DROP": true, "DUAL": true, "EACH": true, "ELSE": true, "ELSEIF": true,
+	"EMPTY": true, "ENCLOSED": true, "ESCAPED": true, "EXCEPT": true, "EXISTS": true, "EXIT": true,
+	"EXPLAIN": true, "FALSE": true, "FETCH": true, "FIRST_VALUE": true, "FLOAT": true, "FLOAT4": true,
+	"FLOAT8": true, "FOR": true, "FORCE": true, "FOREIGN": true, "FROM": true, "FULLTEXT": true,
+	"FUNCTION": true, "GENERATED": true, "GET": true, "GRANT": true, "GROUP": true, "GROUPING": true,
+	"GROUPS": true, "HAVING": true, "HIGH_PRIORITY": true, "HOUR_MICROSECOND": true,
+	"HOUR_MINUTE": true, "HOUR_SECOND": true, "IF": true, "IGNORE": true, "IN": true, "INDEX": true,
+	"INFILE": true, "INNER": true, "INOUT": true, "INSENSITIVE": true, "INSERT": true, "INT": true,
+	"INT1": true, "INT2": true, "INT3": true, "INT4": true, "INT8": true, "INTEGER": true,
+	"INTERSECT": true, "INTERVAL": true, "INTO": true, "IO_AFTER_GTIDS": true,
+	"IO_BEFORE_GTIDS": true, "IS": true, "ITERATE": true, "JOIN": true, "JSON_TABLE": true,
+	"KEY": true, "KEYS": true, "KILL": true, "LAG": true, "LAST_VALUE": true, "LATERAL": true,
+	"LEAD": true, "LEADING": true, "LEAVE": true, "LEFT": true, "LIKE": true, "LIMIT": true,
+	"LINEAR": true, "LINES": true, "LOAD": true, "LOCALTIME": true, "LOCALTIMESTAMP": true,
+	"LOCK": true, "LONG": true, "LONGBLOB": true, "LONGTEXT": true, "LOOP": true,
+	"LOW_PRIORITY": true, "MASTER_BIND": true, "MASTER_SSL_VERIFY_SERVER_CERT": true, "MATCH": true,
+	"MAXVALUE": true, "MEDIUMBLOB": true, "MEDIUMINT": true, "MEDIUMTEXT": true, "MIDDLEINT": true,
+	"MINUTE_MICROSECOND": true, "MINUTE_SECOND": true, "MOD": true, "MODIFIES": true, "NATURAL": true,
+	"NOT": true, "NO_WRITE_TO_BINLOG": true, "NTH_VALUE": true, "NTILE": true, "NULL": true,
+	"NUMERIC": true, "OF": true, "ON": true, "OPTIMIZE": true, "OPTIMIZER_COSTS": true,
+	"OPTION": true, "OPTIONALLY": true, "OR": true, "ORDER": true, "OUT": true, "OUTER": true,
+	"OUTFILE": true, "OVER": true, "PARTITION": true, "PERCENT_RANK": true, "PRECISION": true,
+	"PRIMARY": true, "PROCEDURE": true, "PURGE": true, "RANGE": true, "RANK": true, "READ": true,
+	"READS": true, "READ_WRITE": true, "REAL": true, "RECURSIVE": true, "REFERENCES": true,
+	"REGEXP": true, "RELEASE": true, "RENAME": true, "REPEAT": true, "REPLACE": true, "REQUIRE": true,
+	"RESIGNAL": true, "RESTRICT": true, "RETURN": true, "REVOKE": true, "RIGHT": true, "RLIKE": true,
+	"ROW": true, "ROWS": true, "ROW_NUMBER": true, "SCHEMA": true, "SCHEMAS": true,
+	"SECOND_MICROSECOND": true, "SELECT": true, "SENSITIVE": true, "SEPARATOR": true, "SET": true,
+	"SHOW": true, "SIGNAL": true, "SMALLINT": true, "SPATIAL": true, "SPECIFIC": true, "SQL": true,
+	"SQLEXCEPTION": true, "SQLSTATE": true, "SQLWARNING": true, "SQL_BIG_RESULT": true,
+	"SQL_CALC_FOUND_ROWS": true, "SQL_SMALL_RESULT": true, "SSL": true, "STARTING": true,
+	"STORED": true, "STRAIGHT_JOIN": true, "SYSTEM": true, "TABLE": true, "TERMINATED": true,
+	"THEN": true, "TINYBLOB": true, "TINYINT": true, "TINYTEXT": true, "TO": true, "TRAILING": true,
+	"TRIGGER": true, "TRUE": true, "UNDO": true, "UNION": true, "UNIQUE": true, "UNLOCK": true,
+	"UNSIGNED": true, "UPDATE": true, "USAGE": true, "USE": true, "USING": true, "UTC_DATE": true,
+	"UTC_TIME": true, "UTC_TIMESTAMP": true, "VALUES": true, "VARBINARY": true, "VARCHAR": true,
+	"VARCHARACTER": true, "VARYING": true, "VIRTUAL": true, "WHEN": true, "WHERE": true,
+	"WHILE": true, "WINDOW": true, "WITH": true, "WRITE": true, "XOR": true, "YEAR_MONTH": true,
+	"ZEROFILL": true,
+}
+
+func (mysqlDialect) quote(name string) string {
+	return quoteMySQLIdent(name)
+}
+
+// quoteMySQLIdent 返回可在 MySQL 语句中直接使用的标识符，保留字和含特殊字符的名称加反引号
+func quoteMySQLIdent(name string) string {
+	if mysqlPlainIdent.MatchString(name) && !mysqlReservedWords[strings.ToUpper(name)] {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (mysqlDialect) supportsColumnOrder() bool {
+	return true
+}
+
+func (mysqlDialect) columnDefinition(col *parser.Column) string {
+	return formatColumnDefinition(col)
+}
+
+func (mysqlDialect) indexDefinition(table string, idx *parser.Index) string {
+	return formatIndexDefinition(idx)
+}
+
+func (mysqlDialect) foreignKeyDefinition(fk *parser.Constraint) string {
+	return formatForeignKey(fk)
+}
+
+func (mysqlDialect) createTable(table *parser.TableSchema) []string {
+	return []string{FormatCreateTable(table)}
+}
+
+func (mysqlDialect) addColumn(table string, col *parser.Column, placement string) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s",
+		quoteMySQLIdent(table), quoteMySQLIdent(col.Name), formatColumnDefinition(col), placement)}
+}
+
+// modifyColumn 虚拟生成列与存储生成列、普通列之间不能直接 MODIFY，在同一条语句中删除后重新添加
+// （compareColumnOrder 会为这类列计算位置子句，保持列的位置不变）
+func (mysqlDialect) modifyColumn(table string, source, target *parser.Column, placement string) []string {
+	if isVirtualColumn(source) != isVirtualColumn(target) {
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s, ADD COLUMN %s %s%s",
+			quoteMySQLIdent(table), quoteMySQLIdent(source.Name), quoteMySQLIdent(target.Name), formatColumnDefinition(target), placement)}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s%s",
+		quoteMySQLIdent(table), quoteMySQLIdent(target.Name), formatColumnDefinition(target), placement)}
+}
+
+func (mysqlDialect) renameColumn(table string, rename *ColumnRename) []string {
+	if len(rename.Changes) == 0 {
+		return []string{fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
+			quoteMySQLIdent(table), quoteMySQLIdent(rename.From), quoteMySQLIdent(rename.To))}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s %s",
+		quoteMySQLIdent(table), quoteMySQLIdent(rename.From), quoteMySQLIdent(rename.To), formatColumnDefinition(rename.Target))}
+}
+
+func (mysqlDialect) dropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quoteMySQLIdent(table), quoteMySQLIdent(column))
+}
+
+func (mysqlDialect) addIndex(table string, idx *parser.Index) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", quoteMySQLIdent(table), formatIndexDefinition(idx))
+}
+
+// modifyIndex 删除与重建在同一条语句中完成，表不会出现缺少该索引的时间窗口
+func (mysqlDialect) modifyIndex(table string, diff *IndexDiff) []string {
+	if diff.VisibilityOnly() {
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER INDEX %s %s",
+			quoteMySQLIdent(table), quoteMySQLIdent(diff.Name), formatIndexVisibility(diff.Target))}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP INDEX %s, ADD %s",
+		quoteMySQLIdent(table), quoteMySQLIdent(diff.Source.Name), formatIndexDefinition(diff.Target))}
+}
+
+func (mysqlDialect) renameIndex(table string, rename *IndexRename) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME INDEX %s TO %s",
+		quoteMySQLIdent(table), quoteMySQLIdent(rename.From), quoteMySQLIdent(rename.To))}
+}
+
+func (mysqlDialect) dropIndex(table string, idx *parser.Index) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", quoteMySQLIdent(table), quoteMySQLIdent(idx.Name))
+}
+
+func (mysqlDialect) addForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", quoteMySQLIdent(table), formatForeignKey(fk))
+}
+
+func (mysqlDialect) dropForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", quoteMySQLIdent(table), quoteMySQLIdent(fk.Name))
+}
+
+func (mysqlDialect) checkDefinition(check *parser.Constraint) string {
+	return formatCheck(check, quoteMySQLIdent)
+}
+
+func (m mysqlDialect) addCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", quoteMySQLIdent(table), m.checkDefinition(check))
+}
+
+// modifyCheck 仅强制检查状态变化时直接 ALTER CHECK，否则在同一条语句中删除并重建
+func (m mysqlDialect) modifyCheck(table string, diff *ConstraintDiff) []string {
+	if diff.EnforcementOnly() {
+		enforced := "ENFORCED"
+		if diff.Target.NotEnforced {
+			enforced = "NOT ENFORCED"
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER CHECK %s %s", quoteMySQLIdent(table), quoteMySQLIdent(diff.Source.Name), enforced)}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CHECK %s, ADD %s",
+		quoteMySQLIdent(table), quoteMySQLIdent(diff.Source.Name), m.checkDefinition(diff.Target))}
+}
+
+func (mysqlDialect) dropCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", quoteMySQLIdent(table), quoteMySQLIdent(check.Name))
+}
+
+func (mysqlDialect) deferForeignKeys() bool {
+	return true
+}
+
+func (mysqlDialect) primaryKey(d *Diff, table string) (string, map[string]bool, string) {
+	return d.primaryKeyDDL(table)
+}
+
+// functionCall 函数调用形式的表达式，如 NOW()、NEXTVAL('seq'::regclass)
+var functionCall = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*\(.*\)$`)
+
+// defaultFunctionCall 函数调用形式的默认值，如 CURRENT_TIMESTAMP(3)、NEXTVAL('seq'::regclass)（解析时函数名统一为大写）
+var defaultFunctionCall = regexp.MustCompile(`^[A-Z_][A-Z0-9_.]*\(.*\)$`)
+
+// quoteList 格式化加引号的列名列表
+func quoteList(columns []string, quote func(string) string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quote(col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// formatKeyParts 格式化独立 CREATE INDEX 语句中的索引列（表达式保留原文，函数调用去掉外层括号）
+func formatKeyParts(idx *parser.Index, quote func(string) string) string {
+	parts := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		if strings.HasPrefix(col, "(") {
+			parts[i] = col
+			if inner := strings.TrimSpace(col[1 : len(col)-1]); functionCall.MatchString(inner) {
+				parts[i] = inner
+			}
+		} else {
+			parts[i] = quote(col)
+		}
+		if idx.IsDescending(i) {
+			parts[i] += " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatNamedForeignKey 以 CONSTRAINT name FOREIGN KEY 形式格式化外键（标准 SQL 写法）
+func formatNamedForeignKey(fk *parser.Constraint, quote func(string) string) string {
+	def := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s",
+		quote(fk.Name), quoteList(fk.Columns, quote), quote(fk.RefTable))
+	if len(fk.RefColumns) > 0 {
+		def += fmt.Sprintf(" (%s)", quoteList(fk.RefColumns, quote))
+	}
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	return def
+}
+
+// numericLiteral 数字字面量，如 0、-1.5、1e10（2024-01-01 这类日期不是数字）
+var numericLiteral = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// isNumericLiteral 判断默认值是否为数字字面量
+func isNumericLiteral(value string) bool {
+	return numericLiteral.MatchString(value)
+}
+
+// sameDefault 判断两列的默认值是否相同
+// 加引号的数字（如 SHOW CREATE TABLE 输出的 DEFAULT '0'）与数字字面量等价；
+// 可为空的列上 DEFAULT NULL 与没有默认值等价（从数据库读取时两者无法区分）
+func sameDefault(source, target *parser.Column) bool {
+	sourceValue, sourceQuoted := effectiveDefault(source)
+	targetValue, targetQuoted := effectiveDefault(target)
+	if sourceValue != targetValue {
+		return false
+	}
+	return sourceQuoted == targetQuoted || isNumericLiteral(sourceValue)
+}
+
+// effectiveDefault 返回比对时使用的默认值，可为空的列上的 DEFAULT NULL 视为没有默认值
+func effectiveDefault(col *parser.Column) (string, bool) {
+	if !col.NotNull && !col.DefaultQuoted && col.DefaultValue == "NULL" {
+		return "", false
+	}
+	return col.DefaultValue, col.DefaultQuoted
+}
+
+// describeDefault 返回变更说明中的默认值，字符串带引号以区分 'NULL' 与 NULL
+func describeDefault(col *parser.Column) string {
+	if col.DefaultQuoted {
+		return quoteStringLiteral(col.DefaultValue)
+	}
+	return col.DefaultValue
+}
+
+// quoteStringLiteral 将文本转为标准 SQL 字符串字面量（单引号加倍转义）
+func quoteStringLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// mysqlStringEscaper MySQL 字符串中需要转义的字符（默认 SQL 模式下反斜杠是转义符）
+var mysqlStringEscaper = strings.NewReplacer(`\`, `\\`, "'", "''")
+
+// quoteMySQLString 将文本转为 MySQL 字符串字面量（单引号加倍，反斜杠转义）
+func quoteMySQLString(s string) string {
+	return "'" + mysqlStringEscaper.Replace(s) + "'"
+}
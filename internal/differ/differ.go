@@ -9,17 +9,39 @@ import (
 
 // Differ 表结构差异比对器
 type Differ struct {
-	source *parser.TableSchema // 源表结构
-	target *parser.TableSchema // 目标表结构
+	source  *parser.TableSchema // 源表结构
+	target  *parser.TableSchema // 目标表结构
+	options Options             // 比对选项
+
+	columnRenames map[string]string // 已识别的列重命名（小写原列名 -> 新列名）
 }
 
 // Diff 表结构差异
 type Diff struct {
-	AddedColumns    []*parser.Column // 新增的列
-	RemovedColumns  []*parser.Column // 删除的列
-	ModifiedColumns []*ColumnDiff    // 修改的列
-	AddedIndexes    []*parser.Index  // 新增的索引
-	RemovedIndexes  []*parser.Index  // 删除的索引
+	AddedColumns        []*parser.Column     // 新增的列
+	RemovedColumns      []*parser.Column     // 删除的列
+	ModifiedColumns     []*ColumnDiff        // 修改的列
+	RenamedColumns      []*ColumnRename      // 重命名的列
+	AddedIndexes        []*parser.Index      // 新增的索引
+	RemovedIndexes      []*parser.Index      // 删除的索引
+	ModifiedIndexes     []*IndexDiff         // 修改的索引
+	RenamedIndexes      []*IndexRename       // 重命名的索引
+	PrimaryKey          *PrimaryKeyDiff      // 主键变更（nil 表示主键未变化）
+	AddedForeignKeys    []*parser.Constraint // 新增的外键
+	RemovedForeignKeys  []*parser.Constraint // 删除的外键
+	ModifiedForeignKeys []*ConstraintDiff    // 修改的外键
+	AddedChecks         []*parser.Constraint // 新增的 CHECK 约束
+	RemovedChecks       []*parser.Constraint // 删除的 CHECK 约束
+	ModifiedChecks      []*ConstraintDiff    // 修改的 CHECK 约束
+	TableOptions        []*TableOptionDiff   // 表选项变更
+	Partitions          *PartitionDiff       // 分区变更（nil 表示分区未变化）
+	RenameCandidates    []*RenameCandidate   // 未自动采用的可能重命名
+
+	placements map[string]string // 新增列、移动列的位置子句（小写列名 -> AFTER x / FIRST）
+
+	source  *parser.TableSchema // 比对的源表结构（用于生成回滚语句）
+	target  *parser.TableSchema // 比对的目标表结构
+	options Options             // 比对时使用的选项
 }
 
 // ColumnDiff 列的差异详情
@@ -28,13 +50,51 @@ type ColumnDiff struct {
 	Source  *parser.Column
 	Target  *parser.Column
 	Changes []string // 变更描述
+	Moved   bool     // 列位置是否调整
+}
+
+// IndexDiff 索引的差异详情
+type IndexDiff struct {
+	Name    string
+	Source  *parser.Index
+	Target  *parser.Index
+	Changes []string // 变更描述
+}
+
+// VisibilityOnly 判断是否仅可见性发生变化（可直接 ALTER INDEX，无需重建）
+func (d *IndexDiff) VisibilityOnly() bool {
+	return len(d.Changes) == 1 && d.Source.Invisible != d.Target.Invisible
 }
 
-// NewDiffer 创建新的差异比对器
+// PrimaryKeyDiff 主键的差异详情
+type PrimaryKeyDiff struct {
+	Source        []string       // 原主键列（为空表示新增主键）
+	Target        []string       // 新主键列（为空表示删除主键）
+	SourceName    string         // 原主键约束名（未命名时为空，MySQL 中始终为空）
+	TargetName    string         // 新主键约束名
+	SourceAutoInc *parser.Column // 源表中的 AUTO_INCREMENT 列
+	TargetAutoInc *parser.Column // 目标表中的 AUTO_INCREMENT 列
+}
+
+// ConstraintDiff 约束的差异详情
+type ConstraintDiff struct {
+	Name    string
+	Source  *parser.Constraint
+	Target  *parser.Constraint
+	Changes []string // 变更描述
+}
+
+// NewDiffer 使用默认选项创建差异比对器
 func NewDiffer(source, target *parser.TableSchema) *Differ {
+	return NewDifferWithOptions(source, target, DefaultOptions())
+}
+
+// NewDifferWithOptions 使用指定选项创建差异比对器
+func NewDifferWithOptions(source, target *parser.TableSchema, options Options) *Differ {
 	return &Differ{
-		source: source,
-		target: target,
+		source:  source,
+		target:  target,
+		options: options,
 	}
 }
 
@@ -44,9 +104,26 @@ func (d *Differ) Compare() *Diff {
 		AddedColumns:    make([]*parser.Column, 0),
 		RemovedColumns:  make([]*parser.Column, 0),
 		ModifiedColumns: make([]*ColumnDiff, 0),
+		RenamedColumns:  make([]*ColumnRename, 0),
 		AddedIndexes:    make([]*parser.Index, 0),
 		RemovedIndexes:  make([]*parser.Index, 0),
+		ModifiedIndexes: make([]*IndexDiff, 0),
+		RenamedIndexes:  make([]*IndexRename, 0),
+
+		AddedForeignKeys:    make([]*parser.Constraint, 0),
+		RemovedForeignKeys:  make([]*parser.Constraint, 0),
+		ModifiedForeignKeys: make([]*ConstraintDiff, 0),
+		AddedChecks:         make([]*parser.Constraint, 0),
+		RemovedChecks:       make([]*parser.Constraint, 0),
+		ModifiedChecks:      make([]*ConstraintDiff, 0),
+		TableOptions:        make([]*TableOptionDiff, 0),
+		RenameCandidates:    make([]*RenameCandidate, 0),
+
+		source:  d.source,
+		target:  d.target,
+		options: d.options,
 	}
+	d.columnRenames = make(map[string]string)
 
 	// 创建列映射便于查找
 	sourceColumns := make(map[string]*parser.Column)
@@ -63,7 +140,7 @@ func (d *Differ) Compare() *Diff {
 	for _, targetCol := range d.target.Columns {
 		if sourceCol, exists := sourceColumns[targetCol.Name]; exists {
 			// 列存在，检查是否有修改
-			if changes := compareColumns(sourceCol, targetCol); len(changes) > 0 {
+			if changes := compareColumns(sourceCol, targetCol, d.options.Dialect); len(changes) > 0 {
 				diff.ModifiedColumns = append(diff.ModifiedColumns, &ColumnDiff{
 					Name:    targetCol.Name,
 					Source:  sourceCol,
@@ -84,6 +161,9 @@ func (d *Differ) Compare() *Diff {
 		}
 	}
 
+	// 识别列重命名（后续索引、主键、外键的比对基于重命名后的列名）
+	d.detectColumnRenames(diff)
+
 	// 比对索引
 	sourceIndexes := make(map[string]*parser.Index)
 	for _, idx := range d.source.Indexes {
@@ -95,10 +175,20 @@ func (d *Differ) Compare() *Diff {
 		targetIndexes[idx.Name] = idx
 	}
 
-	// 查找新增的索引
+	// 查找新增和修改的索引
 	for _, targetIdx := range d.target.Indexes {
-		if _, exists := sourceIndexes[targetIdx.Name]; !exists {
+		sourceIdx, exists := sourceIndexes[targetIdx.Name]
+		if !exists {
 			diff.AddedIndexes = append(diff.AddedIndexes, targetIdx)
+			continue
+		}
+		if changes := compareIndexes(d.renamedIndex(sourceIdx), targetIdx, d.options.Dialect); len(changes) > 0 {
+			diff.ModifiedIndexes = append(diff.ModifiedIndexes, &IndexDiff{
+				Name:    targetIdx.Name,
+				Source:  sourceIdx,
+				Target:  targetIdx,
+				Changes: changes,
+			})
 		}
 	}
 
@@ -109,11 +199,239 @@ func (d *Differ) Compare() *Diff {
 		}
 	}
 
+	// 比对列顺序
+	d.compareColumnOrder(diff)
+
+	// 识别索引重命名
+	d.detectIndexRenames(diff)
+
+	// 比对主键（重命名语句最先执行，因此源主键使用重命名后的列名）
+	sourcePrimaryKeys := d.renamedColumns(d.source.PrimaryKeys)
+	if !equalColumnNames(sourcePrimaryKeys, d.target.PrimaryKeys) {
+		diff.PrimaryKey = &PrimaryKeyDiff{
+			Source:        sourcePrimaryKeys,
+			Target:        d.target.PrimaryKeys,
+			SourceName:    d.source.PrimaryKeyName,
+			TargetName:    d.target.PrimaryKeyName,
+			SourceAutoInc: d.renamedAutoIncColumn(),
+			TargetAutoInc: findAutoIncColumn(d.target),
+		}
+	}
+
+	// 比对外键
+	d.compareForeignKeys(diff)
+
+	// 比对 CHECK 约束
+	d.compareChecks(diff)
+
+	// 比对表选项
+	diff.TableOptions = append(diff.TableOptions, compareTableOptions(d.source, d.target, d.options)...)
+	diff.Partitions = comparePartitions(d.source.Partitioning, d.target.Partitioning, d.options.Dialect)
+
 	return diff
 }
 
-// compareColumns 比较两个列的差异
-func compareColumns(source, target *parser.Column) []string {
+// findAutoIncColumn 查找表中的 AUTO_INCREMENT 列
+func findAutoIncColumn(table *parser.TableSchema) *parser.Column {
+	for _, col := range table.Columns {
+		if col.AutoInc {
+			return col
+		}
+	}
+	return nil
+}
+
+// renamedAutoIncColumn 返回源表的 AUTO_INCREMENT 列（列名为重命名后的名称）
+func (d *Differ) renamedAutoIncColumn() *parser.Column {
+	col := findAutoIncColumn(d.source)
+	if col == nil || d.renamedColumn(col.Name) == col.Name {
+		return col
+	}
+	renamed := *col
+	renamed.Name = d.renamedColumn(col.Name)
+	return &renamed
+}
+
+// compareIndexes 比较两个同名索引的差异
+func compareIndexes(source, target *parser.Index, dialect parser.Dialect) []string {
+	changes := make([]string, 0)
+
+	if source.Type != target.Type {
+		changes = append(changes, fmt.Sprintf("类型从 %s 改为 %s", source.Type, target.Type))
+	}
+
+	if !equalKeyParts(source, target, dialect) {
+		changes = append(changes, fmt.Sprintf("列从 (%s) 改为 (%s)",
+			strings.Join(source.Columns, ", "), strings.Join(target.Columns, ", ")))
+	} else {
+		for i, col := range target.Columns {
+			if source.PrefixLength(i) != target.PrefixLength(i) {
+				changes = append(changes, fmt.Sprintf("列 %s 的前缀长度从 %d 改为 %d",
+					col, source.PrefixLength(i), target.PrefixLength(i)))
+			}
+			if source.IsDescending(i) != target.IsDescending(i) {
+				changes = append(changes, fmt.Sprintf("列 %s 从 %s 改为 %s",
+					col, keyPartDirection(source, i), keyPartDirection(target, i)))
+			}
+		}
+	}
+
+	if from, to := indexAlgorithm(source, dialect), indexAlgorithm(target, dialect); !strings.EqualFold(from, to) {
+		changes = append(changes, fmt.Sprintf("索引算法从 %s 改为 %s", orNone(from), orNone(to)))
+	}
+
+	if source.Comment != target.Comment {
+		changes = append(changes, fmt.Sprintf("注释从 '%s' 改为 '%s'", source.Comment, target.Comment))
+	}
+
+	if !strings.EqualFold(source.Parser, target.Parser) {
+		changes = append(changes, fmt.Sprintf("全文解析器从 %s 改为 %s", orNone(source.Parser), orNone(target.Parser)))
+	}
+
+	if source.Invisible != target.Invisible {
+		if target.Invisible {
+			changes = append(changes, "改为不可见")
+		} else {
+			changes = append(changes, "改为可见")
+		}
+	}
+
+	return changes
+}
+
+// compareForeignKeys 按约束名比对外键
+func (d *Differ) compareForeignKeys(diff *Diff) {
+	sourceFKs := make(map[string]*parser.Constraint)
+	for _, fk := range d.source.ForeignKeys() {
+		sourceFKs[fk.Name] = fk
+	}
+
+	targetFKs := make(map[string]*parser.Constraint)
+	for _, fk := range d.target.ForeignKeys() {
+		targetFKs[fk.Name] = fk
+	}
+
+	// 查找新增和修改的外键
+	for _, targetFK := range d.target.ForeignKeys() {
+		sourceFK, exists := sourceFKs[targetFK.Name]
+		if !exists {
+			diff.AddedForeignKeys = append(diff.AddedForeignKeys, targetFK)
+			continue
+		}
+		mapped := *sourceFK
+		mapped.Columns = d.renamedColumns(sourceFK.Columns)
+		if changes := compareForeignKeys(&mapped, targetFK); len(changes) > 0 {
+			diff.ModifiedForeignKeys = append(diff.ModifiedForeignKeys, &ConstraintDiff{
+				Name:    targetFK.Name,
+				Source:  sourceFK,
+				Target:  targetFK,
+				Changes: changes,
+			})
+		}
+	}
+
+	// 查找删除的外键
+	for _, sourceFK := range d.source.ForeignKeys() {
+		if _, exists := targetFKs[sourceFK.Name]; !exists {
+			diff.RemovedForeignKeys = append(diff.RemovedForeignKeys, sourceFK)
+		}
+	}
+}
+
+// compareForeignKeys 比较两个外键的差异
+func compareForeignKeys(source, target *parser.Constraint) []string {
+	changes := make([]string, 0)
+
+	if !equalColumnNames(source.Columns, target.Columns) {
+		changes = append(changes, fmt.Sprintf("列从 (%s) 改为 (%s)",
+			strings.Join(source.Columns, ", "), strings.Join(target.Columns, ", ")))
+	}
+
+	if !strings.EqualFold(source.RefTable, target.RefTable) || !equalColumnNames(source.RefColumns, target.RefColumns) {
+		changes = append(changes, fmt.Sprintf("引用从 %s(%s) 改为 %s(%s)",
+			source.RefTable, strings.Join(source.RefColumns, ", "),
+			target.RefTable, strings.Join(target.RefColumns, ", ")))
+	}
+
+	if normalizeReferenceAction(source.OnDelete) != normalizeReferenceAction(target.OnDelete) {
+		changes = append(changes, fmt.Sprintf("ON DELETE 从 %s 改为 %s",
+			normalizeReferenceAction(source.OnDelete), normalizeReferenceAction(target.OnDelete)))
+	}
+
+	if normalizeReferenceAction(source.OnUpdate) != normalizeReferenceAction(target.OnUpdate) {
+		changes = append(changes, fmt.Sprintf("ON UPDATE 从 %s 改为 %s",
+			normalizeReferenceAction(source.OnUpdate), normalizeReferenceAction(target.OnUpdate)))
+	}
+
+	return changes
+}
+
+// normalizeReferenceAction 规范化外键动作（InnoDB 中未指定、NO ACTION 与 RESTRICT 等价）
+func normalizeReferenceAction(action string) string {
+	if action == "" || action == "NO ACTION" {
+		return "RESTRICT"
+	}
+	return action
+}
+
+// equalKeyParts 比较两个索引的列（列名不区分大小写，函数索引的表达式按规范化后的形式比较）
+func equalKeyParts(source, target *parser.Index, dialect parser.Dialect) bool {
+	if len(source.Columns) != len(target.Columns) {
+		return false
+	}
+	for i := range source.Columns {
+		sourceExpr, targetExpr := source.Expression(i), target.Expression(i)
+		if sourceExpr != "" || targetExpr != "" {
+			if parser.NormalizeExpression(sourceExpr, dialect) != parser.NormalizeExpression(targetExpr, dialect) {
+				return false
+			}
+		} else if !strings.EqualFold(source.Columns[i], target.Columns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyPartDirection 返回索引列的排序方向描述
+func keyPartDirection(idx *parser.Index, i int) string {
+	if idx.IsDescending(i) {
+		return "降序"
+	}
+	return "升序"
+}
+
+// indexAlgorithm 返回索引实际使用的算法（未指定时为方言的默认算法；全文、空间索引没有可选的算法）
+func indexAlgorithm(idx *parser.Index, dialect parser.Dialect) string {
+	if idx.Algorithm != "" {
+		return idx.Algorithm
+	}
+	switch dialect {
+	case parser.PostgreSQL:
+		return "btree"
+	case parser.SQLite:
+		return ""
+	}
+	if idx.Type == "INDEX" || idx.Type == "UNIQUE" {
+		return "BTREE"
+	}
+	return ""
+}
+
+// equalColumnNames 比较两个列名列表（列名不区分大小写）
+func equalColumnNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareColumns 比较两个列的差异（dialect 用于解析生成列的表达式）
+func compareColumns(source, target *parser.Column, dialect parser.Dialect) []string {
 	changes := make([]string, 0)
 
 	if source.Type != target.Type {
@@ -124,6 +442,42 @@ func compareColumns(source, target *parser.Column) []string {
 		changes = append(changes, fmt.Sprintf("长度从 %s 改为 %s", source.Length, target.Length))
 	}
 
+	if source.Type == target.Type && !equalStrings(source.Values, target.Values) {
+		changes = append(changes, compareMembers(source, target)...)
+	}
+
+	if source.Unsigned != target.Unsigned {
+		if target.Unsigned {
+			changes = append(changes, "添加了 UNSIGNED")
+		} else {
+			changes = append(changes, "移除了 UNSIGNED")
+		}
+	}
+
+	if source.Zerofill != target.Zerofill {
+		if target.Zerofill {
+			changes = append(changes, "添加了 ZEROFILL")
+		} else {
+			changes = append(changes, "移除了 ZEROFILL")
+		}
+	}
+
+	if !strings.EqualFold(source.Charset, target.Charset) {
+		changes = append(changes, fmt.Sprintf("字符集从 %s 改为 %s", orTableDefault(source.Charset), orTableDefault(target.Charset)))
+	}
+
+	if !strings.EqualFold(source.Collation, target.Collation) {
+		changes = append(changes, fmt.Sprintf("排序规则从 %s 改为 %s", orTableDefault(source.Collation), orTableDefault(target.Collation)))
+	}
+
+	if source.Binary != target.Binary {
+		if target.Binary {
+			changes = append(changes, "添加了 BINARY 属性")
+		} else {
+			changes = append(changes, "移除了 BINARY 属性")
+		}
+	}
+
 	if source.NotNull != target.NotNull {
 		if target.NotNull {
 			changes = append(changes, "添加了 NOT NULL 约束")
@@ -132,8 +486,19 @@ func compareColumns(source, target *parser.Column) []string {
 		}
 	}
 
-	if source.DefaultValue != target.DefaultValue {
-		changes = append(changes, fmt.Sprintf("默认值从 %s 改为 %s", source.DefaultValue, target.DefaultValue))
+	if !sameDefault(source, target) {
+		changes = append(changes, fmt.Sprintf("默认值从 %s 改为 %s", describeDefault(source), describeDefault(target)))
+	}
+
+	if source.OnUpdate != target.OnUpdate {
+		switch {
+		case source.OnUpdate == "":
+			changes = append(changes, fmt.Sprintf("添加了 ON UPDATE %s", target.OnUpdate))
+		case target.OnUpdate == "":
+			changes = append(changes, fmt.Sprintf("移除了 ON UPDATE %s", source.OnUpdate))
+		default:
+			changes = append(changes, fmt.Sprintf("ON UPDATE 从 %s 改为 %s", source.OnUpdate, target.OnUpdate))
+		}
 	}
 
 	if source.AutoInc != target.AutoInc {
@@ -144,6 +509,36 @@ func compareColumns(source, target *parser.Column) []string {
 		}
 	}
 
+	if source.AutoInc && target.AutoInc && source.Identity != target.Identity {
+		changes = append(changes, fmt.Sprintf("标识列生成方式从 %s 改为 %s", identityLabel(source), identityLabel(target)))
+	}
+
+	if source.Invisible != target.Invisible {
+		if target.Invisible {
+			changes = append(changes, "列改为不可见")
+		} else {
+			changes = append(changes, "列改为可见")
+		}
+	}
+
+	if source.SRID != target.SRID {
+		changes = append(changes, fmt.Sprintf("SRID 从 %s 改为 %s", orNone(source.SRID), orNone(target.SRID)))
+	}
+
+	switch {
+	case source.Generated == "" && target.Generated != "":
+		changes = append(changes, fmt.Sprintf("改为 %s 生成列 AS (%s)，已有数据将被表达式的计算结果替换", generatedKind(target), target.Generated))
+	case source.Generated != "" && target.Generated == "":
+		changes = append(changes, fmt.Sprintf("由 %s 生成列改为普通列", generatedKind(source)))
+	case source.Generated != "":
+		if parser.NormalizeExpression(source.Generated, dialect) != parser.NormalizeExpression(target.Generated, dialect) {
+			changes = append(changes, fmt.Sprintf("生成表达式从 (%s) 改为 (%s)", source.Generated, target.Generated))
+		}
+		if source.Stored != target.Stored {
+			changes = append(changes, fmt.Sprintf("生成列从 %s 改为 %s", generatedKind(source), generatedKind(target)))
+		}
+	}
+
 	if source.Comment != target.Comment {
 		changes = append(changes, fmt.Sprintf("注释从 '%s' 改为 '%s'", source.Comment, target.Comment))
 	}
@@ -151,71 +546,376 @@ func compareColumns(source, target *parser.Column) []string {
 	return changes
 }
 
+// compareMembers 比较 ENUM / SET 的成员列表
+// 在末尾追加成员是安全的变更；删除、重排或在中间插入成员会影响已有数据的取值或排序
+func compareMembers(source, target *parser.Column) []string {
+	if membersAppended(source, target) {
+		change := fmt.Sprintf("%s 末尾追加了成员 %s", target.Type, formatMembers(target.Values[len(source.Values):]))
+		if memberStorageSize(source) != memberStorageSize(target) {
+			change += "（存储长度改变，需要复制表）"
+		}
+		return []string{change}
+	}
+
+	var changes []string
+	kept := make(map[string]bool)
+	for _, value := range target.Values {
+		kept[value] = true
+	}
+	var removed, common []string
+	existed := make(map[string]bool)
+	for _, value := range source.Values {
+		existed[value] = true
+		if kept[value] {
+			common = append(common, value)
+		} else {
+			removed = append(removed, value)
+		}
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("%s 移除了成员 %s，已存储这些值的行将无法保留原值", target.Type, formatMembers(removed)))
+	}
+
+	// 保留的成员相对顺序改变，或新成员插入在已有成员之前，都会改变成员序号
+	var added []string
+	reordered := false
+	i := 0
+	for _, value := range target.Values {
+		if !existed[value] {
+			added = append(added, value)
+			if i < len(common) {
+				reordered = true
+			}
+			continue
+		}
+		if i >= len(common) || common[i] != value {
+			reordered = true
+		}
+		i++
+	}
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("%s 新增了成员 %s", target.Type, formatMembers(added)))
+	}
+	if reordered {
+		changes = append(changes, fmt.Sprintf("%s 已有成员的序号改变（成员重排或插入到中间），排序结果和按序号读写的数据会受影响", target.Type))
+	}
+	return changes
+}
+
+// membersAppended 判断 target 是否只是在 source 的 ENUM / SET 成员末尾追加了新成员
+func membersAppended(source, target *parser.Column) bool {
+	if source.Type != target.Type || source.Values == nil || len(target.Values) <= len(source.Values) {
+		return false
+	}
+	return equalStrings(source.Values, target.Values[:len(source.Values)])
+}
+
+// memberStorageSize 返回 ENUM / SET 列的存储字节数
+// ENUM 最多 255 个成员时占 1 字节，否则 2 字节；SET 按成员数占 1、2、3、4 或 8 字节
+func memberStorageSize(col *parser.Column) int {
+	n := len(col.Values)
+	if col.Type == "ENUM" {
+		if n <= 255 {
+			return 1
+		}
+		return 2
+	}
+	size := (n + 7) / 8
+	if size > 4 {
+		return 8
+	}
+	return size
+}
+
+// formatMembers 格式化成员列表，如 'a', 'b'
+func formatMembers(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteStringLiteral(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// equalStrings 比较两个字符串列表（区分大小写）
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lossyTypeChange 判断把 source 的类型改为 target 的类型是否可能截断或转换数据
+// ENUM / SET 只在末尾追加成员时不影响已有数据
+func lossyTypeChange(source, target *parser.Column) bool {
+	return formatColumnType(source) != formatColumnType(target) && !membersAppended(source, target)
+}
+
+// generatedKind 返回生成列的类型：STORED 或 VIRTUAL
+func generatedKind(col *parser.Column) string {
+	if col.Stored {
+		return "STORED"
+	}
+	return "VIRTUAL"
+}
+
+// isVirtualColumn 判断列是否为虚拟生成列
+// 虚拟生成列与存储生成列、普通列之间不能直接修改，只能删除后重新添加
+func isVirtualColumn(col *parser.Column) bool {
+	return col.Generated != "" && !col.Stored
+}
+
+// orTableDefault 字符集、排序规则为空时显示为表默认值
+func orTableDefault(value string) string {
+	if value == "" {
+		return "表默认值"
+	}
+	return value
+}
+
+// orNone 属性为空时显示为“无”
+func orNone(value string) string {
+	if value == "" {
+		return "无"
+	}
+	return value
+}
+
+// identityLabel 返回自增列的生成方式描述
+func identityLabel(col *parser.Column) string {
+	if col.Identity == "" {
+		return "AUTO_INCREMENT"
+	}
+	return "GENERATED " + col.Identity + " AS IDENTITY"
+}
+
 // GenerateDDL 根据差异生成 DDL 语句
+// 外键的删除最先执行（避免其依赖的索引、列无法修改），外键的添加最后执行（确保引用的列和索引已存在）
 func (d *Diff) GenerateDDL(tableName string) []string {
+	return d.generateDDL(tableName, false)
+}
+
+// generateDDL 生成 DDL 语句
+// rollback 为 true 时用于生成回滚语句：正向脚本中被注释的删除操作视为未执行，
+// 因此新增列、新增索引（即恢复被删除的对象）以注释形式输出，删除列、删除索引则正常执行
+func (d *Diff) generateDDL(tableName string, rollback bool) []string {
 	ddls := make([]string, 0)
+	dialect := d.dialect()
+
+	if rebuilder, ok := dialect.(tableRebuilder); ok && rebuilder.needsRebuild(d, rollback) {
+		return rebuilder.rebuildTable(d, tableName, rollback)
+	}
+
+	// 生成删除外键的 DDL（删除约束不会丢失数据，修改外键也需要先删除）
+	for _, fk := range d.RemovedForeignKeys {
+		ddls = append(ddls, dialect.dropForeignKey(tableName, fk))
+	}
+	for _, fkDiff := range d.ModifiedForeignKeys {
+		ddls = append(ddls, dialect.dropForeignKey(tableName, fkDiff.Source))
+	}
+
+	// 生成删除 CHECK 约束的 DDL（先于修改列执行，避免修改列时按旧约束检查数据）
+	for _, check := range d.RemovedChecks {
+		ddls = append(ddls, dialect.dropCheck(tableName, check))
+	}
+
+	// 生成重命名的 DDL（先于其他语句执行，后续语句均使用新名称）
+	for _, rename := range d.RenamedColumns {
+		statements := dialect.renameColumn(tableName, rename)
+		if rollback && len(rename.Changes) > 0 && lossyTypeChange(rename.Source, rename.Target) {
+			statements[0] = dataLossWarning(fmt.Sprintf("列 %s 改回 %s 可能截断或转换数据", rename.To, formatColumnType(rename.Target))) + statements[0]
+		}
+		ddls = append(ddls, statements...)
+	}
+	for _, rename := range d.RenamedIndexes {
+		ddls = append(ddls, dialect.renameIndex(tableName, rename)...)
+	}
+
+	// 生成修改表选项的 DDL（在修改列之前执行，转换字符集后再按目标定义修改各列）
+	if statements := dialect.alterTableOptions(d, tableName); len(statements) > 0 {
+		if option := d.tableOption("CHARSET"); rollback && option != nil && d.convertsCharset() {
+			statements[0] = dataLossWarning(fmt.Sprintf("表 %s 的字符集改回 %s 可能丢失无法表示的字符", tableName, option.Target)) + statements[0]
+		}
+		ddls = append(ddls, statements...)
+	}
+
+	pkDDL, handled, restoreDDL := dialect.primaryKey(d, tableName)
 
 	// 生成新增列的 DDL
 	for _, col := range d.AddedColumns {
-		ddl := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-			tableName,
-			col.Name,
-			formatColumnDefinition(col))
-		ddls = append(ddls, ddl)
+		if handled[col.Name] {
+			continue
+		}
+		statements := dialect.addColumn(tableName, col, d.placement(col.Name))
+		if rollback {
+			statements = commentOut(statements)
+		}
+		ddls = append(ddls, statements...)
+	}
+
+	// 生成主键变更的 DDL
+	if pkDDL != "" {
+		ddls = append(ddls, pkDDL)
 	}
 
 	// 生成修改列的 DDL
 	for _, colDiff := range d.ModifiedColumns {
-		ddl := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s",
-			tableName,
-			colDiff.Target.Name,
-			formatColumnDefinition(colDiff.Target))
+		if handled[colDiff.Name] {
+			continue
+		}
+		statements := dialect.modifyColumn(tableName, colDiff.Source, colDiff.Target, d.placement(colDiff.Name))
+		if len(statements) == 0 {
+			continue
+		}
+		if rollback && lossyTypeChange(colDiff.Source, colDiff.Target) {
+			statements[0] = dataLossWarning(fmt.Sprintf("列 %s 改回 %s 可能截断或转换数据", colDiff.Name, formatColumnType(colDiff.Target))) + statements[0]
+		}
+		if rollback && colDiff.Source.Generated != "" && colDiff.Target.Generated == "" {
+			statements[0] = dataLossWarning(fmt.Sprintf("列 %s 改为生成列时原有数据已被替换，改回普通列后无法恢复", colDiff.Name)) + statements[0]
+		}
+		ddls = append(ddls, statements...)
+	}
+
+	// 生成删除索引的 DDL（注释掉，先于删除列执行：删除列时包含该列的索引会随之删除或改变）
+	for _, idx := range d.RemovedIndexes {
+		ddl := "-- " + dialect.dropIndex(tableName, idx)
+		if rollback {
+			ddl = strings.TrimPrefix(ddl, "-- ")
+		}
 		ddls = append(ddls, ddl)
 	}
 
+	// 生成修改索引的 DDL（同样先于删除列执行，原索引包含被删除的列时会随之改变）
+	for _, idxDiff := range d.ModifiedIndexes {
+		ddls = append(ddls, dialect.modifyIndex(tableName, idxDiff)...)
+	}
+
 	// 生成删除列的 DDL（注释掉，因为删除操作比较危险）
 	for _, col := range d.RemovedColumns {
-		ddl := fmt.Sprintf("-- ALTER TABLE %s DROP COLUMN %s", tableName, col.Name)
+		ddl := "-- " + dialect.dropColumn(tableName, col.Name)
+		if rollback {
+			ddl = dataLossWarning(fmt.Sprintf("列 %s 中的数据将丢失", col.Name)) + dialect.dropColumn(tableName, col.Name)
+		}
 		ddls = append(ddls, ddl)
 	}
 
 	// 生成新增索引的 DDL
 	for _, idx := range d.AddedIndexes {
-		var ddl string
-		if idx.Type == "UNIQUE" {
-			ddl = fmt.Sprintf("ALTER TABLE %s ADD UNIQUE INDEX %s (%s)",
-				tableName, idx.Name, strings.Join(idx.Columns, ", "))
-		} else {
-			ddl = fmt.Sprintf("ALTER TABLE %s ADD INDEX %s (%s)",
-				tableName, idx.Name, strings.Join(idx.Columns, ", "))
+		ddl := dialect.addIndex(tableName, idx)
+		if rollback {
+			ddl = "-- " + ddl
 		}
 		ddls = append(ddls, ddl)
 	}
 
-	// 生成删除索引的 DDL（注释掉）
-	for _, idx := range d.RemovedIndexes {
-		ddl := fmt.Sprintf("-- ALTER TABLE %s DROP INDEX %s", tableName, idx.Name)
-		ddls = append(ddls, ddl)
+	// 主键调整完成、索引建好后，恢复自增属性
+	if restoreDDL != "" {
+		ddls = append(ddls, restoreDDL)
+	}
+
+	// 生成新增、修改 CHECK 约束的 DDL（在列调整完成后执行，确保约束引用的列已存在）
+	for _, check := range d.AddedChecks {
+		ddls = append(ddls, dialect.addCheck(tableName, check))
+	}
+	for _, checkDiff := range d.ModifiedChecks {
+		ddls = append(ddls, dialect.modifyCheck(tableName, checkDiff)...)
+	}
+
+	// 生成新增外键的 DDL
+	for _, fk := range d.AddedForeignKeys {
+		ddls = append(ddls, dialect.addForeignKey(tableName, fk))
+	}
+	for _, fkDiff := range d.ModifiedForeignKeys {
+		ddls = append(ddls, dialect.addForeignKey(tableName, fkDiff.Target))
+	}
+
+	// 生成分区变更的 DDL（在列和索引调整完成后执行，唯一索引必须包含分区键）
+	if d.Partitions != nil {
+		statements := dialect.alterPartitions(tableName, d.Partitions)
+		// 删除分区会删除其中的全部数据，与删除列一样注释掉；回滚时删除的是正向脚本新增的分区
+		if len(d.Partitions.Removed) > 0 && len(statements) > 0 {
+			if rollback {
+				reason := fmt.Sprintf("删除分区 %s 将删除其中的全部数据", partitionNames(d.Partitions.Removed))
+				statements[0] = dataLossWarning(reason) + statements[0]
+			} else {
+				statements[0] = "-- " + statements[0]
+			}
+		}
+		// 正向脚本中被注释的删除分区语句视为未执行，重新创建分区的语句同样注释掉；
+		// 执行了删除分区时，重新创建只能恢复分区定义，其中的数据已经丢失
+		if rollback && len(statements) > 0 {
+			if forward := comparePartitions(d.Partitions.Target, d.Partitions.Source, d.options.Dialect); forward != nil && len(forward.Removed) > 0 {
+				reason := fmt.Sprintf("分区 %s 中的数据已在删除分区时丢失，只能恢复分区定义", partitionNames(forward.Removed))
+				last := len(statements) - 1
+				statements[last] = dataLossWarning(reason) + commentOut(statements[last:])[0]
+			}
+		}
+		ddls = append(ddls, statements...)
 	}
 
 	return ddls
 }
 
+// commentOut 将语句注释掉（多行语句的每一行都加注释前缀）
+func commentOut(ddls []string) []string {
+	commented := make([]string, len(ddls))
+	for i, ddl := range ddls {
+		commented[i] = "-- " + strings.ReplaceAll(ddl, "\n", "\n-- ")
+	}
+	return commented
+}
+
+// formatColumnType 格式化列的数据类型（含长度或 ENUM / SET 成员，数组类型的长度位于 [] 之前）
+func formatColumnType(col *parser.Column) string {
+	if col.Values != nil {
+		// ENUM / SET 是 MySQL 特有的类型
+		values := make([]string, len(col.Values))
+		for i, value := range col.Values {
+			values[i] = quoteMySQLString(value)
+		}
+		return fmt.Sprintf("%s(%s)", col.Type, strings.Join(values, ","))
+	}
+	if col.Length != "" {
+		base := strings.TrimRight(col.Type, "[]")
+		return fmt.Sprintf("%s(%s)%s", base, col.Length, col.Type[len(base):])
+	}
+	return col.Type
+}
+
 // formatColumnDefinition 格式化列定义
 func formatColumnDefinition(col *parser.Column) string {
 	var parts []string
 
 	// 数据类型
-	typeStr := col.Type
-	if col.Length != "" {
-		typeStr += fmt.Sprintf("(%s)", col.Length)
-	}
-	parts = append(parts, typeStr)
+	parts = append(parts, formatColumnType(col))
 
-	// UNSIGNED
+	// UNSIGNED / ZEROFILL
 	if col.Unsigned {
 		parts = append(parts, "UNSIGNED")
 	}
+	if col.Zerofill {
+		parts = append(parts, "ZEROFILL")
+	}
+
+	// 字符集和排序规则
+	if col.Binary {
+		parts = append(parts, "BINARY")
+	}
+	if col.Charset != "" {
+		parts = append(parts, "CHARACTER SET "+col.Charset)
+	}
+	if col.Collation != "" {
+		parts = append(parts, "COLLATE "+col.Collation)
+	}
+
+	// 生成列（不能有默认值、ON UPDATE 和 AUTO_INCREMENT）
+	if col.Generated != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", col.Generated, generatedKind(col)))
+	}
 
 	// NOT NULL
 	if col.NotNull {
@@ -223,46 +923,187 @@ func formatColumnDefinition(col *parser.Column) string {
 	}
 
 	// DEFAULT
-	if col.DefaultValue != "" {
-		if needsQuotes(col.DefaultValue) {
-			parts = append(parts, fmt.Sprintf("DEFAULT '%s'", col.DefaultValue))
-		} else {
-			parts = append(parts, fmt.Sprintf("DEFAULT %s", col.DefaultValue))
-		}
+	if col.HasDefault() && col.Generated == "" {
+		parts = append(parts, "DEFAULT "+formatMySQLDefault(col))
+	}
+
+	// ON UPDATE
+	if col.OnUpdate != "" && col.Generated == "" {
+		parts = append(parts, "ON UPDATE "+col.OnUpdate)
 	}
 
 	// AUTO_INCREMENT
-	if col.AutoInc {
+	if col.AutoInc && col.Generated == "" {
 		parts = append(parts, "AUTO_INCREMENT")
 	}
 
+	// INVISIBLE / SRID
+	if col.Invisible {
+		parts = append(parts, "INVISIBLE")
+	}
+	if col.SRID != "" {
+		parts = append(parts, "SRID "+col.SRID)
+	}
+
 	// COMMENT
 	if col.Comment != "" {
-		parts = append(parts, fmt.Sprintf("COMMENT '%s'", col.Comment))
+		parts = append(parts, "COMMENT "+quoteMySQLString(col.Comment))
 	}
 
 	return strings.Join(parts, " ")
 }
 
-// needsQuotes 判断默认值是否需要引号
-func needsQuotes(value string) bool {
-	// 如果已经有引号，不需要再加
-	if strings.HasPrefix(value, "'") || strings.HasPrefix(value, "\"") {
-		return false
+// primaryKeyDDL 生成主键变更语句
+// MySQL 要求 AUTO_INCREMENT 列必须是某个索引的首列，因此：
+//   - 原主键中的自增列若不再是新主键的首列，在同一条语句中先去掉 AUTO_INCREMENT，
+//     待索引调整完成后再通过 restore 语句恢复
+//   - 作为新主键首列的自增列若是新增列或新获得自增属性，其 ADD/MODIFY 合并到主键语句中
+//
+// 返回值 handled 中的列已在主键语句中处理，不再单独生成 ADD/MODIFY 语句
+func (d *Diff) primaryKeyDDL(tableName string) (string, map[string]bool, string) {
+	handled := make(map[string]bool)
+	pk := d.PrimaryKey
+	if pk == nil {
+		return "", handled, ""
 	}
-	// NULL、CURRENT_TIMESTAMP 等关键字不需要引号
-	upper := strings.ToUpper(value)
-	keywords := []string{"NULL", "CURRENT_TIMESTAMP", "NOW()", "TRUE", "FALSE"}
-	for _, kw := range keywords {
-		if upper == kw || strings.HasPrefix(upper, kw) {
-			return false
+
+	var clauses []string
+	var restore string
+
+	if src := pk.SourceAutoInc; src != nil && containsColumn(pk.Source, src.Name) && !leadsWith(pk.Target, src.Name) {
+		final := d.finalColumn(src, pk.TargetAutoInc)
+		stripped := *final
+		stripped.AutoInc = false
+		handled[src.Name] = true
+		// 列位置随最后一条语句调整
+		if final.AutoInc {
+			clauses = append(clauses, fmt.Sprintf("MODIFY COLUMN %s %s", quoteMySQLIdent(stripped.Name), formatColumnDefinition(&stripped)))
+			restore = fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s%s",
+				quoteMySQLIdent(tableName), quoteMySQLIdent(final.Name), formatColumnDefinition(final), d.placement(final.Name))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("MODIFY COLUMN %s %s%s",
+				quoteMySQLIdent(stripped.Name), formatColumnDefinition(&stripped), d.placement(stripped.Name)))
 		}
 	}
-	// 数字不需要引号
-	if _, err := fmt.Sscanf(value, "%f", new(float64)); err == nil {
-		return false
+
+	if tgt := pk.TargetAutoInc; tgt != nil && leadsWith(pk.Target, tgt.Name) && !handled[tgt.Name] {
+		for _, col := range d.AddedColumns {
+			if col.Name == tgt.Name {
+				clauses = append(clauses, fmt.Sprintf("ADD COLUMN %s %s%s", quoteMySQLIdent(col.Name), formatColumnDefinition(col), d.placement(col.Name)))
+				handled[col.Name] = true
+			}
+		}
+		for _, colDiff := range d.ModifiedColumns {
+			if colDiff.Name == tgt.Name {
+				clauses = append(clauses, fmt.Sprintf("MODIFY COLUMN %s %s%s",
+					quoteMySQLIdent(colDiff.Name), formatColumnDefinition(colDiff.Target), d.placement(colDiff.Name)))
+				handled[colDiff.Name] = true
+			}
+		}
 	}
-	return true
+
+	if len(pk.Source) > 0 {
+		clauses = append(clauses, "DROP PRIMARY KEY")
+	}
+	if len(pk.Target) > 0 {
+		clauses = append(clauses, fmt.Sprintf("ADD PRIMARY KEY (%s)", quoteList(pk.Target, quoteMySQLIdent)))
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s", quoteMySQLIdent(tableName), strings.Join(clauses, ", ")), handled, restore
+}
+
+// finalColumn 返回源表列在迁移完成后的定义
+func (d *Diff) finalColumn(source, targetAutoInc *parser.Column) *parser.Column {
+	if targetAutoInc != nil && targetAutoInc.Name == source.Name {
+		return targetAutoInc
+	}
+	for _, colDiff := range d.ModifiedColumns {
+		if colDiff.Name == source.Name {
+			return colDiff.Target
+		}
+	}
+	return source
+}
+
+// containsColumn 判断列名列表中是否包含指定列
+func containsColumn(columns []string, name string) bool {
+	for _, col := range columns {
+		if strings.EqualFold(col, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// leadsWith 判断列名列表是否以指定列开头
+func leadsWith(columns []string, name string) bool {
+	return len(columns) > 0 && strings.EqualFold(columns[0], name)
+}
+
+// formatIndexDefinition 格式化索引定义（不含 ADD 前缀）
+func formatIndexDefinition(idx *parser.Index) string {
+	prefix := "INDEX"
+	switch idx.Type {
+	case "UNIQUE", "FULLTEXT", "SPATIAL":
+		prefix = idx.Type + " INDEX"
+	}
+	parts := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		// 函数索引的表达式保留原文
+		parts[i] = col
+		if !strings.HasPrefix(col, "(") {
+			parts[i] = quoteMySQLIdent(col)
+		}
+		if length := idx.PrefixLength(i); length > 0 {
+			parts[i] += fmt.Sprintf("(%d)", length)
+		}
+		if idx.IsDescending(i) {
+			parts[i] += " DESC"
+		}
+	}
+	def := fmt.Sprintf("%s %s (%s)", prefix, quoteMySQLIdent(idx.Name), strings.Join(parts, ", "))
+	if idx.Algorithm != "" {
+		def += " USING " + idx.Algorithm
+	}
+	if idx.Comment != "" {
+		def += " COMMENT " + quoteMySQLString(idx.Comment)
+	}
+	if idx.Parser != "" {
+		def += " WITH PARSER " + idx.Parser
+	}
+	if idx.Invisible {
+		def += " INVISIBLE"
+	}
+	return def
+}
+
+// formatIndexVisibility 返回索引的可见性关键字
+func formatIndexVisibility(idx *parser.Index) string {
+	if idx.Invisible {
+		return "INVISIBLE"
+	}
+	return "VISIBLE"
+}
+
+// formatForeignKey 格式化外键定义（不含 ADD 前缀）
+func formatForeignKey(fk *parser.Constraint) string {
+	def := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", quoteMySQLIdent(fk.Name),
+		quoteList(fk.Columns, quoteMySQLIdent), quoteMySQLIdent(fk.RefTable), quoteList(fk.RefColumns, quoteMySQLIdent))
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	return def
+}
+
+// formatMySQLDefault 格式化默认值：字符串字面量加引号，数字、关键字、函数调用和表达式保持原样
+func formatMySQLDefault(col *parser.Column) string {
+	if col.DefaultQuoted {
+		return quoteMySQLString(col.DefaultValue)
+	}
+	return col.DefaultValue
 }
 
 // HasChanges 判断是否有变更
@@ -270,8 +1111,20 @@ func (d *Diff) HasChanges() bool {
 	return len(d.AddedColumns) > 0 ||
 		len(d.RemovedColumns) > 0 ||
 		len(d.ModifiedColumns) > 0 ||
+		len(d.RenamedColumns) > 0 ||
 		len(d.AddedIndexes) > 0 ||
-		len(d.RemovedIndexes) > 0
+		len(d.RemovedIndexes) > 0 ||
+		len(d.ModifiedIndexes) > 0 ||
+		len(d.RenamedIndexes) > 0 ||
+		d.PrimaryKey != nil ||
+		len(d.AddedForeignKeys) > 0 ||
+		len(d.RemovedForeignKeys) > 0 ||
+		len(d.ModifiedForeignKeys) > 0 ||
+		len(d.AddedChecks) > 0 ||
+		len(d.RemovedChecks) > 0 ||
+		len(d.ModifiedChecks) > 0 ||
+		len(d.TableOptions) > 0 ||
+		d.Partitions != nil
 }
 
 // Summary 返回差异摘要
@@ -292,6 +1145,17 @@ func (d *Diff) Summary() string {
 		}
 	}
 
+	if len(d.RenamedColumns) > 0 {
+		summary.WriteString(fmt.Sprintf("重命名列: %d 个\n", len(d.RenamedColumns)))
+		for _, rename := range d.RenamedColumns {
+			line := fmt.Sprintf("  ~ %s -> %s", rename.From, rename.To)
+			if len(rename.Changes) > 0 {
+				line += ": " + strings.Join(rename.Changes, ", ")
+			}
+			summary.WriteString(line + "\n")
+		}
+	}
+
 	if len(d.RemovedColumns) > 0 {
 		summary.WriteString(fmt.Sprintf("删除列: %d 个\n", len(d.RemovedColumns)))
 		for _, col := range d.RemovedColumns {
@@ -306,6 +1170,20 @@ func (d *Diff) Summary() string {
 		}
 	}
 
+	if len(d.ModifiedIndexes) > 0 {
+		summary.WriteString(fmt.Sprintf("修改索引: %d 个\n", len(d.ModifiedIndexes)))
+		for _, idxDiff := range d.ModifiedIndexes {
+			summary.WriteString(fmt.Sprintf("  * %s: %s\n", idxDiff.Name, strings.Join(idxDiff.Changes, ", ")))
+		}
+	}
+
+	if len(d.RenamedIndexes) > 0 {
+		summary.WriteString(fmt.Sprintf("重命名索引: %d 个\n", len(d.RenamedIndexes)))
+		for _, rename := range d.RenamedIndexes {
+			summary.WriteString(fmt.Sprintf("  ~ %s -> %s\n", rename.From, rename.To))
+		}
+	}
+
 	if len(d.RemovedIndexes) > 0 {
 		summary.WriteString(fmt.Sprintf("删除索引: %d 个\n", len(d.RemovedIndexes)))
 		for _, idx := range d.RemovedIndexes {
@@ -313,6 +1191,86 @@ func (d *Diff) Summary() string {
 		}
 	}
 
+	if pk := d.PrimaryKey; pk != nil {
+		switch {
+		case len(pk.Source) == 0:
+			summary.WriteString(fmt.Sprintf("新增主键: (%s)\n", strings.Join(pk.Target, ", ")))
+		case len(pk.Target) == 0:
+			summary.WriteString(fmt.Sprintf("删除主键: (%s)\n", strings.Join(pk.Source, ", ")))
+		default:
+			summary.WriteString(fmt.Sprintf("修改主键: (%s) -> (%s)\n", strings.Join(pk.Source, ", "), strings.Join(pk.Target, ", ")))
+		}
+	}
+
+	if len(d.AddedForeignKeys) > 0 {
+		summary.WriteString(fmt.Sprintf("新增外键: %d 个\n", len(d.AddedForeignKeys)))
+		for _, fk := range d.AddedForeignKeys {
+			summary.WriteString(fmt.Sprintf("  + %s (%s) -> %s(%s)\n", fk.Name,
+				strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", ")))
+		}
+	}
+
+	if len(d.ModifiedForeignKeys) > 0 {
+		summary.WriteString(fmt.Sprintf("修改外键: %d 个\n", len(d.ModifiedForeignKeys)))
+		for _, fkDiff := range d.ModifiedForeignKeys {
+			summary.WriteString(fmt.Sprintf("  * %s: %s\n", fkDiff.Name, strings.Join(fkDiff.Changes, ", ")))
+		}
+	}
+
+	if len(d.RemovedForeignKeys) > 0 {
+		summary.WriteString(fmt.Sprintf("删除外键: %d 个\n", len(d.RemovedForeignKeys)))
+		for _, fk := range d.RemovedForeignKeys {
+			summary.WriteString(fmt.Sprintf("  - %s\n", fk.Name))
+		}
+	}
+
+	if len(d.AddedChecks) > 0 {
+		summary.WriteString(fmt.Sprintf("新增 CHECK 约束: %d 个\n", len(d.AddedChecks)))
+		for _, check := range d.AddedChecks {
+			summary.WriteString(fmt.Sprintf("  + %s CHECK (%s)\n", orUnnamed(check.Name), check.Definition))
+		}
+	}
+
+	if len(d.ModifiedChecks) > 0 {
+		summary.WriteString(fmt.Sprintf("修改 CHECK 约束: %d 个\n", len(d.ModifiedChecks)))
+		for _, checkDiff := range d.ModifiedChecks {
+			summary.WriteString(fmt.Sprintf("  * %s: %s\n", orUnnamed(checkDiff.Name), strings.Join(checkDiff.Changes, ", ")))
+		}
+	}
+
+	if len(d.RemovedChecks) > 0 {
+		summary.WriteString(fmt.Sprintf("删除 CHECK 约束: %d 个\n", len(d.RemovedChecks)))
+		for _, check := range d.RemovedChecks {
+			summary.WriteString(fmt.Sprintf("  - %s CHECK (%s)\n", orUnnamed(check.Name), check.Definition))
+		}
+	}
+
+	if len(d.TableOptions) > 0 {
+		summary.WriteString(fmt.Sprintf("修改表选项: %d 个\n", len(d.TableOptions)))
+		for _, option := range d.TableOptions {
+			summary.WriteString(fmt.Sprintf("  * %s\n", option.Change))
+		}
+	}
+
+	if d.Partitions != nil {
+		summary.WriteString(fmt.Sprintf("修改分区: %d 项\n", len(d.Partitions.Changes)))
+		for _, change := range d.Partitions.Changes {
+			summary.WriteString(fmt.Sprintf("  * %s\n", change))
+		}
+	}
+
+	if len(d.RenameCandidates) > 0 {
+		summary.WriteString(fmt.Sprintf("可能的重命名（未自动采用，可通过 --rename 指定）: %d 个\n", len(d.RenameCandidates)))
+		for _, candidate := range d.RenameCandidates {
+			kind := "列"
+			if candidate.Kind == RenameKindIndex {
+				kind = "索引"
+			}
+			summary.WriteString(fmt.Sprintf("  ? %s %s -> %s (置信度 %.0f%%)\n",
+				kind, candidate.From, candidate.To, candidate.Confidence*100))
+		}
+	}
+
 	if summary.Len() == 0 {
 		return "没有发现差异"
 	}
@@ -155,3 +155,544 @@ func TestDiffWithIndexes(t *testing.T) {
 		t.Error("DDL 应包含 ADD INDEX")
 	}
 }
+
+func TestDiffForeignKeys(t *testing.T) {
+	sourceSQL := `CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		user_id INT,
+		shop_id INT,
+		INDEX idx_shop (shop_id),
+		CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id),
+		CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops (id) ON DELETE RESTRICT
+	)`
+
+	targetSQL := `CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		user_id INT,
+		shop_id INT,
+		coupon_id INT,
+		INDEX idx_shop (shop_id),
+		INDEX idx_coupon (coupon_id),
+		CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+		CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops (id),
+		CONSTRAINT fk_coupon FOREIGN KEY (coupon_id) REFERENCES coupons (id)
+	)`
+
+	p := parser.NewParser()
+	source, _ := p.Parse(sourceSQL)
+	target, _ := p.Parse(targetSQL)
+
+	diff := NewDiffer(source, target).Compare()
+
+	if len(diff.AddedForeignKeys) != 1 || diff.AddedForeignKeys[0].Name != "fk_coupon" {
+		t.Errorf("期望新增外键 fk_coupon，实际 %v", diff.AddedForeignKeys)
+	}
+
+	// RESTRICT 与未指定等价，fk_shop 不应视为修改
+	if len(diff.ModifiedForeignKeys) != 1 || diff.ModifiedForeignKeys[0].Name != "fk_user" {
+		t.Errorf("期望修改外键 fk_user，实际 %v", diff.ModifiedForeignKeys)
+	}
+
+	ddls := diff.GenerateDDL("orders")
+	expected := []string{
+		"ALTER TABLE orders DROP FOREIGN KEY fk_user",
+		"ALTER TABLE orders ADD COLUMN coupon_id INT",
+		"ALTER TABLE orders ADD INDEX idx_coupon (coupon_id)",
+		"ALTER TABLE orders ADD CONSTRAINT fk_coupon FOREIGN KEY (coupon_id) REFERENCES coupons (id)",
+		"ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE",
+	}
+
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 顺序错误:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestDiffPrimaryKey(t *testing.T) {
+	p := parser.NewParser()
+
+	tests := []struct {
+		name     string
+		source   string
+		target   string
+		expected []string
+	}{
+		{
+			name:   "扩展复合主键",
+			source: `CREATE TABLE t (id INT NOT NULL, tenant_id INT NOT NULL, PRIMARY KEY (id))`,
+			target: `CREATE TABLE t (id INT NOT NULL, tenant_id INT NOT NULL, PRIMARY KEY (id, tenant_id))`,
+			expected: []string{
+				"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (id, tenant_id)",
+			},
+		},
+		{
+			name:   "新增主键",
+			source: `CREATE TABLE t (id INT NOT NULL, name VARCHAR(50))`,
+			target: `CREATE TABLE t (id INT NOT NULL, name VARCHAR(50), PRIMARY KEY (id))`,
+			expected: []string{
+				"ALTER TABLE t ADD PRIMARY KEY (id)",
+			},
+		},
+		{
+			name:   "删除主键",
+			source: `CREATE TABLE t (id INT NOT NULL, PRIMARY KEY (id))`,
+			target: `CREATE TABLE t (id INT NOT NULL)`,
+			expected: []string{
+				"ALTER TABLE t DROP PRIMARY KEY",
+			},
+		},
+		{
+			name:   "自增列不再是主键首列",
+			source: `CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, tenant_id INT NOT NULL, PRIMARY KEY (id))`,
+			target: `CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, tenant_id INT NOT NULL, PRIMARY KEY (tenant_id, id), KEY idx_id (id))`,
+			expected: []string{
+				"ALTER TABLE t MODIFY COLUMN id INT NOT NULL, DROP PRIMARY KEY, ADD PRIMARY KEY (tenant_id, id)",
+				"ALTER TABLE t ADD INDEX idx_id (id)",
+				"ALTER TABLE t MODIFY COLUMN id INT NOT NULL AUTO_INCREMENT",
+			},
+		},
+		{
+			name:   "新增自增主键列",
+			source: `CREATE TABLE t (code VARCHAR(20) NOT NULL, PRIMARY KEY (code))`,
+			target: `CREATE TABLE t (id BIGINT NOT NULL AUTO_INCREMENT, code VARCHAR(20) NOT NULL, PRIMARY KEY (id), UNIQUE KEY uk_code (code))`,
+			expected: []string{
+				"ALTER TABLE t ADD COLUMN id BIGINT NOT NULL AUTO_INCREMENT FIRST, DROP PRIMARY KEY, ADD PRIMARY KEY (id)",
+				"ALTER TABLE t ADD UNIQUE INDEX uk_code (code)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := p.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("解析源表失败: %v", err)
+			}
+			target, err := p.Parse(tt.target)
+			if err != nil {
+				t.Fatalf("解析目标表失败: %v", err)
+			}
+
+			diff := NewDiffer(source, target).Compare()
+			if diff.PrimaryKey == nil {
+				t.Fatal("应该检测到主键变更")
+			}
+
+			ddls := diff.GenerateDDL("t")
+			if strings.Join(ddls, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+			}
+		})
+	}
+}
+
+func TestDiffModifiedIndexes(t *testing.T) {
+	sourceSQL := `CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		user_id INT,
+		code VARCHAR(64),
+		created_at DATETIME,
+		INDEX idx_user (user_id),
+		INDEX idx_code (code(10)),
+		INDEX idx_created (created_at),
+		INDEX idx_same (user_id, created_at)
+	)`
+
+	targetSQL := `CREATE TABLE orders (
+		id INT PRIMARY KEY,
+		user_id INT,
+		code VARCHAR(64),
+		created_at DATETIME,
+		INDEX idx_user (user_id, created_at),
+		UNIQUE INDEX idx_code (code(16)),
+		INDEX idx_created (created_at) INVISIBLE,
+		KEY idx_same (USER_ID, created_at)
+	)`
+
+	p := parser.NewParser()
+	source, _ := p.Parse(sourceSQL)
+	target, _ := p.Parse(targetSQL)
+
+	diff := NewDiffer(source, target).Compare()
+
+	if len(diff.AddedIndexes) != 0 || len(diff.RemovedIndexes) != 0 {
+		t.Errorf("同名索引不应视为新增或删除")
+	}
+	if len(diff.ModifiedIndexes) != 3 {
+		t.Fatalf("期望修改 3 个索引，实际 %d 个", len(diff.ModifiedIndexes))
+	}
+	if len(diff.ModifiedIndexes[1].Changes) != 2 {
+		t.Errorf("idx_code 应有类型和前缀长度两处变更，实际 %v", diff.ModifiedIndexes[1].Changes)
+	}
+
+	ddls := diff.GenerateDDL("orders")
+	expected := []string{
+		"ALTER TABLE orders DROP INDEX idx_user, ADD INDEX idx_user (user_id, created_at)",
+		"ALTER TABLE orders DROP INDEX idx_code, ADD UNIQUE INDEX idx_code (code(16))",
+		"ALTER TABLE orders ALTER INDEX idx_created INVISIBLE",
+	}
+
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	// 索引改建到其他列、原列被删除时，应先修改索引再删除列
+	for _, dialect := range []parser.Dialect{parser.MySQL, parser.PostgreSQL, parser.SQLite} {
+		p := parser.NewParserWithDialect(dialect)
+		source, err := p.ParseScript("CREATE TABLE t (id INT PRIMARY KEY, a INT, b INT);\nCREATE INDEX idx ON t (a);")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		target, err := p.ParseScript("CREATE TABLE t (id INT PRIMARY KEY, b INT);\nCREATE INDEX idx ON t (b);")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		options := DefaultOptions()
+		options.Dialect = dialect
+		d := NewDatabaseDifferWithOptions(source, target, options)
+		if err := d.Verify(d.Compare()); err != nil {
+			t.Errorf("%s 验证失败: %v", dialect, err)
+		}
+	}
+}
+
+func TestDiffIndexOptions(t *testing.T) {
+	sourceSQL := `CREATE TABLE posts (
+		id INT PRIMARY KEY,
+		email VARCHAR(255),
+		title VARCHAR(100),
+		body TEXT,
+		created_at DATETIME,
+		INDEX idx_created (created_at),
+		INDEX idx_title (title) USING BTREE,
+		INDEX idx_hash (id) USING BTREE,
+		INDEX idx_comment (title(10)) COMMENT 'old',
+		UNIQUE KEY uk_email ((LOWER(email))),
+		FULLTEXT KEY ft_body (body)
+	)`
+
+	targetSQL := `CREATE TABLE posts (
+		id INT PRIMARY KEY,
+		email VARCHAR(255),
+		title VARCHAR(100),
+		body TEXT,
+		created_at DATETIME,
+		INDEX idx_created (created_at DESC),
+		INDEX idx_title (title),
+		INDEX idx_hash (id) USING HASH,
+		INDEX idx_comment (title(10)) COMMENT 'new',
+		UNIQUE KEY uk_email ((lower(email))),
+		FULLTEXT KEY ft_body (body) WITH PARSER ngram
+	)`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	var changes []string
+	for _, idx := range diff.ModifiedIndexes {
+		changes = append(changes, idx.Name+": "+strings.Join(idx.Changes, "; "))
+	}
+	expectedChanges := []string{
+		"idx_created: 列 created_at 从 升序 改为 降序",
+		"idx_hash: 索引算法从 BTREE 改为 HASH",
+		"idx_comment: 注释从 'old' 改为 'new'",
+		"ft_body: 全文解析器从 无 改为 ngram",
+	}
+	if strings.Join(changes, "\n") != strings.Join(expectedChanges, "\n") {
+		t.Errorf("索引变更不符合预期:\n%s", strings.Join(changes, "\n"))
+	}
+
+	ddls := diff.GenerateDDL("posts")
+	expected := []string{
+		"ALTER TABLE posts DROP INDEX idx_created, ADD INDEX idx_created (created_at DESC)",
+		"ALTER TABLE posts DROP INDEX idx_hash, ADD INDEX idx_hash (id) USING HASH",
+		"ALTER TABLE posts DROP INDEX idx_comment, ADD INDEX idx_comment (title(10)) COMMENT 'new'",
+		"ALTER TABLE posts DROP INDEX ft_body, ADD FULLTEXT INDEX ft_body (body) WITH PARSER ngram",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	roundTrip := compareSQL(t, FormatCreateTable(diff.target), targetSQL, DefaultOptions())
+	if roundTrip.HasChanges() {
+		t.Errorf("FormatCreateTable 的输出应与目标一致:\n%s", strings.Join(roundTrip.GenerateDDL("posts"), "\n"))
+	}
+}
+
+func TestPostgresIndexOptions(t *testing.T) {
+	diff := compareDialect(t, parser.PostgreSQL,
+		`CREATE TABLE events (id int, tags text[], created_at timestamp);`,
+		`CREATE TABLE events (id int, tags text[], created_at timestamp);
+CREATE INDEX idx_tags ON events USING gin (tags);
+CREATE INDEX idx_created ON events USING btree (created_at DESC);`)
+
+	ddls := diff.GenerateDDL()
+	expected := []string{
+		"CREATE INDEX idx_tags ON events USING gin (tags)",
+		"CREATE INDEX idx_created ON events USING btree (created_at DESC)",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestDiffColumnAttributes(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (
+		id INT NOT NULL,
+		name VARCHAR(50),
+		updated_at TIMESTAMP
+	) DEFAULT CHARSET=utf8mb4`
+
+	targetSQL := `CREATE TABLE t (
+		id INT UNSIGNED NOT NULL,
+		name VARCHAR(50) COLLATE utf8mb4_bin,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP INVISIBLE
+	) DEFAULT CHARSET=utf8mb4`
+
+	p := parser.NewParser()
+	source, _ := p.Parse(sourceSQL)
+	target, _ := p.Parse(targetSQL)
+
+	diff := NewDiffer(source, target).Compare()
+	if len(diff.ModifiedColumns) != 3 {
+		t.Fatalf("期望修改 3 列，实际 %d 列", len(diff.ModifiedColumns))
+	}
+
+	ddls := diff.GenerateDDL("t")
+	expected := []string{
+		"ALTER TABLE t MODIFY COLUMN id INT UNSIGNED NOT NULL",
+		"ALTER TABLE t MODIFY COLUMN name VARCHAR(50) COLLATE utf8mb4_bin",
+		"ALTER TABLE t MODIFY COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP INVISIBLE",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestDiffEnumMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		target  string
+		changes []string
+	}{
+		{
+			name:    "末尾追加",
+			source:  "ENUM('a','b')",
+			target:  "ENUM('a','b','c, d')",
+			changes: []string{"ENUM 末尾追加了成员 'c, d'"},
+		},
+		{
+			name:    "SET 追加后超过 8 个成员",
+			source:  "SET('a','b','c','d','e','f','g','h')",
+			target:  "SET('a','b','c','d','e','f','g','h','i')",
+			changes: []string{"SET 末尾追加了成员 'i'（存储长度改变，需要复制表）"},
+		},
+		{
+			name:   "中间插入",
+			source: "ENUM('a','c')",
+			target: "ENUM('a','b','c')",
+			changes: []string{
+				"ENUM 新增了成员 'b'",
+				"ENUM 已有成员的序号改变（成员重排或插入到中间），排序结果和按序号读写的数据会受影响",
+			},
+		},
+		{
+			name:   "删除并重排",
+			source: "ENUM('a','b','c')",
+			target: "ENUM('c','a')",
+			changes: []string{
+				"ENUM 移除了成员 'b'，已存储这些值的行将无法保留原值",
+				"ENUM 已有成员的序号改变（成员重排或插入到中间），排序结果和按序号读写的数据会受影响",
+			},
+		},
+		{
+			name:    "大小写不同",
+			source:  "ENUM('a')",
+			target:  "ENUM('A')",
+			changes: []string{"ENUM 移除了成员 'a'，已存储这些值的行将无法保留原值", "ENUM 新增了成员 'A'"},
+		},
+	}
+
+	p := parser.NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, _ := p.Parse("CREATE TABLE t (a " + tt.source + ")")
+			target, _ := p.Parse("CREATE TABLE t (a " + tt.target + ")")
+			diff := NewDiffer(source, target).Compare()
+			if len(diff.ModifiedColumns) != 1 {
+				t.Fatalf("期望修改 1 列，实际 %d 列", len(diff.ModifiedColumns))
+			}
+			if changes := diff.ModifiedColumns[0].Changes; strings.Join(changes, "\n") != strings.Join(tt.changes, "\n") {
+				t.Errorf("变更描述不符合预期:\n%s", strings.Join(changes, "\n"))
+			}
+			if ddls := diff.GenerateDDL("t"); len(ddls) != 1 || ddls[0] != "ALTER TABLE t MODIFY COLUMN a "+tt.target {
+				t.Errorf("DDL 不符合预期: %v", ddls)
+			}
+		})
+	}
+}
+
+func TestDiffGeneratedColumns(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (
+		a INT,
+		b INT,
+		total INT AS (a + b) STORED,
+		doubled INT AS (a * 2) VIRTUAL,
+		label VARCHAR(20) AS (concat('#', a)),
+		note VARCHAR(20),
+		code INT
+	)`
+	targetSQL := "CREATE TABLE t (\n" +
+		"		a INT,\n" +
+		"		b INT,\n" +
+		"		total INT GENERATED ALWAYS AS ((`a` + `b` + 1)) STORED,\n" +
+		"		doubled INT AS (a * 2) STORED,\n" +
+		"		label VARCHAR(20),\n" +
+		"		note VARCHAR(20) AS (upper(label)) STORED,\n" +
+		"		code INT\n" +
+		"	)"
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	expectedChanges := map[string]string{
+		"total":   "生成表达式从 (a + b) 改为 ((`a` + `b` + 1))",
+		"doubled": "生成列从 VIRTUAL 改为 STORED",
+		"label":   "由 VIRTUAL 生成列改为普通列",
+		"note":    "改为 STORED 生成列 AS (upper(label))，已有数据将被表达式的计算结果替换",
+	}
+	if len(diff.ModifiedColumns) != len(expectedChanges) {
+		t.Fatalf("期望修改 %d 列，实际为 %d 列", len(expectedChanges), len(diff.ModifiedColumns))
+	}
+	for _, colDiff := range diff.ModifiedColumns {
+		if change := strings.Join(colDiff.Changes, ", "); change != expectedChanges[colDiff.Name] {
+			t.Errorf("列 %s 的变更描述不正确: %s", colDiff.Name, change)
+		}
+	}
+
+	// 虚拟生成列与其他列之间不能直接 MODIFY，删除后在原位置重新添加
+	expected := []string{
+		"ALTER TABLE t MODIFY COLUMN total INT GENERATED ALWAYS AS ((`a` + `b` + 1)) STORED",
+		"ALTER TABLE t DROP COLUMN doubled, ADD COLUMN doubled INT GENERATED ALWAYS AS (a * 2) STORED AFTER total",
+		"ALTER TABLE t DROP COLUMN label, ADD COLUMN label VARCHAR(20) AFTER doubled",
+		"ALTER TABLE t MODIFY COLUMN note VARCHAR(20) GENERATED ALWAYS AS (upper(label)) STORED",
+	}
+	if ddls := diff.GenerateDDL("t"); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+	if risk := diff.Risk(); risk != RiskDestructive {
+		t.Errorf("普通列改为生成列的风险应为 destructive，实际为 %s", risk)
+	}
+
+	rollback := diff.GenerateRollbackDDL("t")
+	if last := rollback[len(rollback)-1]; !strings.HasPrefix(last, DataLossWarning+"列 note 改为生成列时原有数据已被替换") {
+		t.Errorf("note 的回滚语句应带有数据丢失警告:\n%s", strings.Join(rollback, "\n"))
+	}
+
+	if diff := compareSQL(t, sourceSQL, strings.ReplaceAll(sourceSQL, "a + b", "(`A`+`b`)"), DefaultOptions()); diff.HasChanges() {
+		t.Errorf("等价的生成表达式不应有差异:\n%s", diff.Summary())
+	}
+}
+
+func TestPostgresGeneratedColumns(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a integer, total integer GENERATED ALWAYS AS (a * 2) STORED, legacy integer GENERATED ALWAYS AS (a + 1) STORED, b integer);`
+	targetSQL := `CREATE TABLE t (a integer, total integer GENERATED ALWAYS AS (a * 3) STORED, legacy integer, b integer GENERATED ALWAYS AS (a - 1) STORED);`
+
+	expected := []string{
+		"ALTER TABLE t ALTER COLUMN total SET EXPRESSION AS (a * 3)",
+		"ALTER TABLE t ALTER COLUMN legacy DROP EXPRESSION",
+		"ALTER TABLE t DROP COLUMN b, ADD COLUMN b INTEGER GENERATED ALWAYS AS (a - 1) STORED",
+	}
+	if ddls := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL).GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestSQLiteGeneratedColumns(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, a INTEGER, doubled INTEGER GENERATED ALWAYS AS (a * 2) VIRTUAL);`
+	targetSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, a INTEGER, doubled INTEGER GENERATED ALWAYS AS (a * 3) STORED, label TEXT AS ('#' || a));`
+
+	ddls := compareDialect(t, parser.SQLite, sourceSQL, targetSQL).GenerateDDL()
+	if joined := strings.Join(ddls, "\n"); !strings.Contains(joined, "INSERT INTO new_t (id, a) SELECT id, a FROM t") {
+		t.Errorf("重建表时不应复制生成列:\n%s", joined)
+	}
+
+	db := openSQLite(t, sourceSQL)
+	execSQLite(t, db, []string{"INSERT INTO t (a) VALUES (2)"})
+	execSQLite(t, db, ddls)
+	var doubled int
+	var label string
+	if err := db.QueryRow("SELECT doubled, label FROM t").Scan(&doubled, &label); err != nil || doubled != 6 || label != "#2" {
+		t.Errorf("迁移后生成列的值不正确: %d %s（%v）", doubled, label, err)
+	}
+	if migrated := compareDialect(t, parser.SQLite, dumpSQLite(t, db), targetSQL); migrated.HasChanges() {
+		t.Errorf("迁移后的结构与目标不一致:\n%s", strings.Join(migrated.GenerateDDL(), "\n"))
+	}
+}
+
+func TestFormatMySQLDefault(t *testing.T) {
+	tests := []struct {
+		value    string // 建表语句中 DEFAULT 之后的原文
+		expected string
+	}{
+		{"NULL", "NULL"},
+		{"null", "NULL"},
+		{"'NULL'", "'NULL'"},
+		{"'null'", "'null'"},
+		{"CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{"'CURRENT_TIMESTAMP'", "'CURRENT_TIMESTAMP'"},
+		{"current_timestamp(3)", "CURRENT_TIMESTAMP(3)"},
+		{"(UUID())", "(UUID())"},
+		{"'ABC(1)'", "'ABC(1)'"},
+		{"-1.5", "-1.5"},
+		{"'007'", "'007'"},
+		{"'1e3'", "'1e3'"},
+		{"0x1F", "0x1F"},
+		{"b'0101'", "b'0101'"},
+		{"_utf8mb4'abc'", "'abc'"},
+		{"'2024-01-01'", "'2024-01-01'"},
+		{"'it''s'", "'it''s'"},
+		{`'C:\\temp'`, `'C:\\temp'`},
+	}
+
+	for _, tt := range tests {
+		table, err := parser.NewParser().Parse("CREATE TABLE t (c VARCHAR(20) DEFAULT " + tt.value + ")")
+		if err != nil {
+			t.Fatalf("解析默认值 %s 失败: %v", tt.value, err)
+		}
+		if got := formatMySQLDefault(table.Columns[0]); got != tt.expected {
+			t.Errorf("默认值 %s: 期望 %s，实际 %s", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestMySQLQuotesIdentifiers(t *testing.T) {
+	p := parser.NewParser()
+	source, err := p.ParseScript("CREATE TABLE `order` (id INT PRIMARY KEY, `key` INT, `my col` INT, KEY `index` (`key`));")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	target, err := p.ParseScript("CREATE TABLE `order` (id INT PRIMARY KEY, `desc` VARCHAR(10), `my col` BIGINT, " +
+		"`group` INT, KEY `index` (`desc`, `my col`), CONSTRAINT `check` FOREIGN KEY (`group`) REFERENCES `order` (id));")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	options := DefaultOptions()
+	options.IgnoreOrder = true
+	d := NewDatabaseDifferWithOptions(source, target, options)
+	diff := d.Compare()
+
+	expected := []string{
+		"ALTER TABLE `order` ADD COLUMN `desc` VARCHAR(10)",
+		"ALTER TABLE `order` ADD COLUMN `group` INT",
+		"ALTER TABLE `order` MODIFY COLUMN `my col` BIGINT",
+		"ALTER TABLE `order` DROP INDEX `index`, ADD INDEX `index` (`desc`, `my col`)",
+		"-- ALTER TABLE `order` DROP COLUMN `key`",
+		"ALTER TABLE `order` ADD CONSTRAINT `check` FOREIGN KEY (`group`) REFERENCES `order` (id)",
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("保留字和含空格的标识符应加反引号:\n%s", strings.Join(ddls, "\n"))
+	}
+	if err := d.Verify(diff); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+}
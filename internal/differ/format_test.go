@@ -0,0 +1,168 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// roundTripScripts 覆盖各方言解析器支持的语法，用于 解析→输出→解析 的往返测试
+var roundTripScripts = []struct {
+	name    string
+	dialect parser.Dialect
+	sql     string
+}{
+	{"MySQL 列属性", parser.MySQL, "CREATE TABLE `users` (\n" +
+		"  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `code` int(6) unsigned zerofill DEFAULT NULL,\n" +
+		"  `name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT '用户名',\n" +
+		"  `tag` varchar(20) binary,\n" +
+		"  `status` enum('active','disabled') NOT NULL DEFAULT 'active',\n" +
+		"  `flags` set('a','b','c'),\n" +
+		"  `balance` decimal(10,2) NOT NULL DEFAULT 0.00,\n" +
+		"  `geo` point NOT NULL SRID 4326,\n" +
+		"  `secret` varchar(64) INVISIBLE,\n" +
+		"  `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),\n" +
+		"  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC COMMENT='用户表';"},
+	{"MySQL 索引与约束", parser.MySQL, `
+CREATE TABLE teams (id INT PRIMARY KEY, name VARCHAR(50));
+CREATE TABLE posts (
+	id INT NOT NULL,
+	team_id INT,
+	email VARCHAR(255),
+	title VARCHAR(200),
+	body TEXT,
+	price INT,
+	total INT GENERATED ALWAYS AS (price * 2) STORED,
+	slug VARCHAR(200) AS (LOWER(title)) VIRTUAL,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_email (email),
+	KEY idx_title (title(20) DESC) USING BTREE COMMENT '标题',
+	KEY idx_hash USING HASH (team_id),
+	KEY idx_func ((LOWER(email))) INVISIBLE,
+	FULLTEXT KEY ft_body (body) WITH PARSER ngram,
+	CONSTRAINT fk_team FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE ON UPDATE SET NULL,
+	CONSTRAINT chk_price CHECK (price >= 0),
+	CHECK (total < 1000) NOT ENFORCED
+);
+CREATE INDEX idx_team_title ON posts (team_id, title);`},
+	{"MySQL 分区", parser.MySQL, `
+CREATE TABLE logs (
+	id INT NOT NULL,
+	created DATE NOT NULL,
+	region INT NOT NULL
+) ENGINE=InnoDB
+PARTITION BY RANGE (YEAR(created)) (
+	PARTITION p2023 VALUES LESS THAN (2024) COMMENT = 'old',
+	PARTITION p2024 VALUES LESS THAN (2025),
+	PARTITION pmax VALUES LESS THAN MAXVALUE
+);
+CREATE TABLE regions (id INT NOT NULL, region INT NOT NULL)
+PARTITION BY LIST (region) (
+	PARTITION east VALUES IN (1, 2),
+	PARTITION west VALUES IN (3)
+);
+CREATE TABLE buckets (id INT NOT NULL) PARTITION BY KEY ALGORITHM=2 (id) PARTITIONS 4;`},
+	{"MySQL 保留字标识符", parser.MySQL, "CREATE TABLE `order` (\n" +
+		"  `key` INT NOT NULL,\n" +
+		"  `primary` INT,\n" +
+		"  `index` INT,\n" +
+		"  `check` INT,\n" +
+		"  `my col` VARCHAR(20) DEFAULT 'x',\n" +
+		"  `back``tick` INT,\n" +
+		"  PRIMARY KEY (`key`),\n" +
+		"  UNIQUE KEY `unique` (`primary`, `my col`(10)),\n" +
+		"  KEY `desc` (`index` DESC),\n" +
+		"  CONSTRAINT `references` FOREIGN KEY (`check`) REFERENCES `order` (`key`),\n" +
+		"  CONSTRAINT `select` CHECK (`check` > 0)\n" +
+		");"},
+	{"PostgreSQL", parser.PostgreSQL, `
+CREATE TABLE accounts (
+	id BIGSERIAL PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE,
+	nickname VARCHAR(50) COLLATE "C",
+	tags TEXT[],
+	score NUMERIC(8, 2) DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	doubled INT GENERATED ALWAYS AS (score * 2) STORED,
+	CHECK (score >= 0)
+);
+CREATE TABLE orders (
+	id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	account_id BIGINT REFERENCES accounts (id) ON DELETE CASCADE,
+	placed_at DATE NOT NULL
+) PARTITION BY RANGE (placed_at);
+CREATE INDEX idx_tags ON accounts USING gin (tags);
+CREATE INDEX idx_created ON accounts (created_at DESC);
+CREATE TABLE "order" ("key" INT, "user" TEXT, "My Col" INT, "group" INT REFERENCES "order" ("key"), UNIQUE ("key"));
+CREATE INDEX "select" ON "order" ("My Col", "user");
+COMMENT ON TABLE accounts IS '账户';
+COMMENT ON COLUMN accounts.email IS '邮箱';`},
+	{"SQLite", parser.SQLite, `
+CREATE TABLE authors (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL COLLATE NOCASE);
+CREATE TABLE books (
+	id INTEGER NOT NULL,
+	author_id INTEGER REFERENCES authors (id) ON DELETE SET NULL,
+	title TEXT NOT NULL DEFAULT 'untitled',
+	isbn TEXT UNIQUE,
+	pages INTEGER CHECK (pages > 0),
+	PRIMARY KEY (id)
+) WITHOUT ROWID;
+CREATE INDEX idx_books_title ON books (title DESC);
+CREATE TABLE "order" ("key" INTEGER PRIMARY KEY, "index" TEXT, "my col" INTEGER, "check" INTEGER CHECK ("check" > 0), UNIQUE ("index"));
+CREATE INDEX "primary" ON "order" ("my col");`},
+}
+
+func TestFormatSchemaRoundTrip(t *testing.T) {
+	for _, tt := range roundTripScripts {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewParserWithDialect(tt.dialect)
+			original, err := p.ParseScript(tt.sql)
+			if err != nil {
+				t.Fatalf("解析原始脚本失败: %v", err)
+			}
+
+			formatted := FormatSchema(original, tt.dialect)
+			reparsed, err := p.ParseScript(formatted)
+			if err != nil {
+				t.Fatalf("解析输出的脚本失败: %v\n%s", err, formatted)
+			}
+
+			options := DefaultOptions()
+			options.Dialect = tt.dialect
+			if diff := NewDatabaseDifferWithOptions(original, reparsed, options).Compare(); diff.HasChanges() {
+				t.Errorf("往返后结构发生变化:\n%s\n%s", strings.Join(diff.GenerateDDL(), "\n"), formatted)
+			}
+			if again := FormatSchema(reparsed, tt.dialect); again != formatted {
+				t.Errorf("输出不稳定，第二次输出:\n%s\n第一次输出:\n%s", again, formatted)
+			}
+		})
+	}
+}
+
+func TestFormatSchema(t *testing.T) {
+	db, err := parser.NewParser().ParseScript("create table `t` (`id` int not null auto_increment primary key, `name` varchar(20) default 'a') engine=innodb;\n" +
+		"create table u (id int);")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	expected := "CREATE TABLE t (\n" +
+		"  id INT NOT NULL AUTO_INCREMENT,\n" +
+		"  name VARCHAR(20) DEFAULT 'a',\n" +
+		"  PRIMARY KEY (id)\n" +
+		") ENGINE=innodb;\n" +
+		"\n" +
+		"CREATE TABLE u (\n" +
+		"  id INT\n" +
+		");\n"
+	if got := FormatSchema(db, parser.MySQL); got != expected {
+		t.Errorf("输出不符合预期:\n%s", got)
+	}
+	if got := FormatSchema(parser.NewDatabaseSchema(), parser.MySQL); got != "" {
+		t.Errorf("空结构应输出空字符串，实际 %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// Options 比对选项
+type Options struct {
+	DetectRenames bool           // 是否根据定义和位置自动识别列、索引的重命名
+	RenameHints   []RenameHint   // 明确指定的重命名（优先于自动识别）
+	IgnoreOrder   bool           // 是否忽略列顺序（不识别顺序调整，也不生成 AFTER/FIRST）
+	Dialect       parser.Dialect // 生成 DDL 使用的方言（为空表示 MySQL）
+
+	CompareAutoIncrement bool // 是否比较 AUTO_INCREMENT 计数器（计数器随数据变化，默认忽略）
+}
+
+// RenameHint 重命名提示
+type RenameHint struct {
+	Table string // 表名（为空表示适用于所有表）
+	From  string // 原列名或索引名
+	To    string // 新列名或索引名
+}
+
+// DefaultOptions 返回默认比对选项
+func DefaultOptions() Options {
+	return Options{
+		DetectRenames: true,
+	}
+}
+
+// ParseRenameHint 解析重命名提示，格式为 [表名.]原名称=新名称
+func ParseRenameHint(s string) (RenameHint, error) {
+	from, to, ok := strings.Cut(s, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" {
+		return RenameHint{}, fmt.Errorf("无效的重命名提示 %q，格式应为 [表名.]原名称=新名称", s)
+	}
+
+	hint := RenameHint{From: from, To: to}
+	if table, name, ok := strings.Cut(from, "."); ok {
+		if table == "" || name == "" {
+			return RenameHint{}, fmt.Errorf("无效的重命名提示 %q，格式应为 [表名.]原名称=新名称", s)
+		}
+		hint.Table, hint.From = table, name
+	}
+	return hint, nil
+}
+
+// appliesTo 判断提示是否适用于指定的表
+func (h RenameHint) appliesTo(tableNames ...string) bool {
+	if h.Table == "" {
+		return true
+	}
+	for _, name := range tableNames {
+		if strings.EqualFold(h.Table, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package differ
+
+import "testing"
+
+func TestParseRenameHint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected RenameHint
+		wantErr  bool
+	}{
+		{input: "name=full_name", expected: RenameHint{From: "name", To: "full_name"}},
+		{input: "users.name = full_name", expected: RenameHint{Table: "users", From: "name", To: "full_name"}},
+		{input: "name", wantErr: true},
+		{input: "name=", wantErr: true},
+		{input: ".name=full_name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		hint, err := ParseRenameHint(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q 应该解析失败", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q 解析失败: %v", tt.input, err)
+			continue
+		}
+		if hint != tt.expected {
+			t.Errorf("%q 解析结果错误: %+v", tt.input, hint)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// compareColumnOrder 识别列顺序调整，并计算新增列、移动列在 DDL 中的位置子句
+//
+// 保留列中按源表顺序构成最长递增子序列的列保持不动，其余列视为移动。
+// 生成的语句分两步还原目标顺序：
+//  1. 新增列按目标顺序放在最近一个不移动的前序列之后（位于所有保留列之后的新增列直接追加）
+//  2. 移动列按目标顺序放在其在目标表中的前一列之后
+func (d *Differ) compareColumnOrder(diff *Diff) {
+	diff.placements = make(map[string]string)
+	if d.options.IgnoreOrder || !dialectFor(d.options.Dialect).supportsColumnOrder() {
+		return
+	}
+
+	added := make(map[string]bool)
+	for _, col := range diff.AddedColumns {
+		added[strings.ToLower(col.Name)] = true
+	}
+
+	// 保留列在源表中的序号，按目标顺序排列
+	sourceColumns := make(map[string]*parser.Column)
+	sourceOrder := make(map[string]int)
+	for i, col := range d.source.Columns {
+		name := strings.ToLower(d.renamedColumn(col.Name))
+		sourceColumns[name] = col
+		sourceOrder[name] = i
+	}
+	var kept []*parser.Column
+	var order []int
+	for _, col := range d.target.Columns {
+		name := strings.ToLower(col.Name)
+		if i, ok := sourceOrder[name]; ok && !added[name] {
+			kept = append(kept, col)
+			order = append(order, i)
+		}
+	}
+
+	moved := make(map[string]bool)
+	fixed := longestIncreasing(order)
+	for i, col := range kept {
+		if !fixed[i] {
+			moved[strings.ToLower(col.Name)] = true
+		}
+	}
+
+	lastKept := -1
+	for i, col := range d.target.Columns {
+		if !added[strings.ToLower(col.Name)] {
+			lastKept = i
+		}
+	}
+
+	for i, col := range d.target.Columns {
+		name := strings.ToLower(col.Name)
+		switch {
+		case moved[name]:
+			after := ""
+			if i > 0 {
+				after = d.target.Columns[i-1].Name
+			}
+			diff.placements[name] = formatPlacement(after)
+			d.markMoved(diff, sourceColumns[name], col, after)
+		case added[name] && i < lastKept:
+			after := ""
+			for j := i - 1; j >= 0; j-- {
+				if !moved[strings.ToLower(d.target.Columns[j].Name)] {
+					after = d.target.Columns[j].Name
+					break
+				}
+			}
+			diff.placements[name] = formatPlacement(after)
+		}
+	}
+
+	// 需要删除后重新添加的生成列（见 mysqlDialect.modifyColumn）同样需要位置子句，否则会被追加到最后
+	for _, colDiff := range diff.ModifiedColumns {
+		name := strings.ToLower(colDiff.Name)
+		if _, ok := diff.placements[name]; ok || isVirtualColumn(colDiff.Source) == isVirtualColumn(colDiff.Target) {
+			continue
+		}
+		for i, col := range d.target.Columns {
+			if col == colDiff.Target {
+				after := ""
+				if i > 0 {
+					after = d.target.Columns[i-1].Name
+				}
+				diff.placements[name] = formatPlacement(after)
+			}
+		}
+	}
+
+	// 移动列需按目标顺序执行
+	targetOrder := make(map[string]int)
+	for i, col := range d.target.Columns {
+		targetOrder[col.Name] = i
+	}
+	sort.SliceStable(diff.ModifiedColumns, func(i, j int) bool {
+		return targetOrder[diff.ModifiedColumns[i].Name] < targetOrder[diff.ModifiedColumns[j].Name]
+	})
+}
+
+// markMoved 将列标记为位置调整（没有其他修改的列新增一条 ColumnDiff）
+func (d *Differ) markMoved(diff *Diff, source, target *parser.Column, after string) {
+	change := "位置调整到最前"
+	if after != "" {
+		change = fmt.Sprintf("位置调整到 %s 之后", after)
+	}
+
+	for _, colDiff := range diff.ModifiedColumns {
+		if colDiff.Name == target.Name {
+			colDiff.Moved = true
+			colDiff.Changes = append(colDiff.Changes, change)
+			return
+		}
+	}
+
+	diff.ModifiedColumns = append(diff.ModifiedColumns, &ColumnDiff{
+		Name:    target.Name,
+		Source:  source,
+		Target:  target,
+		Changes: []string{change},
+		Moved:   true,
+	})
+}
+
+// formatPlacement 生成列位置子句
+func formatPlacement(after string) string {
+	if after == "" {
+		return "FIRST"
+	}
+	return "AFTER " + quoteMySQLIdent(after)
+}
+
+// placement 返回列在 ADD/MODIFY 语句中的位置子句（带前导空格，无需调整时为空）
+func (d *Diff) placement(name string) string {
+	if p, ok := d.placements[strings.ToLower(name)]; ok {
+		return " " + p
+	}
+	return ""
+}
+
+// longestIncreasing 求最长递增子序列，返回属于该子序列的下标集合
+func longestIncreasing(values []int) map[int]bool {
+	n := len(values)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i := 0; i < n; i++ {
+		length[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if values[j] < values[i] && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if best < 0 || length[i] > length[best] {
+			best = i
+		}
+	}
+
+	result := make(map[int]bool)
+	for i := best; i >= 0; i = prev[i] {
+		result[i] = true
+	}
+	return result
+}
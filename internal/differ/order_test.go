@@ -0,0 +1,65 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnOrder(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INT, a INT, b INT, c INT, d INT)`
+	targetSQL := `CREATE TABLE t (id INT, c INT, a INT, x INT, b INT, d INT, y INT)`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	if len(diff.ModifiedColumns) != 1 || diff.ModifiedColumns[0].Name != "c" || !diff.ModifiedColumns[0].Moved {
+		t.Fatalf("应只识别 c 的位置调整，实际 %+v", diff.ModifiedColumns)
+	}
+	if !strings.Contains(diff.Summary(), "c: 位置调整到 id 之后") {
+		t.Errorf("摘要中应包含位置调整:\n%s", diff.Summary())
+	}
+
+	// 依次执行后列顺序为 id, c, a, x, b, d, y
+	ddls := diff.GenerateDDL("t")
+	expected := []string{
+		"ALTER TABLE t ADD COLUMN x INT AFTER a",
+		"ALTER TABLE t ADD COLUMN y INT",
+		"ALTER TABLE t MODIFY COLUMN c INT AFTER id",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestColumnOrderFirst(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a INT, b VARCHAR(10), id INT)`
+	targetSQL := `CREATE TABLE t (id INT, a INT, b VARCHAR(20))`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	ddls := diff.GenerateDDL("t")
+	expected := []string{
+		"ALTER TABLE t MODIFY COLUMN id INT FIRST",
+		"ALTER TABLE t MODIFY COLUMN b VARCHAR(20)",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestIgnoreColumnOrder(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a INT, b INT)`
+	targetSQL := `CREATE TABLE t (x INT, b INT, a INT)`
+
+	options := DefaultOptions()
+	options.IgnoreOrder = true
+	diff := compareSQL(t, sourceSQL, targetSQL, options)
+
+	if len(diff.ModifiedColumns) != 0 {
+		t.Errorf("忽略列顺序时不应识别位置调整，实际 %+v", diff.ModifiedColumns)
+	}
+
+	ddls := diff.GenerateDDL("t")
+	if len(ddls) != 1 || ddls[0] != "ALTER TABLE t ADD COLUMN x INT" {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
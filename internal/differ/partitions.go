@@ -0,0 +1,385 @@
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// PartitionDiff 分区的差异详情
+// 分区方式、分区键或子分区方式变化（含改为分区表、取消分区）时需要重新分区；
+// 否则 RANGE / LIST 分区逐个比对分区定义，HASH / KEY 分区只比较分区数量
+type PartitionDiff struct {
+	Source      *parser.Partitioning   // 变更前的分区方案（未分区时为空）
+	Target      *parser.Partitioning   // 变更后的分区方案（未分区时为空）
+	Repartition bool                   // 是否需要重新分区（PARTITION BY / REMOVE PARTITIONING）
+	Removed     []*parser.Partition    // 删除的分区（分区中的数据会被删除）
+	Reorganized []*PartitionReorganize // 重组的分区（数据迁移到新的分区中）
+	Added       []*parser.Partition    // 新增的分区
+	Count       int                    // HASH / KEY 分区数量的变化（正数为增加，负数为合并）
+	Changes     []string               // 变更描述
+}
+
+// PartitionReorganize 将一组分区重组为另一组分区（REORGANIZE PARTITION ... INTO）
+type PartitionReorganize struct {
+	Source []*parser.Partition
+	Target []*parser.Partition
+}
+
+// comparePartitions 比对分区方案，没有差异时返回 nil
+func comparePartitions(source, target *parser.Partitioning, dialect parser.Dialect) *PartitionDiff {
+	if source == nil && target == nil {
+		return nil
+	}
+
+	diff := &PartitionDiff{Source: source, Target: target}
+	switch {
+	case source == nil:
+		diff.Repartition = true
+		diff.Changes = append(diff.Changes, fmt.Sprintf("改为分区表，按 %s 分区", formatPartitionKey(target)))
+	case target == nil:
+		diff.Repartition = true
+		diff.Changes = append(diff.Changes, "取消分区")
+	case !samePartitionKey(source, target, dialect):
+		diff.Repartition = true
+		diff.Changes = append(diff.Changes, fmt.Sprintf("分区方式从 %s 改为 %s", formatPartitionKey(source), formatPartitionKey(target)))
+	case isHashPartitioning(target):
+		if count := target.PartitionCount() - source.PartitionCount(); count != 0 {
+			diff.Count = count
+			diff.Changes = append(diff.Changes, fmt.Sprintf("分区数量从 %d 改为 %d", source.PartitionCount(), target.PartitionCount()))
+		}
+	case strings.HasPrefix(target.Type, "LIST"):
+		diff.compareListPartitions(dialect)
+	default:
+		diff.compareRangePartitions(dialect)
+	}
+
+	if len(diff.Changes) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// compareRangePartitions 比对 RANGE 分区
+// 分区按边界有序，以两侧相同的分区（最长公共子序列）为锚点，逐段处理锚点之间的分区：
+// 只有源分区的删除；只有目标分区的位于末尾时新增，否则与下一个锚点分区一起重组（如拆分 MAXVALUE 分区）；
+// 两侧都有分区的重组，重组前后的上界不同时并入后面的锚点分区，直到两侧覆盖的范围相同
+func (d *PartitionDiff) compareRangePartitions(dialect parser.Dialect) {
+	source, target := d.Source.Partitions, d.Target.Partitions
+
+	// lcs[i][j] 为 source[i:] 与 target[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(source)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(target)+1)
+	}
+	for i := len(source) - 1; i >= 0; i-- {
+		for j := len(target) - 1; j >= 0; j-- {
+			switch {
+			case samePartition(source[i], target[j], dialect):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var runSource, runTarget []*parser.Partition
+	i, j := 0, 0
+	for i < len(source) || j < len(target) {
+		anchored := i < len(source) && j < len(target) && samePartition(source[i], target[j], dialect)
+		switch {
+		case anchored && len(runSource) == 0 && len(runTarget) > 0:
+			// 新分区位于已有分区之前，只能从该分区中拆分出来
+			d.reorganize(append(runSource, source[i]), append(runTarget, target[j]))
+			runSource, runTarget = nil, nil
+		case anchored && len(runSource) > 0 && len(runTarget) > 0 && !sameRangeBound(runSource, runTarget, dialect):
+			// 重组不能改变覆盖的范围（如 p1 的上界从 200 改为 150），与锚点分区一起重组
+			runSource, runTarget = append(runSource, source[i]), append(runTarget, target[j])
+		case anchored:
+			d.flushRangeRun(runSource, runTarget, dialect)
+			runSource, runTarget = nil, nil
+		case j < len(target) && (i == len(source) || lcs[i][j+1] >= lcs[i+1][j]):
+			runTarget = append(runTarget, target[j])
+			j++
+			continue
+		default:
+			runSource = append(runSource, source[i])
+			i++
+			continue
+		}
+		i++
+		j++
+	}
+
+	d.flushRangeRun(runSource, runTarget, dialect)
+}
+
+// flushRangeRun 处理两个锚点之间（或末尾）的一段分区：源为空时新增，目标为空时删除，否则重组
+// MySQL 要求重组前后覆盖的范围相同，只有末尾的分区可以扩大范围；
+// 末尾的范围缩小时（如将 MAXVALUE 分区改为 LESS THAN (300)）无法重组，只能删除原分区（数据丢失）再新增
+func (d *PartitionDiff) flushRangeRun(source, target []*parser.Partition, dialect parser.Dialect) {
+	switch {
+	case len(source) == 0:
+		d.add(target)
+	case len(target) == 0:
+		d.remove(source)
+	case sameRangeBound(source, target, dialect):
+		d.reorganize(source, target)
+	default:
+		if cmp, ok := compareRangeBounds(source[len(source)-1], target[len(target)-1], dialect); ok && cmp < 0 {
+			d.reorganize(source, target)
+			return
+		}
+		d.remove(source)
+		d.add(target)
+	}
+}
+
+// sameRangeBound 判断两组相邻分区的上界（最后一个分区的边界）是否相同
+func sameRangeBound(source, target []*parser.Partition, dialect parser.Dialect) bool {
+	cmp, ok := compareRangeBounds(source[len(source)-1], target[len(target)-1], dialect)
+	return ok && cmp == 0
+}
+
+// compareRangeBounds 比较两个 RANGE 分区的上界，ok 为 false 表示无法比较（如边界为表达式或多列取值）
+func compareRangeBounds(source, target *parser.Partition, dialect parser.Dialect) (cmp int, ok bool) {
+	sourceBound := parser.NormalizeExpression(source.Values, dialect)
+	targetBound := parser.NormalizeExpression(target.Values, dialect)
+	if sourceBound == targetBound {
+		return 0, true
+	}
+	sourceMax, targetMax := strings.EqualFold(source.Values, "LESS THAN MAXVALUE"), strings.EqualFold(target.Values, "LESS THAN MAXVALUE")
+	switch {
+	case sourceMax:
+		return 1, true
+	case targetMax:
+		return -1, true
+	}
+
+	sourceValue, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(source.Values, "LESS THAN ("), ")"), 64)
+	if err != nil {
+		return 0, false
+	}
+	targetValue, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(target.Values, "LESS THAN ("), ")"), 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case sourceValue < targetValue:
+		return -1, true
+	case sourceValue > targetValue:
+		return 1, true
+	}
+	return 0, true
+}
+
+// compareListPartitions 比对 LIST 分区（分区之间没有顺序，按分区名匹配）
+// 修改了取值列表的分区一起重组，以便取值在分区之间移动
+func (d *PartitionDiff) compareListPartitions(dialect parser.Dialect) {
+	var removed, added, reorganizeSource, reorganizeTarget []*parser.Partition
+	for _, target := range d.Target.Partitions {
+		source := findPartition(d.Source.Partitions, target.Name)
+		switch {
+		case source == nil:
+			added = append(added, target)
+		case !samePartition(source, target, dialect):
+			reorganizeSource = append(reorganizeSource, source)
+			reorganizeTarget = append(reorganizeTarget, target)
+		}
+	}
+	for _, source := range d.Source.Partitions {
+		if findPartition(d.Target.Partitions, source.Name) == nil {
+			removed = append(removed, source)
+		}
+	}
+
+	d.remove(removed)
+	if len(reorganizeSource) > 0 {
+		d.reorganize(reorganizeSource, reorganizeTarget)
+	}
+	d.add(added)
+}
+
+// remove 记录删除的分区
+func (d *PartitionDiff) remove(partitions []*parser.Partition) {
+	for _, p := range partitions {
+		d.Removed = append(d.Removed, p)
+		d.Changes = append(d.Changes, fmt.Sprintf("删除分区 %s，分区中的数据将被删除", p.Name))
+	}
+}
+
+// reorganize 记录重组的分区
+func (d *PartitionDiff) reorganize(source, target []*parser.Partition) {
+	d.Reorganized = append(d.Reorganized, &PartitionReorganize{Source: source, Target: target})
+	d.Changes = append(d.Changes, fmt.Sprintf("重组分区 %s 为 %s", partitionNames(source), partitionNames(target)))
+}
+
+// add 记录新增的分区
+func (d *PartitionDiff) add(partitions []*parser.Partition) {
+	for _, p := range partitions {
+		d.Added = append(d.Added, p)
+		d.Changes = append(d.Changes, fmt.Sprintf("新增分区 %s", p.Name))
+	}
+}
+
+// samePartitionKey 判断分区方式、分区键和子分区方式是否相同
+func samePartitionKey(source, target *parser.Partitioning, dialect parser.Dialect) bool {
+	return strings.EqualFold(source.Type, target.Type) &&
+		source.Algorithm == target.Algorithm &&
+		parser.NormalizeExpression(source.Expression, dialect) == parser.NormalizeExpression(target.Expression, dialect) &&
+		parser.NormalizeExpression(source.Subpartition, dialect) == parser.NormalizeExpression(target.Subpartition, dialect)
+}
+
+// samePartition 判断两个分区的名称、边界和选项是否相同
+func samePartition(source, target *parser.Partition, dialect parser.Dialect) bool {
+	if !strings.EqualFold(source.Name, target.Name) ||
+		parser.NormalizeExpression(source.Values, dialect) != parser.NormalizeExpression(target.Values, dialect) ||
+		len(source.Options) != len(target.Options) {
+		return false
+	}
+	for name, value := range source.Options {
+		if targetValue, ok := target.Options[name]; !ok || targetValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// findPartition 按名称查找分区（不区分大小写）
+func findPartition(partitions []*parser.Partition, name string) *parser.Partition {
+	for _, p := range partitions {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// isHashPartitioning 判断是否为 HASH / KEY 分区（按分区数量分布数据）
+func isHashPartitioning(p *parser.Partitioning) bool {
+	return strings.HasSuffix(p.Type, "HASH") || strings.HasSuffix(p.Type, "KEY")
+}
+
+// partitionNames 返回以逗号分隔的分区名
+func partitionNames(partitions []*parser.Partition) string {
+	names := make([]string, len(partitions))
+	for i, p := range partitions {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// quotedPartitionNames 返回以逗号分隔、可直接用于 MySQL 语句的分区名
+func quotedPartitionNames(partitions []*parser.Partition) string {
+	names := make([]string, len(partitions))
+	for i, p := range partitions {
+		names[i] = quoteMySQLIdent(p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatPartitionKey 格式化分区方式和分区键，如 RANGE COLUMNS (created_at)、KEY ALGORITHM=2 (id)
+func formatPartitionKey(p *parser.Partitioning) string {
+	key := p.Type
+	if p.Algorithm != "" {
+		key += " ALGORITHM=" + p.Algorithm
+	}
+	return fmt.Sprintf("%s (%s)", key, p.Expression)
+}
+
+// formatPartitioning 格式化 MySQL 的分区子句（PARTITION BY ...）
+func formatPartitioning(p *parser.Partitioning) string {
+	clause := "PARTITION BY " + formatPartitionKey(p)
+	if p.Count > 0 {
+		clause += fmt.Sprintf(" PARTITIONS %d", p.Count)
+	}
+	if p.Subpartition != "" {
+		clause += " SUBPARTITION BY " + p.Subpartition
+	}
+	if len(p.Partitions) > 0 {
+		clause += "\n(" + formatPartitionList(p.Partitions) + ")"
+	}
+	return clause
+}
+
+// formatPartitionList 格式化以逗号分隔的分区定义
+func formatPartitionList(partitions []*parser.Partition) string {
+	defs := make([]string, len(partitions))
+	for i, p := range partitions {
+		defs[i] = formatPartition(p)
+	}
+	return strings.Join(defs, ",\n ")
+}
+
+// formatPartition 格式化单个分区定义，如 PARTITION p0 VALUES LESS THAN (1000) COMMENT='x'
+func formatPartition(p *parser.Partition) string {
+	def := "PARTITION " + quoteMySQLIdent(p.Name)
+	if p.Values != "" {
+		def += " VALUES " + p.Values
+	}
+
+	var names []string
+	for name := range p.Options {
+		if name != "SUBPARTITIONS" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := p.Options[name]
+		switch name {
+		case "COMMENT", "DATA DIRECTORY", "INDEX DIRECTORY":
+			value = quoteMySQLString(value)
+		}
+		def += fmt.Sprintf(" %s=%s", name, value)
+	}
+
+	if sub, ok := p.Options["SUBPARTITIONS"]; ok {
+		def += fmt.Sprintf(" (%s)", sub)
+	}
+	return def
+}
+
+// alterPartitions 生成 MySQL 的分区变更语句，顺序为删除、重组、新增（删除分区的语句位于首位，由调用方注释掉）
+func (mysqlDialect) alterPartitions(table string, d *PartitionDiff) []string {
+	var ddls []string
+	table = quoteMySQLIdent(table)
+	switch {
+	case d.Repartition && d.Target == nil:
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING", table))
+	case d.Repartition:
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s %s", table, formatPartitioning(d.Target)))
+	case d.Count > 0:
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s ADD PARTITION PARTITIONS %d", table, d.Count))
+	case d.Count < 0:
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s COALESCE PARTITION %d", table, -d.Count))
+	}
+
+	if len(d.Removed) > 0 {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", table, quotedPartitionNames(d.Removed)))
+	}
+	for _, r := range d.Reorganized {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (\n %s)",
+			table, quotedPartitionNames(r.Source), formatPartitionList(r.Target)))
+	}
+	if len(d.Added) > 0 {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s ADD PARTITION (\n %s)", table, formatPartitionList(d.Added)))
+	}
+	return ddls
+}
+
+// alterPartitions 生成 PostgreSQL 的分区变更语句
+// 分区表的分区方式不能修改，分区本身是独立的表（CREATE TABLE ... PARTITION OF），需要手动处理
+func (p postgresDialect) alterPartitions(table string, d *PartitionDiff) []string {
+	return []string{fmt.Sprintf("-- PostgreSQL 不支持修改表 %s 的分区方式（%s），需要重建表", p.quote(table), strings.Join(d.Changes, "，"))}
+}
+
+// alterPartitions SQLite 不支持分区表
+func (s sqliteDialect) alterPartitions(table string, d *PartitionDiff) []string {
+	return nil
+}
@@ -0,0 +1,193 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestDiffRangePartitions(t *testing.T) {
+	table := `CREATE TABLE metrics (id BIGINT NOT NULL, created_at DATE NOT NULL, PRIMARY KEY (id, created_at)) `
+	sourceSQL := table + `PARTITION BY RANGE COLUMNS(created_at) (
+		PARTITION p2023 VALUES LESS THAN ('2024-01-01'),
+		PARTITION p2024 VALUES LESS THAN ('2025-01-01'),
+		PARTITION pmax VALUES LESS THAN MAXVALUE)`
+	targetSQL := table + `PARTITION BY RANGE COLUMNS(created_at) (
+		PARTITION p2024 VALUES LESS THAN ('2025-01-01'),
+		PARTITION p2025 VALUES LESS THAN ('2026-01-01'),
+		PARTITION pmax VALUES LESS THAN MAXVALUE)`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	expected := []string{
+		"-- ALTER TABLE metrics DROP PARTITION p2023",
+		"ALTER TABLE metrics REORGANIZE PARTITION pmax INTO (\n" +
+			" PARTITION p2025 VALUES LESS THAN ('2026-01-01'),\n" +
+			" PARTITION pmax VALUES LESS THAN MAXVALUE)",
+	}
+	if ddls := diff.GenerateDDL("metrics"); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+	if risk := diff.Risk(); risk != RiskDestructive {
+		t.Errorf("删除分区的风险应为 destructive，实际为 %s", risk)
+	}
+
+	// 验证时被注释的删除分区语句按已执行处理
+	source, err := parser.NewParser().ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v", err)
+	}
+	target, err := parser.NewParser().ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v", err)
+	}
+	d := NewDatabaseDiffer(source, target)
+	if err := d.Verify(d.Compare()); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+
+	// 正向脚本中删除分区的语句被注释掉，重新创建分区的语句同样注释掉
+	expectedRollback := []string{
+		DataLossWarning + "删除分区 p2025 将删除其中的全部数据\n" +
+			"ALTER TABLE metrics DROP PARTITION p2025",
+		DataLossWarning + "分区 p2023 中的数据已在删除分区时丢失，只能恢复分区定义\n" +
+			"-- ALTER TABLE metrics REORGANIZE PARTITION p2024 INTO (\n" +
+			"--  PARTITION p2023 VALUES LESS THAN ('2024-01-01'),\n" +
+			"--  PARTITION p2024 VALUES LESS THAN ('2025-01-01'))",
+	}
+	if ddls := diff.GenerateRollbackDDL("metrics"); strings.Join(ddls, "\n") != strings.Join(expectedRollback, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	kinds := make(map[ChangeKind]int)
+	for _, c := range diff.Changes("metrics") {
+		kinds[c.Kind]++
+	}
+	if kinds[ChangeDropPartition] != 1 || kinds[ChangeReorganizePartition] != 1 || len(kinds) != 2 {
+		t.Errorf("变更类型不正确: %v", kinds)
+	}
+}
+
+func TestDiffPartitionChanges(t *testing.T) {
+	table := `CREATE TABLE t (id INT NOT NULL, region INT NOT NULL) `
+	tests := []struct {
+		name     string
+		source   string
+		target   string
+		expected []string
+	}{
+		{
+			name:     "在末尾新增分区",
+			source:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100))",
+			target:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (200), PARTITION p2 VALUES LESS THAN (300))",
+			expected: []string{"ALTER TABLE t ADD PARTITION (\n PARTITION p1 VALUES LESS THAN (200),\n PARTITION p2 VALUES LESS THAN (300))"},
+		},
+		{
+			name:     "修改分区边界",
+			source:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (200))",
+			target:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (150), PARTITION p1 VALUES LESS THAN (200))",
+			expected: []string{"ALTER TABLE t REORGANIZE PARTITION p0, p1 INTO (\n PARTITION p0 VALUES LESS THAN (150),\n PARTITION p1 VALUES LESS THAN (200))"},
+		},
+		{
+			name:     "缩小中间分区的边界",
+			source:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (200), PARTITION p2 VALUES LESS THAN (300))",
+			target:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (150), PARTITION p2 VALUES LESS THAN (300))",
+			expected: []string{"ALTER TABLE t REORGANIZE PARTITION p1, p2 INTO (\n PARTITION p1 VALUES LESS THAN (150),\n PARTITION p2 VALUES LESS THAN (300))"},
+		},
+		{
+			name:     "扩大末尾分区的边界",
+			source:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (200))",
+			target:   "PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN (300))",
+			expected: []string{"ALTER TABLE t REORGANIZE PARTITION p1 INTO (\n PARTITION p1 VALUES LESS THAN (300))"},
+		},
+		{
+			name:   "MAXVALUE 分区改为有界分区",
+			source: "PARTITION BY RANGE (id) (PARTITION p1 VALUES LESS THAN (200), PARTITION pmax VALUES LESS THAN MAXVALUE)",
+			target: "PARTITION BY RANGE (id) (PARTITION p1 VALUES LESS THAN (200), PARTITION p2 VALUES LESS THAN (300))",
+			expected: []string{
+				"-- ALTER TABLE t DROP PARTITION pmax",
+				"ALTER TABLE t ADD PARTITION (\n PARTITION p2 VALUES LESS THAN (300))",
+			},
+		},
+		{
+			name:   "LIST 分区",
+			source: "PARTITION BY LIST (region) (PARTITION east VALUES IN (1, 2), PARTITION west VALUES IN (3, 4), PARTITION old VALUES IN (9))",
+			target: "PARTITION BY LIST (region) (PARTITION north VALUES IN (5), PARTITION east VALUES IN (1), PARTITION west VALUES IN (2, 3, 4))",
+			expected: []string{
+				"-- ALTER TABLE t DROP PARTITION old",
+				"ALTER TABLE t REORGANIZE PARTITION east, west INTO (\n PARTITION east VALUES IN (1),\n PARTITION west VALUES IN (2, 3, 4))",
+				"ALTER TABLE t ADD PARTITION (\n PARTITION north VALUES IN (5))",
+			},
+		},
+		{
+			name:     "增加 HASH 分区数量",
+			source:   "PARTITION BY HASH (id) PARTITIONS 4",
+			target:   "PARTITION BY HASH (id) PARTITIONS 8",
+			expected: []string{"ALTER TABLE t ADD PARTITION PARTITIONS 4"},
+		},
+		{
+			name:     "减少 KEY 分区数量",
+			source:   "PARTITION BY KEY (id) PARTITIONS 8",
+			target:   "PARTITION BY KEY (id) PARTITIONS 2",
+			expected: []string{"ALTER TABLE t COALESCE PARTITION 6"},
+		},
+		{
+			name:     "改为分区表",
+			source:   "",
+			target:   "PARTITION BY HASH (id) PARTITIONS 4",
+			expected: []string{"ALTER TABLE t PARTITION BY HASH (id) PARTITIONS 4"},
+		},
+		{
+			name:     "修改分区方式",
+			source:   "PARTITION BY HASH (id) PARTITIONS 4",
+			target:   "PARTITION BY LIST (region) (PARTITION a VALUES IN (1))",
+			expected: []string{"ALTER TABLE t PARTITION BY LIST (region)\n(PARTITION a VALUES IN (1))"},
+		},
+		{
+			name:     "取消分区",
+			source:   "PARTITION BY HASH (id) PARTITIONS 4",
+			target:   "",
+			expected: []string{"ALTER TABLE t REMOVE PARTITIONING"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := compareSQL(t, table+tt.source, table+tt.target, DefaultOptions())
+			if ddls := diff.GenerateDDL("t"); strings.Join(ddls, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+			}
+		})
+	}
+}
+
+func TestDiffPartitionsUnchanged(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INT NOT NULL) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100) COMMENT 'old', PARTITION p1 VALUES LESS THAN MAXVALUE)`
+	targetSQL := "CREATE TABLE `t` (`id` int NOT NULL) ENGINE=InnoDB\n" +
+		"/*!50100 PARTITION BY RANGE (`id`)\n" +
+		"(PARTITION p0 VALUES LESS THAN (100) COMMENT = 'old' ENGINE = InnoDB,\n" +
+		" PARTITION p1 VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */"
+
+	if diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions()); diff.HasChanges() {
+		t.Errorf("不应有差异:\n%s", diff.Summary())
+	}
+
+	diff := compareSQL(t, sourceSQL, sourceSQL, DefaultOptions())
+	if ddl := FormatCreateTable(diff.source); !strings.HasSuffix(ddl, "\nPARTITION BY RANGE (id)\n(PARTITION p0 VALUES LESS THAN (100) COMMENT='old',\n PARTITION p1 VALUES LESS THAN MAXVALUE)") {
+		t.Errorf("建表语句缺少分区定义:\n%s", ddl)
+	}
+}
+
+func TestPostgresPartitions(t *testing.T) {
+	sourceSQL := `CREATE TABLE events (id integer, created_at date) PARTITION BY RANGE (created_at);`
+	targetSQL := `CREATE TABLE events (id integer, created_at date) PARTITION BY HASH (id);`
+
+	ddls := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL).GenerateDDL()
+	if len(ddls) != 1 || !strings.HasPrefix(ddls[0], "-- PostgreSQL 不支持修改表 events 的分区方式") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+	if diff := compareDialect(t, parser.PostgreSQL, sourceSQL, sourceSQL); diff.HasChanges() {
+		t.Errorf("不应有差异:\n%s", diff.Summary())
+	}
+}
@@ -0,0 +1,371 @@
+package differ
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// postgresDialect PostgreSQL 方言
+// 列的修改拆分为 ALTER COLUMN ... TYPE / SET NOT NULL / SET DEFAULT 等子句，
+// 索引通过独立的 CREATE INDEX / DROP INDEX 语句维护，注释通过 COMMENT ON 语句设置
+type postgresDialect struct{}
+
+// postgresPlainIdent 无需加引号的 PostgreSQL 标识符（未加引号的标识符会被转为小写）
+var postgresPlainIdent = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// postgresReservedWords 作为标识符时必须加引号的 PostgreSQL 保留字
+var postgresReservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true,
+	"asc": true, "asymmetric": true, "both": true, "case": true, "cast": true, "check": true,
+	"collate": true, "column": true, "constraint": true, "create": true, "current_catalog": true,
+	"current_date": true, "current_role": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "deferrable": true, "desc": true, "distinct": true,
+	"do": true, "else": true, "end": true, "except": true, "false": true, "fetch": true, "for": true,
+	"foreign": true, "from": true, "grant": true, "group": true, "having": true, "in": true,
+	"initially": true, "intersect": true, "into": true, "lateral": true, "leading": true,
+	"limit": true, "localtime": true, "localtimestamp": true, "not": true, "null": true,
+	"offset": true, "on": true, "only": true, "or": true, "order": true, "placing": true,
+	"primary": true, "references": true, "returning": true, "select": true, "session_user": true,
+	"some": true, "symmetric": true, "table": true, "then": true, "to": true, "trailing": true,
+	"true": true, "union": true, "unique": true, "user": true, "using": true, "variadic": true,
+	"when": true, "where": true, "window": true, "with": true,
+}
+
+// postgresSerialTypes 自增整数类型对应的实际存储类型（ALTER COLUMN ... TYPE 中不能使用 SERIAL）
+var postgresSerialTypes = map[string]string{
+	"SMALLSERIAL": "SMALLINT", "SERIAL2": "SMALLINT",
+	"SERIAL": "INTEGER", "SERIAL4": "INTEGER",
+	"BIGSERIAL": "BIGINT", "SERIAL8": "BIGINT",
+}
+
+// postgresTypeFamilies 可以相互隐式转换的类型类别
+var postgresTypeFamilies = map[string]string{
+	"SMALLINT": "number", "INTEGER": "number", "INT": "number", "INT2": "number", "INT4": "number",
+	"INT8": "number", "BIGINT": "number", "NUMERIC": "number", "DECIMAL": "number", "REAL": "number",
+	"FLOAT": "number", "FLOAT4": "number", "FLOAT8": "number", "DOUBLE PRECISION": "number",
+	"TEXT": "text", "VARCHAR": "text", "CHARACTER VARYING": "text", "CHAR": "text",
+	"CHARACTER": "text", "BPCHAR": "text",
+	"TIMESTAMP": "timestamp", "TIMESTAMPTZ": "timestamp",
+	"TIME": "time", "TIMETZ": "time",
+}
+
+func (postgresDialect) quote(name string) string {
+	if postgresPlainIdent.MatchString(name) && !postgresReservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// supportsColumnOrder PostgreSQL 不能调整已有列的顺序，新增列总是追加在最后
+func (postgresDialect) supportsColumnOrder() bool {
+	return false
+}
+
+func (p postgresDialect) columnDefinition(col *parser.Column) string {
+	parts := []string{formatColumnType(col)}
+	if col.Collation != "" {
+		parts = append(parts, "COLLATE "+p.quote(col.Collation))
+	}
+	if col.Generated != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", col.Generated, generatedKind(col)))
+	} else if identity := postgresIdentity(col); identity != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED %s AS IDENTITY", identity))
+	}
+	if col.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.HasDefault() && col.Generated == "" {
+		parts = append(parts, "DEFAULT "+formatPostgresDefault(col))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indexDefinition 生成 CREATE INDEX 语句（PostgreSQL 没有前缀索引，FULLTEXT、SPATIAL 按普通索引处理）
+func (p postgresDialect) indexDefinition(table string, idx *parser.Index) string {
+	prefix := "CREATE INDEX"
+	if idx.Type == "UNIQUE" {
+		prefix = "CREATE UNIQUE INDEX"
+	}
+	using := ""
+	if idx.Algorithm != "" {
+		using = " USING " + idx.Algorithm
+	}
+	return fmt.Sprintf("%s %s ON %s%s (%s)", prefix, p.quote(idx.Name), p.quote(table), using, formatKeyParts(idx, p.quote))
+}
+
+func (p postgresDialect) foreignKeyDefinition(fk *parser.Constraint) string {
+	return formatNamedForeignKey(fk, p.quote)
+}
+
+func (p postgresDialect) createTable(table *parser.TableSchema) []string {
+	var defs []string
+	for _, col := range table.Columns {
+		defs = append(defs, fmt.Sprintf("%s %s", p.quote(col.Name), p.columnDefinition(col)))
+	}
+	if len(table.PrimaryKeys) > 0 {
+		defs = append(defs, p.primaryKeyDefinition(table.PrimaryKeys, table.PrimaryKeyName))
+	}
+	for _, fk := range table.ForeignKeys() {
+		defs = append(defs, p.foreignKeyDefinition(fk))
+	}
+	for _, check := range table.Checks() {
+		defs = append(defs, p.checkDefinition(check))
+	}
+
+	ddls := []string{fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", p.quote(table.Name), strings.Join(defs, ",\n  "))}
+	if partitioning := table.Partitioning; partitioning != nil {
+		ddls[0] += fmt.Sprintf(" PARTITION BY %s (%s)", partitioning.Type, partitioning.Expression)
+	}
+	for _, idx := range table.Indexes {
+		ddls = append(ddls, p.indexDefinition(table.Name, idx))
+	}
+	if comment, ok := table.Options["COMMENT"]; ok && comment != "" {
+		ddls = append(ddls, fmt.Sprintf("COMMENT ON TABLE %s IS %s", p.quote(table.Name), quoteStringLiteral(comment)))
+	}
+	for _, col := range table.Columns {
+		if col.Comment != "" {
+			ddls = append(ddls, p.commentOnColumn(table.Name, col))
+		}
+	}
+	return ddls
+}
+
+func (p postgresDialect) addColumn(table string, col *parser.Column, placement string) []string {
+	ddls := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", p.quote(table), p.quote(col.Name), p.columnDefinition(col))}
+	if col.Comment != "" {
+		ddls = append(ddls, p.commentOnColumn(table, col))
+	}
+	return ddls
+}
+
+// modifyColumn 将列的各项变化转换为一条 ALTER TABLE 语句中的多个 ALTER COLUMN 子句，注释单独设置
+// 普通列不能改为生成列，虚拟生成列也不能与其他列相互转换，这些情况在同一条语句中删除后重新添加
+func (p postgresDialect) modifyColumn(table string, source, target *parser.Column, placement string) []string {
+	column := p.quote(target.Name)
+	if (source.Generated == "" && target.Generated != "") || isVirtualColumn(source) != isVirtualColumn(target) {
+		ddls := []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s, ADD COLUMN %s %s",
+			p.quote(table), p.quote(source.Name), column, p.columnDefinition(target))}
+		if target.Comment != "" {
+			ddls = append(ddls, p.commentOnColumn(table, target))
+		}
+		return ddls
+	}
+
+	var actions []string
+	switch {
+	case source.Generated != "" && target.Generated == "":
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP EXPRESSION", column))
+	case parser.NormalizeExpression(source.Generated, parser.PostgreSQL) != parser.NormalizeExpression(target.Generated, parser.PostgreSQL):
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET EXPRESSION AS (%s)", column, target.Generated))
+	}
+
+	// 修改排序规则同样通过 TYPE 子句完成，未指定 COLLATE 时恢复为类型的默认排序规则
+	sourceType, targetType := postgresStorageType(source), postgresStorageType(target)
+	if sourceType != targetType || !strings.EqualFold(source.Collation, target.Collation) {
+		action := fmt.Sprintf("ALTER COLUMN %s TYPE %s", column, targetType)
+		if target.Collation != "" {
+			action += " COLLATE " + p.quote(target.Collation)
+		}
+		if postgresTypeFamily(source) != postgresTypeFamily(target) {
+			action += fmt.Sprintf(" USING %s::%s", column, targetType)
+		}
+		actions = append(actions, action)
+	}
+
+	if source.NotNull != target.NotNull {
+		if target.NotNull {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", column))
+		} else {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", column))
+		}
+	}
+
+	// SERIAL 的自增通过序列默认值实现，改为普通类型时需要去掉默认值
+	dropSerial := isPostgresSerial(source) && !isPostgresSerial(target) && !target.HasDefault()
+	switch {
+	case !sameDefault(source, target) && target.HasDefault():
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", column, formatPostgresDefault(target)))
+	case !sameDefault(source, target), dropSerial:
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", column))
+	}
+
+	// 无法将已有列改为 SERIAL，改用等价的标识列
+	sourceIdentity, targetIdentity := postgresIdentity(source), postgresIdentity(target)
+	if !isPostgresSerial(source) && isPostgresSerial(target) {
+		targetIdentity = "BY DEFAULT"
+	}
+	switch {
+	case sourceIdentity == targetIdentity:
+	case sourceIdentity == "":
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s ADD GENERATED %s AS IDENTITY", column, targetIdentity))
+	case targetIdentity == "":
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP IDENTITY IF EXISTS", column))
+	default:
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET GENERATED %s", column, targetIdentity))
+	}
+
+	var ddls []string
+	if len(actions) > 0 {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s %s", p.quote(table), strings.Join(actions, ", ")))
+	}
+	if source.Comment != target.Comment {
+		ddls = append(ddls, p.commentOnColumn(table, target))
+	}
+	return ddls
+}
+
+// renameColumn 先重命名，再按新列名修改定义
+func (p postgresDialect) renameColumn(table string, rename *ColumnRename) []string {
+	ddls := []string{fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", p.quote(table), p.quote(rename.From), p.quote(rename.To))}
+	if len(rename.Changes) > 0 {
+		source := *rename.Source
+		source.Name = rename.To
+		ddls = append(ddls, p.modifyColumn(table, &source, rename.Target, "")...)
+	}
+	return ddls
+}
+
+func (p postgresDialect) dropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", p.quote(table), p.quote(column))
+}
+
+func (p postgresDialect) addIndex(table string, idx *parser.Index) string {
+	return p.indexDefinition(table, idx)
+}
+
+// modifyIndex PostgreSQL 没有不可见索引，仅可见性变化时不生成语句
+func (p postgresDialect) modifyIndex(table string, diff *IndexDiff) []string {
+	if diff.VisibilityOnly() {
+		return nil
+	}
+	return []string{p.dropIndex(table, diff.Source), p.indexDefinition(table, diff.Target)}
+}
+
+func (p postgresDialect) renameIndex(table string, rename *IndexRename) []string {
+	return []string{fmt.Sprintf("ALTER INDEX %s RENAME TO %s", p.quote(rename.From), p.quote(rename.To))}
+}
+
+func (p postgresDialect) dropIndex(table string, idx *parser.Index) string {
+	return fmt.Sprintf("DROP INDEX %s", p.quote(idx.Name))
+}
+
+func (p postgresDialect) addForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", p.quote(table), p.foreignKeyDefinition(fk))
+}
+
+func (p postgresDialect) dropForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", p.quote(table), p.quote(fk.Name))
+}
+
+func (p postgresDialect) checkDefinition(check *parser.Constraint) string {
+	return formatCheck(check, p.quote)
+}
+
+func (p postgresDialect) addCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", p.quote(table), p.checkDefinition(check))
+}
+
+// modifyCheck 在同一条语句中删除并重建约束
+func (p postgresDialect) modifyCheck(table string, diff *ConstraintDiff) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s, ADD %s", p.quote(table), p.quote(diff.Source.Name), p.checkDefinition(diff.Target))}
+}
+
+func (p postgresDialect) dropCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", p.quote(table), p.quote(check.Name))
+}
+
+func (postgresDialect) deferForeignKeys() bool {
+	return true
+}
+
+// primaryKey 按约束名删除并重建主键，未命名的主键使用 PostgreSQL 的默认名称 <表名>_pkey；
+// 自增列不要求位于索引首列，因此无需像 MySQL 那样临时去掉自增属性
+func (p postgresDialect) primaryKey(d *Diff, table string) (string, map[string]bool, string) {
+	pk := d.PrimaryKey
+	if pk == nil {
+		return "", nil, ""
+	}
+	var clauses []string
+	if len(pk.Source) > 0 {
+		name := pk.SourceName
+		if name == "" {
+			name = table + "_pkey"
+		}
+		clauses = append(clauses, "DROP CONSTRAINT "+p.quote(name))
+	}
+	if len(pk.Target) > 0 {
+		clauses = append(clauses, "ADD "+p.primaryKeyDefinition(pk.Target, pk.TargetName))
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s", p.quote(table), strings.Join(clauses, ", ")), nil, ""
+}
+
+// commentOnColumn 生成设置列注释的语句（注释为空时清除）
+func (p postgresDialect) commentOnColumn(table string, col *parser.Column) string {
+	comment := "NULL"
+	if col.Comment != "" {
+		comment = quoteStringLiteral(col.Comment)
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", p.quote(table), p.quote(col.Name), comment)
+}
+
+// columnList 格式化加引号的列名列表
+func (p postgresDialect) columnList(columns []string) string {
+	return quoteList(columns, p.quote)
+}
+
+// primaryKeyDefinition 生成主键定义，约束名为空时由 PostgreSQL 自动命名
+func (p postgresDialect) primaryKeyDefinition(columns []string, name string) string {
+	if name == "" {
+		return fmt.Sprintf("PRIMARY KEY (%s)", p.columnList(columns))
+	}
+	return fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)", p.quote(name), p.columnList(columns))
+}
+
+// isPostgresSerial 判断列是否为 SERIAL 系列类型
+func isPostgresSerial(col *parser.Column) bool {
+	_, ok := postgresSerialTypes[col.Type]
+	return ok
+}
+
+// postgresIdentity 返回标识列的生成方式
+// 来自 MySQL 写法的 AUTO_INCREMENT 列（非 SERIAL）按 GENERATED BY DEFAULT AS IDENTITY 处理
+func postgresIdentity(col *parser.Column) string {
+	if col.Identity != "" {
+		return col.Identity
+	}
+	if col.AutoInc && !isPostgresSerial(col) {
+		return "BY DEFAULT"
+	}
+	return ""
+}
+
+// postgresStorageType 返回列的实际存储类型（SERIAL 转为对应的整数类型）
+func postgresStorageType(col *parser.Column) string {
+	if base, ok := postgresSerialTypes[col.Type]; ok {
+		return base
+	}
+	return formatColumnType(col)
+}
+
+// postgresTypeFamily 返回类型所属的类别，同类别之间可以隐式转换，无需 USING 子句
+func postgresTypeFamily(col *parser.Column) string {
+	base := col.Type
+	if storage, ok := postgresSerialTypes[base]; ok {
+		base = storage
+	}
+	if family, ok := postgresTypeFamilies[base]; ok {
+		return family
+	}
+	return base
+}
+
+// formatPostgresDefault 格式化默认值：字符串字面量加引号，数字、关键字、函数调用和表达式保持原样
+func formatPostgresDefault(col *parser.Column) string {
+	if col.DefaultQuoted {
+		return quoteStringLiteral(col.DefaultValue)
+	}
+	return col.DefaultValue
+}
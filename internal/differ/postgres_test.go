@@ -0,0 +1,164 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestPostgresGenerateDDL(t *testing.T) {
+	sourceSQL := `
+CREATE TABLE users (
+	id SERIAL PRIMARY KEY,
+	email varchar(100),
+	score integer DEFAULT 0,
+	status text,
+	org_id integer
+);
+CREATE INDEX idx_status ON users (status);
+`
+	targetSQL := `
+CREATE TABLE users (
+	id SERIAL PRIMARY KEY,
+	email varchar(255) NOT NULL,
+	score bigint,
+	status integer,
+	org_id integer REFERENCES orgs (id),
+	"createdAt" timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_status ON users (status, org_id);
+CREATE UNIQUE INDEX ON users (lower(email));
+COMMENT ON COLUMN users.email IS 'it''s the login';
+`
+	diff := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL)
+
+	expected := []string{
+		`ALTER TABLE users ADD COLUMN "createdAt" TIMESTAMPTZ NOT NULL DEFAULT NOW()`,
+		"ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255), ALTER COLUMN email SET NOT NULL",
+		"COMMENT ON COLUMN users.email IS 'it''s the login'",
+		"ALTER TABLE users ALTER COLUMN score TYPE BIGINT, ALTER COLUMN score DROP DEFAULT",
+		"ALTER TABLE users ALTER COLUMN status TYPE INTEGER USING status::INTEGER",
+		"DROP INDEX idx_status",
+		"CREATE INDEX idx_status ON users (status, org_id)",
+		"CREATE UNIQUE INDEX users_expr_idx ON users (lower(email))",
+		"ALTER TABLE users ADD CONSTRAINT users_org_id_fkey FOREIGN KEY (org_id) REFERENCES orgs (id)",
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	rollback := strings.Join(diff.GenerateRollbackDDL(), "\n")
+	for _, want := range []string{
+		`ALTER TABLE users DROP COLUMN "createdAt"`,
+		"ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(100), ALTER COLUMN email DROP NOT NULL",
+		"COMMENT ON COLUMN users.email IS NULL",
+		"ALTER TABLE users ALTER COLUMN score TYPE INTEGER, ALTER COLUMN score SET DEFAULT 0",
+		"ALTER TABLE users DROP CONSTRAINT users_org_id_fkey",
+		"DROP INDEX users_expr_idx",
+	} {
+		if !strings.Contains(rollback, want) {
+			t.Errorf("回滚 DDL 缺少 %q:\n%s", want, rollback)
+		}
+	}
+}
+
+func TestPostgresCreateTable(t *testing.T) {
+	targetSQL := `
+CREATE TABLE "order" (
+	id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	user_id integer NOT NULL,
+	note text,
+	CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user ON "order" (user_id);
+COMMENT ON TABLE "order" IS 'orders';
+COMMENT ON COLUMN "order".note IS 'free text';
+`
+	diff := compareDialect(t, parser.PostgreSQL, "", targetSQL)
+
+	expected := []string{
+		`CREATE TABLE "order" (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  user_id INTEGER NOT NULL,
+  note TEXT,
+  PRIMARY KEY (id)
+)`,
+		`CREATE INDEX idx_user ON "order" (user_id)`,
+		`COMMENT ON TABLE "order" IS 'orders'`,
+		`COMMENT ON COLUMN "order".note IS 'free text'`,
+		`ALTER TABLE "order" ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE`,
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("建表 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestPostgresPrimaryKeyAndIdentity(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id integer NOT NULL, code integer GENERATED BY DEFAULT AS IDENTITY, PRIMARY KEY (id));`
+	targetSQL := `CREATE TABLE t (id integer NOT NULL, code integer GENERATED ALWAYS AS IDENTITY, PRIMARY KEY (id, code));`
+
+	diff := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL)
+
+	expected := []string{
+		"ALTER TABLE t DROP CONSTRAINT t_pkey, ADD PRIMARY KEY (id, code)",
+		"ALTER TABLE t ALTER COLUMN code SET GENERATED ALWAYS",
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestPostgresNamedPrimaryKey(t *testing.T) {
+	sourceSQL := `CREATE TABLE users (id integer NOT NULL, t integer NOT NULL, CONSTRAINT pk_users PRIMARY KEY (id));`
+	targetSQL := `CREATE TABLE users (id integer NOT NULL, t integer NOT NULL, CONSTRAINT pk_users PRIMARY KEY (id, t));`
+
+	p := parser.NewParserWithDialect(parser.PostgreSQL)
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源脚本失败: %v", err)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标脚本失败: %v", err)
+	}
+	options := DefaultOptions()
+	options.Dialect = parser.PostgreSQL
+	d := NewDatabaseDifferWithOptions(source, target, options)
+	diff := d.Compare()
+
+	// 按原约束名删除主键，重建时保留约束名
+	ddls := diff.GenerateDDL()
+	expected := "ALTER TABLE users DROP CONSTRAINT pk_users, ADD CONSTRAINT pk_users PRIMARY KEY (id, t)"
+	if len(ddls) != 1 || ddls[0] != expected {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+	if err := d.Verify(diff); err != nil {
+		t.Errorf("验证失败: %v", err)
+	}
+	applied, err := ApplyDDL(source, ddls, parser.PostgreSQL)
+	if err != nil {
+		t.Fatalf("执行 DDL 失败: %v", err)
+	}
+	if name := applied.Table("users").PrimaryKeyName; name != "pk_users" {
+		t.Errorf("期望主键约束名为 pk_users，实际为 %q", name)
+	}
+
+	// 建表语句同样保留约束名
+	created := compareDialect(t, parser.PostgreSQL, "", targetSQL).GenerateDDL()
+	if len(created) == 0 || !strings.Contains(created[0], "CONSTRAINT pk_users PRIMARY KEY (id, t)") {
+		t.Errorf("建表 DDL 缺少主键约束名:\n%s", strings.Join(created, "\n"))
+	}
+}
+
+func TestPostgresCollation(t *testing.T) {
+	diff := compareDialect(t, parser.PostgreSQL,
+		`CREATE TABLE users (name VARCHAR(50), code TEXT COLLATE "C");`,
+		`CREATE TABLE users (name VARCHAR(50) COLLATE "C", code TEXT);`)
+
+	ddls := diff.GenerateDDL()
+	expected := `ALTER TABLE users ALTER COLUMN name TYPE VARCHAR(50) COLLATE "C"`
+	if len(ddls) != 2 || ddls[0] != expected || ddls[1] != "ALTER TABLE users ALTER COLUMN code TYPE TEXT" {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
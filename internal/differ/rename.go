@@ -0,0 +1,372 @@
+package differ
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// 重命名识别的置信度阈值（百分制）
+const (
+	renameAutoConfidence      = 90 // 达到该置信度且无歧义时自动采用
+	renameCandidateConfidence = 50 // 达到该置信度时作为候选在摘要中提示
+)
+
+// 重命名候选的对象类型
+const (
+	RenameKindColumn = "column"
+	RenameKindIndex  = "index"
+)
+
+// ColumnRename 列的重命名
+type ColumnRename struct {
+	From       string
+	To         string
+	Source     *parser.Column
+	Target     *parser.Column
+	Changes    []string // 除名称外的定义变更
+	Confidence float64  // 置信度（来自重命名提示时为 1）
+}
+
+// IndexRename 索引的重命名
+type IndexRename struct {
+	From       string
+	To         string
+	Source     *parser.Index
+	Target     *parser.Index
+	Confidence float64 // 置信度（来自重命名提示时为 1）
+}
+
+// RenameCandidate 未被自动采用的可能重命名（存在歧义或置信度不足）
+type RenameCandidate struct {
+	Kind       string  // 对象类型：column 或 index
+	From       string  // 原名称
+	To         string  // 新名称
+	Confidence float64 // 置信度
+}
+
+// renamePair 重命名匹配的中间结果
+type renamePair struct {
+	from, to int // 在删除列表、新增列表中的下标
+	score    int // 置信度（百分制）
+}
+
+// detectColumnRenames 从删除列和新增列中识别重命名
+// 先采用重命名提示，再按类型、属性、位置和名称相似度打分；
+// 置信度足够且双方都没有其他同分候选时视为重命名，其余候选记录到 RenameCandidates
+func (d *Differ) detectColumnRenames(diff *Diff) {
+	removedUsed := make([]bool, len(diff.RemovedColumns))
+	addedUsed := make([]bool, len(diff.AddedColumns))
+
+	for _, hint := range d.options.RenameHints {
+		if !hint.appliesTo(d.source.Name, d.target.Name) {
+			continue
+		}
+		i := columnPosition(diff.RemovedColumns, hint.From)
+		j := columnPosition(diff.AddedColumns, hint.To)
+		if i < 0 || j < 0 || removedUsed[i] || addedUsed[j] {
+			continue
+		}
+		d.addColumnRename(diff, diff.RemovedColumns[i], diff.AddedColumns[j], 1)
+		removedUsed[i], addedUsed[j] = true, true
+	}
+
+	if d.options.DetectRenames {
+		var pairs []renamePair
+		for i, source := range diff.RemovedColumns {
+			for j, target := range diff.AddedColumns {
+				if removedUsed[i] || addedUsed[j] {
+					continue
+				}
+				if score := d.columnRenameScore(source, target); score >= renameCandidateConfidence {
+					pairs = append(pairs, renamePair{from: i, to: j, score: score})
+				}
+			}
+		}
+
+		for _, pair := range acceptRenamePairs(pairs, removedUsed, addedUsed) {
+			d.addColumnRename(diff, diff.RemovedColumns[pair.from], diff.AddedColumns[pair.to], float64(pair.score)/100)
+		}
+
+		for _, pair := range pairs {
+			if !removedUsed[pair.from] && !addedUsed[pair.to] {
+				diff.RenameCandidates = append(diff.RenameCandidates, &RenameCandidate{
+					Kind:       RenameKindColumn,
+					From:       diff.RemovedColumns[pair.from].Name,
+					To:         diff.AddedColumns[pair.to].Name,
+					Confidence: float64(pair.score) / 100,
+				})
+			}
+		}
+	}
+
+	diff.RemovedColumns = filterColumns(diff.RemovedColumns, removedUsed)
+	diff.AddedColumns = filterColumns(diff.AddedColumns, addedUsed)
+}
+
+// addColumnRename 记录列重命名
+func (d *Differ) addColumnRename(diff *Diff, source, target *parser.Column, confidence float64) {
+	diff.RenamedColumns = append(diff.RenamedColumns, &ColumnRename{
+		From:       source.Name,
+		To:         target.Name,
+		Source:     source,
+		Target:     target,
+		Changes:    compareColumns(source, target, d.options.Dialect),
+		Confidence: confidence,
+	})
+	d.columnRenames[strings.ToLower(source.Name)] = target.Name
+}
+
+// columnRenameScore 计算两列为同一列改名的置信度
+//   - 类型（含长度、UNSIGNED）必须一致，得 50 分
+//   - 其余属性一致，加 20 分
+//   - 在表中的位置一致（序号相同或前一列相同），加 20 分
+//   - 名称相似，加 10 分
+func (d *Differ) columnRenameScore(source, target *parser.Column) int {
+	if formatColumnType(source) != formatColumnType(target) || source.Unsigned != target.Unsigned {
+		return 0
+	}
+
+	score := 50
+	if len(compareColumns(source, target, d.options.Dialect)) == 0 {
+		score += 20
+	}
+	if d.samePosition(source, target) {
+		score += 20
+	}
+	if similarNames(source.Name, target.Name) {
+		score += 10
+	}
+	return score
+}
+
+// samePosition 判断列在源表和目标表中的位置是否一致
+func (d *Differ) samePosition(source, target *parser.Column) bool {
+	sourcePos := columnPosition(d.source.Columns, source.Name)
+	targetPos := columnPosition(d.target.Columns, target.Name)
+	if sourcePos == targetPos {
+		return true
+	}
+
+	var sourcePrev, targetPrev string
+	if sourcePos > 0 {
+		sourcePrev = d.source.Columns[sourcePos-1].Name
+	}
+	if targetPos > 0 {
+		targetPrev = d.target.Columns[targetPos-1].Name
+	}
+	return strings.EqualFold(d.renamedColumn(sourcePrev), targetPrev)
+}
+
+// detectIndexRenames 从删除索引和新增索引中识别重命名
+// 定义（按列重命名映射后）完全一致的索引才可能是重命名
+func (d *Differ) detectIndexRenames(diff *Diff) {
+	removedUsed := make([]bool, len(diff.RemovedIndexes))
+	addedUsed := make([]bool, len(diff.AddedIndexes))
+
+	for _, hint := range d.options.RenameHints {
+		if !hint.appliesTo(d.source.Name, d.target.Name) {
+			continue
+		}
+		i := findIndex(diff.RemovedIndexes, hint.From)
+		j := findIndex(diff.AddedIndexes, hint.To)
+		if i < 0 || j < 0 || removedUsed[i] || addedUsed[j] {
+			continue
+		}
+		d.addIndexRename(diff, diff.RemovedIndexes[i], diff.AddedIndexes[j], 1)
+		removedUsed[i], addedUsed[j] = true, true
+	}
+
+	if d.options.DetectRenames {
+		var pairs []renamePair
+		for i, source := range diff.RemovedIndexes {
+			for j, target := range diff.AddedIndexes {
+				if removedUsed[i] || addedUsed[j] {
+					continue
+				}
+				if len(compareIndexes(d.renamedIndex(source), target, d.options.Dialect)) != 0 {
+					continue
+				}
+				score := 90
+				if similarNames(source.Name, target.Name) {
+					score += 10
+				}
+				pairs = append(pairs, renamePair{from: i, to: j, score: score})
+			}
+		}
+
+		for _, pair := range acceptRenamePairs(pairs, removedUsed, addedUsed) {
+			d.addIndexRename(diff, diff.RemovedIndexes[pair.from], diff.AddedIndexes[pair.to], float64(pair.score)/100)
+		}
+
+		for _, pair := range pairs {
+			if !removedUsed[pair.from] && !addedUsed[pair.to] {
+				diff.RenameCandidates = append(diff.RenameCandidates, &RenameCandidate{
+					Kind:       RenameKindIndex,
+					From:       diff.RemovedIndexes[pair.from].Name,
+					To:         diff.AddedIndexes[pair.to].Name,
+					Confidence: float64(pair.score) / 100,
+				})
+			}
+		}
+	}
+
+	diff.RemovedIndexes = filterIndexes(diff.RemovedIndexes, removedUsed)
+	diff.AddedIndexes = filterIndexes(diff.AddedIndexes, addedUsed)
+}
+
+// addIndexRename 记录索引重命名
+func (d *Differ) addIndexRename(diff *Diff, source, target *parser.Index, confidence float64) {
+	diff.RenamedIndexes = append(diff.RenamedIndexes, &IndexRename{
+		From:       source.Name,
+		To:         target.Name,
+		Source:     source,
+		Target:     target,
+		Confidence: confidence,
+	})
+}
+
+// acceptRenamePairs 按置信度从高到低采用无歧义的重命名，并标记已使用的对象
+// 若某一侧存在另一个同分候选，则视为有歧义，不自动采用
+func acceptRenamePairs(pairs []renamePair, removedUsed, addedUsed []bool) []renamePair {
+	sorted := make([]renamePair, len(pairs))
+	copy(sorted, pairs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].score > sorted[j].score })
+
+	var accepted []renamePair
+	for _, pair := range sorted {
+		if pair.score < renameAutoConfidence || removedUsed[pair.from] || addedUsed[pair.to] {
+			continue
+		}
+		ambiguous := false
+		for _, other := range sorted {
+			if other == pair || removedUsed[other.from] || addedUsed[other.to] {
+				continue
+			}
+			if (other.from == pair.from || other.to == pair.to) && other.score >= pair.score {
+				ambiguous = true
+				break
+			}
+		}
+		if ambiguous {
+			continue
+		}
+		accepted = append(accepted, pair)
+		removedUsed[pair.from], addedUsed[pair.to] = true, true
+	}
+	return accepted
+}
+
+// renamedColumn 返回源表列名在目标表中的名称（未重命名时原样返回）
+func (d *Differ) renamedColumn(name string) string {
+	if to, ok := d.columnRenames[strings.ToLower(name)]; ok {
+		return to
+	}
+	return name
+}
+
+// renamedColumns 将源表列名列表映射为目标表中的名称
+func (d *Differ) renamedColumns(names []string) []string {
+	if len(d.columnRenames) == 0 {
+		return names
+	}
+	mapped := make([]string, len(names))
+	for i, name := range names {
+		mapped[i] = d.renamedColumn(name)
+	}
+	return mapped
+}
+
+// renamedIndex 返回列名映射为目标表名称后的索引副本
+func (d *Differ) renamedIndex(idx *parser.Index) *parser.Index {
+	mapped := *idx
+	mapped.Columns = d.renamedColumns(idx.Columns)
+	return &mapped
+}
+
+// similarNames 判断两个名称是否相似（包含关系或编辑距离不超过较长名称的一半）
+func similarNames(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return true
+	}
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	return levenshtein(a, b)*2 <= longest
+}
+
+// levenshtein 计算两个字符串的编辑距离
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// columnPosition 返回列在列表中的序号（不存在时返回 -1）
+func columnPosition(columns []*parser.Column, name string) int {
+	for i, col := range columns {
+		if strings.EqualFold(col.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findIndex 按名称查找索引的下标（不区分大小写）
+func findIndex(indexes []*parser.Index, name string) int {
+	for i, idx := range indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// filterColumns 返回未被标记的列
+func filterColumns(columns []*parser.Column, used []bool) []*parser.Column {
+	result := make([]*parser.Column, 0, len(columns))
+	for i, col := range columns {
+		if !used[i] {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// filterIndexes 返回未被标记的索引
+func filterIndexes(indexes []*parser.Index, used []bool) []*parser.Index {
+	result := make([]*parser.Index, 0, len(indexes))
+	for i, idx := range indexes {
+		if !used[i] {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
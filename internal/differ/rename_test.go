@@ -0,0 +1,113 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func compareSQL(t *testing.T, sourceSQL, targetSQL string, options Options) *Diff {
+	t.Helper()
+	p := parser.NewParser()
+	source, err := p.Parse(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源表失败: %v", err)
+	}
+	target, err := p.Parse(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标表失败: %v", err)
+	}
+	return NewDifferWithOptions(source, target, options).Compare()
+}
+
+func TestDetectColumnRename(t *testing.T) {
+	sourceSQL := `CREATE TABLE users (
+		id INT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		age INT,
+		INDEX idx_name (name)
+	)`
+
+	targetSQL := `CREATE TABLE users (
+		id INT PRIMARY KEY,
+		full_name VARCHAR(100) NOT NULL,
+		age INT,
+		INDEX idx_name (full_name)
+	)`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	if len(diff.RenamedColumns) != 1 || diff.RenamedColumns[0].From != "name" || diff.RenamedColumns[0].To != "full_name" {
+		t.Fatalf("应识别 name -> full_name 的重命名，实际 %+v", diff.RenamedColumns)
+	}
+	if len(diff.AddedColumns) != 0 || len(diff.RemovedColumns) != 0 {
+		t.Error("重命名的列不应再出现在新增或删除列中")
+	}
+	if len(diff.ModifiedIndexes) != 0 {
+		t.Error("索引列随列重命名，不应视为修改")
+	}
+
+	ddls := diff.GenerateDDL("users")
+	expected := "ALTER TABLE users RENAME COLUMN name TO full_name"
+	if len(ddls) != 1 || ddls[0] != expected {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestRenameHint(t *testing.T) {
+	sourceSQL := `CREATE TABLE users (id INT PRIMARY KEY, nick VARCHAR(50), INDEX idx_nick (nick))`
+	targetSQL := `CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(50), display_name VARCHAR(100) NOT NULL, INDEX idx_display (display_name))`
+
+	// 自动识别只会匹配定义和位置都一致的 email
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+	if len(diff.RenamedColumns) != 1 || diff.RenamedColumns[0].To != "email" {
+		t.Fatalf("应自动识别 nick -> email，实际 %+v", diff.RenamedColumns)
+	}
+
+	options := DefaultOptions()
+	options.RenameHints = []RenameHint{
+		{Table: "orders", From: "nick", To: "email"},
+		{Table: "users", From: "nick", To: "display_name"},
+		{From: "idx_nick", To: "idx_display"},
+	}
+	diff = compareSQL(t, sourceSQL, targetSQL, options)
+
+	ddls := diff.GenerateDDL("users")
+	expected := []string{
+		"ALTER TABLE users CHANGE COLUMN nick display_name VARCHAR(100) NOT NULL",
+		"ALTER TABLE users RENAME INDEX idx_nick TO idx_display",
+		"ALTER TABLE users ADD COLUMN email VARCHAR(50) AFTER id",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestRenameCandidates(t *testing.T) {
+	// 列位置不一致、两个索引定义相同，均不应自动重命名
+	sourceSQL := `CREATE TABLE t (id INT PRIMARY KEY, a INT, b INT, INDEX idx_x (id), INDEX idx_y (id))`
+	targetSQL := `CREATE TABLE t (c INT, id INT PRIMARY KEY, INDEX idx_z (id))`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	if len(diff.RenamedColumns) != 0 || len(diff.RenamedIndexes) != 0 {
+		t.Fatalf("存在歧义时不应自动重命名，实际 %+v %+v", diff.RenamedColumns, diff.RenamedIndexes)
+	}
+	if len(diff.RenameCandidates) != 4 {
+		t.Fatalf("期望 4 个重命名候选，实际 %d 个", len(diff.RenameCandidates))
+	}
+	if diff.RenameCandidates[0].Kind != RenameKindColumn || diff.RenameCandidates[0].Confidence != 0.7 {
+		t.Errorf("列候选错误: %+v", diff.RenameCandidates[0])
+	}
+	if !strings.Contains(diff.Summary(), "可能的重命名") {
+		t.Errorf("摘要中应提示可能的重命名:\n%s", diff.Summary())
+	}
+
+	options := DefaultOptions()
+	options.DetectRenames = false
+	diff = compareSQL(t, sourceSQL, targetSQL, options)
+	if len(diff.RenameCandidates) != 0 {
+		t.Error("关闭自动识别后不应产生重命名候选")
+	}
+}
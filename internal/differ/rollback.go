@@ -0,0 +1,74 @@
+package differ
+
+import (
+	"fmt"
+)
+
+// DataLossWarning 回滚脚本中标记无法无损回滚的语句的注释前缀
+const DataLossWarning = "-- 警告: 无法无损回滚，"
+
+// dataLossWarning 生成数据丢失警告注释行（语句之前单独一行）
+func dataLossWarning(reason string) string {
+	return DataLossWarning + reason + "\n"
+}
+
+// GenerateRollbackDDL 生成回滚语句，将按 GenerateDDL 迁移后的表恢复为源表结构
+// 正向脚本中被注释的删除语句视为未执行，对应的恢复语句同样以注释形式输出；
+// 无法无损回滚的语句（如删除新增列、改回更小的类型）前会带有 DataLossWarning 注释
+func (d *Diff) GenerateRollbackDDL(tableName string) []string {
+	if d.source == nil || d.target == nil {
+		return make([]string, 0)
+	}
+	return d.reverse().generateDDL(tableName, true)
+}
+
+// reverse 以目标表为源、源表为目标重新比对，重命名直接沿用正向识别的结果
+func (d *Diff) reverse() *Diff {
+	options := d.options
+	options.DetectRenames = false
+	options.RenameHints = nil
+	for _, rename := range d.RenamedColumns {
+		options.RenameHints = append(options.RenameHints, RenameHint{From: rename.To, To: rename.From})
+	}
+	for _, rename := range d.RenamedIndexes {
+		options.RenameHints = append(options.RenameHints, RenameHint{From: rename.To, To: rename.From})
+	}
+	return NewDifferWithOptions(d.target, d.source, options).Compare()
+}
+
+// GenerateRollbackDDL 生成整个数据库迁移的回滚脚本
+// 顺序：删除新建表中推迟添加的外键 → 回滚修改的表（倒序）→ 删除新建的表（按创建顺序倒序）→
+// 恢复删除的表（注释掉，对应正向脚本中被注释的删除语句）
+func (d *DatabaseDiff) GenerateRollbackDDL() []string {
+	ddls := make([]string, 0)
+	dialect := dialectFor(d.Dialect)
+
+	// 推迟添加的外键需要先删除，否则无法删除被引用的表，也无法回滚被引用的列或索引
+	tables, deferred := d.sortAddedTables(dialect)
+	for _, item := range deferred {
+		ddls = append(ddls, dialect.dropForeignKey(item.table, item.fk))
+	}
+
+	for i := len(d.ModifiedTables) - 1; i >= 0; i-- {
+		tableDiff := d.ModifiedTables[i]
+		ddls = append(ddls, tableDiff.Diff.GenerateRollbackDDL(tableDiff.Name)...)
+	}
+	for i := len(tables) - 1; i >= 0; i-- {
+		ddls = append(ddls, dataLossWarning(fmt.Sprintf("表 %s 中的数据将丢失", tables[i].Name))+
+			fmt.Sprintf("DROP TABLE %s", dialect.quote(tables[i].Name)))
+	}
+
+	// 被引用的表先恢复；循环依赖的外键在所有表恢复之后再添加
+	removed, cyclic := sortTablesByForeignKeys(d.RemovedTables)
+	if !dialect.deferForeignKeys() {
+		cyclic = nil
+	}
+	for _, table := range removed {
+		ddls = append(ddls, commentOut(dialect.createTable(withoutForeignKeys(table, cyclic)))...)
+	}
+	for _, item := range cyclic {
+		ddls = append(ddls, commentOut([]string{dialect.addForeignKey(item.table, item.fk)})...)
+	}
+
+	return ddls
+}
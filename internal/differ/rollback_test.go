@@ -0,0 +1,87 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestGenerateRollbackDDL(t *testing.T) {
+	sourceSQL := `CREATE TABLE users (
+		id INT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		nick VARCHAR(20),
+		legacy INT,
+		INDEX idx_legacy (legacy)
+	)`
+
+	targetSQL := `CREATE TABLE users (
+		id INT PRIMARY KEY,
+		full_name VARCHAR(100) NOT NULL,
+		nick VARCHAR(50),
+		email VARCHAR(255),
+		INDEX idx_email (email)
+	)`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	ddls := diff.GenerateRollbackDDL("users")
+	expected := []string{
+		"ALTER TABLE users RENAME COLUMN full_name TO name",
+		"-- ALTER TABLE users ADD COLUMN legacy INT",
+		DataLossWarning + "列 nick 改回 VARCHAR(20) 可能截断或转换数据\n" +
+			"ALTER TABLE users MODIFY COLUMN nick VARCHAR(20)",
+		"ALTER TABLE users DROP INDEX idx_email",
+		DataLossWarning + "列 email 中的数据将丢失\n" +
+			"ALTER TABLE users DROP COLUMN email",
+		"-- ALTER TABLE users ADD INDEX idx_legacy (legacy)",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestDatabaseRollbackDDL(t *testing.T) {
+	p := parser.NewParser()
+	source, _ := p.ParseScript(`
+		CREATE TABLE users (id INT PRIMARY KEY);
+		CREATE TABLE logs (id INT PRIMARY KEY);
+	`)
+	target, _ := p.ParseScript(`
+		CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50));
+		CREATE TABLE orders (id INT PRIMARY KEY, user_id INT, CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));
+		CREATE TABLE order_items (id INT PRIMARY KEY, order_id INT, CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id));
+	`)
+
+	diff := NewDatabaseDiffer(source, target).Compare()
+	ddls := diff.GenerateRollbackDDL()
+
+	expected := []string{
+		"ALTER TABLE orders DROP FOREIGN KEY fk_user",
+		DataLossWarning + "列 name 中的数据将丢失\nALTER TABLE users DROP COLUMN name",
+		DataLossWarning + "表 order_items 中的数据将丢失\nDROP TABLE order_items",
+		DataLossWarning + "表 orders 中的数据将丢失\nDROP TABLE orders",
+		"-- CREATE TABLE logs (\n--   id INT,\n--   PRIMARY KEY (id)\n-- )",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestRollbackEnumMembers(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (a ENUM('x','y'), b SET('x','y'))`
+	targetSQL := `CREATE TABLE t (a ENUM('x','y','z'), b SET('x'))`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	ddls := diff.GenerateRollbackDDL("t")
+	expected := []string{
+		DataLossWarning + "列 a 改回 ENUM('x','y') 可能截断或转换数据\n" +
+			"ALTER TABLE t MODIFY COLUMN a ENUM('x','y')",
+		"ALTER TABLE t MODIFY COLUMN b SET('x','y')",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
@@ -0,0 +1,466 @@
+package differ
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// sqliteDialect SQLite 方言
+// SQLite 的 ALTER TABLE 只能重命名表和列、新增列、删除列，索引通过独立的 CREATE INDEX / DROP INDEX 维护；
+// 修改列、主键、外键、唯一约束等无法直接 ALTER 的变更，按官方文档的 12 步流程重建表
+// （https://www.sqlite.org/lang_altertable.html#otheralter）
+type sqliteDialect struct{}
+
+// sqlitePlainIdent 无需加引号的 SQLite 标识符（SQLite 的标识符不区分大小写）
+var sqlitePlainIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqliteKeywords 作为标识符时需要加引号的 SQLite 关键字
+var sqliteKeywords = map[string]bool{
+	"ADD": true, "ALL": true, "ALTER": true, "AND": true, "AS": true, "AUTOINCREMENT": true,
+	"BETWEEN": true, "CASE": true, "CHECK": true, "COLLATE": true, "COLUMN": true, "COMMIT": true,
+	"CONSTRAINT": true, "CREATE": true, "CROSS": true, "CURRENT_DATE": true, "CURRENT_TIME": true,
+	"CURRENT_TIMESTAMP": true, "DEFAULT": true, "DEFERRABLE": true, "DELETE": true, "DISTINCT": true,
+	"DROP": true, "ELSE": true, "ESCAPE": true, "EXCEPT": true, "EXISTS": true, "FOREIGN": true,
+	"FROM": true, "FULL": true, "GROUP": true, "HAVING": true, "IN": true, "INDEX": true,
+	"INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true, "IS": true, "ISNULL": true,
+	"JOIN": true, "KEY": true, "LEFT": true, "LIMIT": true, "NATURAL": true, "NOT": true,
+	"NOTNULL": true, "NULL": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true,
+	"PRIMARY": true, "REFERENCES": true, "RETURNING": true, "RIGHT": true, "SELECT": true,
+	"SET": true, "TABLE": true, "THEN": true, "TO": true, "TRANSACTION": true, "UNION": true,
+	"UNIQUE": true, "UPDATE": true, "USING": true, "VALUES": true, "WHEN": true, "WHERE": true,
+}
+
+// sqliteAutoIndexPrefix 唯一约束对应的自动索引名前缀，这类索引只能随建表语句创建、随重建表删除
+const sqliteAutoIndexPrefix = "sqlite_autoindex_"
+
+// sqliteRebuildCheck 重建表时核对行数使用的临时表
+const sqliteRebuildCheck = "sql_diff_rebuild_check"
+
+// sqliteTableOptions 建表语句末尾的表选项及其输出顺序
+var sqliteTableOptions = []string{"WITHOUT ROWID", "STRICT"}
+
+func (sqliteDialect) quote(name string) string {
+	if sqlitePlainIdent.MatchString(name) && !sqliteKeywords[strings.ToUpper(name)] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// supportsColumnOrder SQLite 新增列总是追加在最后
+func (sqliteDialect) supportsColumnOrder() bool {
+	return false
+}
+
+// columnDefinition 自增列只能是 INTEGER PRIMARY KEY，主键和 AUTOINCREMENT 直接写在列定义中；
+// SQLite 允许省略类型
+func (sqliteDialect) columnDefinition(col *parser.Column) string {
+	var parts []string
+	if columnType := formatColumnType(col); columnType != "" {
+		parts = append(parts, columnType)
+	}
+	if col.Collation != "" {
+		parts = append(parts, "COLLATE "+col.Collation)
+	}
+	if col.AutoInc {
+		parts = append(parts, "PRIMARY KEY AUTOINCREMENT")
+	}
+	if col.Generated != "" {
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", col.Generated, generatedKind(col)))
+	}
+	if col.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.HasDefault() && col.Generated == "" {
+		parts = append(parts, "DEFAULT "+formatSQLiteDefault(col))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indexDefinition 唯一约束格式化为表定义中的 UNIQUE (...)，其余索引为独立的 CREATE INDEX 语句
+func (s sqliteDialect) indexDefinition(table string, idx *parser.Index) string {
+	if isSQLiteAutoIndex(idx) {
+		return fmt.Sprintf("UNIQUE (%s)", quoteList(idx.Columns, s.quote))
+	}
+	prefix := "CREATE INDEX"
+	if idx.Type == "UNIQUE" {
+		prefix = "CREATE UNIQUE INDEX"
+	}
+	return fmt.Sprintf("%s %s ON %s (%s)", prefix, s.quote(idx.Name), s.quote(table), formatKeyParts(idx, s.quote))
+}
+
+func (s sqliteDialect) foreignKeyDefinition(fk *parser.Constraint) string {
+	return formatNamedForeignKey(fk, s.quote)
+}
+
+func (s sqliteDialect) createTable(table *parser.TableSchema) []string {
+	return append([]string{s.tableDefinition(table.Name, table)}, s.createIndexes(table.Name, table)...)
+}
+
+// tableDefinition 以指定表名生成 CREATE TABLE 语句（重建表时先以临时表名创建）
+func (s sqliteDialect) tableDefinition(name string, table *parser.TableSchema) string {
+	var defs []string
+	inlinePrimaryKey := false
+	for _, col := range table.Columns {
+		defs = append(defs, strings.TrimSpace(s.quote(col.Name)+" "+s.columnDefinition(col)))
+		if col.AutoInc && len(table.PrimaryKeys) == 1 && strings.EqualFold(table.PrimaryKeys[0], col.Name) {
+			inlinePrimaryKey = true
+		}
+	}
+	if len(table.PrimaryKeys) > 0 && !inlinePrimaryKey {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(table.PrimaryKeys, s.quote)))
+	}
+	for _, idx := range table.Indexes {
+		if isSQLiteAutoIndex(idx) {
+			defs = append(defs, s.indexDefinition(table.Name, idx))
+		}
+	}
+	for _, fk := range table.ForeignKeys() {
+		defs = append(defs, s.foreignKeyDefinition(fk))
+	}
+	for _, check := range table.Checks() {
+		defs = append(defs, s.checkDefinition(check))
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", s.quote(name), strings.Join(defs, ",\n  "))
+	var options []string
+	for _, option := range sqliteTableOptions {
+		if _, ok := table.Options[option]; ok {
+			options = append(options, option)
+		}
+	}
+	if len(options) > 0 {
+		ddl += " " + strings.Join(options, ", ")
+	}
+	return ddl
+}
+
+// createIndexes 生成表中除唯一约束以外的建索引语句
+func (s sqliteDialect) createIndexes(table string, schema *parser.TableSchema) []string {
+	var ddls []string
+	for _, idx := range schema.Indexes {
+		if !isSQLiteAutoIndex(idx) {
+			ddls = append(ddls, s.indexDefinition(table, idx))
+		}
+	}
+	return ddls
+}
+
+func (s sqliteDialect) addColumn(table string, col *parser.Column, placement string) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", s.quote(table), strings.TrimSpace(s.quote(col.Name)+" "+s.columnDefinition(col)))}
+}
+
+// modifyColumn 列定义的修改总是通过重建表完成（见 needsRebuild）
+func (sqliteDialect) modifyColumn(table string, source, target *parser.Column, placement string) []string {
+	return nil
+}
+
+// renameColumn 同时修改定义的重命名通过重建表完成，这里只处理单纯的重命名
+func (s sqliteDialect) renameColumn(table string, rename *ColumnRename) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", s.quote(table), s.quote(rename.From), s.quote(rename.To))}
+}
+
+func (s sqliteDialect) dropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", s.quote(table), s.quote(column))
+}
+
+func (s sqliteDialect) addIndex(table string, idx *parser.Index) string {
+	return s.indexDefinition(table, idx)
+}
+
+// modifyIndex SQLite 没有不可见索引，仅可见性变化时不生成语句
+func (s sqliteDialect) modifyIndex(table string, diff *IndexDiff) []string {
+	if diff.VisibilityOnly() {
+		return nil
+	}
+	return []string{s.dropIndex(table, diff.Source), s.indexDefinition(table, diff.Target)}
+}
+
+// renameIndex SQLite 不能重命名索引，删除后按新名称重建
+func (s sqliteDialect) renameIndex(table string, rename *IndexRename) []string {
+	return []string{s.dropIndex(table, rename.Source), s.indexDefinition(table, rename.Target)}
+}
+
+func (s sqliteDialect) dropIndex(table string, idx *parser.Index) string {
+	return fmt.Sprintf("DROP INDEX %s", s.quote(idx.Name))
+}
+
+// addForeignKey SQLite 不能单独添加外键，外键变更总是通过重建表完成（见 needsRebuild）
+func (s sqliteDialect) addForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("-- SQLite 不支持单独添加外键，需要重建表 %s: %s", table, s.foreignKeyDefinition(fk))
+}
+
+// dropForeignKey SQLite 不能单独删除外键，外键变更总是通过重建表完成（见 needsRebuild）
+func (s sqliteDialect) dropForeignKey(table string, fk *parser.Constraint) string {
+	return fmt.Sprintf("-- SQLite 不支持单独删除外键，需要重建表 %s: %s", table, s.foreignKeyDefinition(fk))
+}
+
+func (s sqliteDialect) checkDefinition(check *parser.Constraint) string {
+	return formatCheck(check, s.quote)
+}
+
+// addCheck SQLite 不能单独添加 CHECK 约束，约束变更总是通过重建表完成（见 needsRebuild）
+func (s sqliteDialect) addCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("-- SQLite 不支持单独添加 CHECK 约束，需要重建表 %s: %s", table, s.checkDefinition(check))
+}
+
+// modifyCheck CHECK 约束的修改总是通过重建表完成（见 needsRebuild）
+func (sqliteDialect) modifyCheck(table string, diff *ConstraintDiff) []string {
+	return nil
+}
+
+// dropCheck SQLite 不能单独删除 CHECK 约束，约束变更总是通过重建表完成（见 needsRebuild）
+func (s sqliteDialect) dropCheck(table string, check *parser.Constraint) string {
+	return fmt.Sprintf("-- SQLite 不支持单独删除 CHECK 约束，需要重建表 %s: %s", table, s.checkDefinition(check))
+}
+
+// deferForeignKeys SQLite 建表时不检查被引用的表是否存在，外键总是随建表语句定义
+func (sqliteDialect) deferForeignKeys() bool {
+	return false
+}
+
+// primaryKey 主键变更总是通过重建表完成（见 needsRebuild）
+func (sqliteDialect) primaryKey(d *Diff, table string) (string, map[string]bool, string) {
+	return "", nil, ""
+}
+
+// needsRebuild 判断差异中是否存在 SQLite 无法直接 ALTER 的变更
+// 正向迁移中删除列、删除索引的语句会被注释，回滚时新增列、新增索引的语句会被注释，这些操作不需要考虑
+func (s sqliteDialect) needsRebuild(d *Diff, rollback bool) bool {
+	if d.PrimaryKey != nil || len(d.ModifiedColumns) > 0 || len(d.TableOptions) > 0 ||
+		len(d.AddedForeignKeys) > 0 || len(d.RemovedForeignKeys) > 0 || len(d.ModifiedForeignKeys) > 0 ||
+		len(d.AddedChecks) > 0 || len(d.RemovedChecks) > 0 || len(d.ModifiedChecks) > 0 {
+		return true
+	}
+	for _, rename := range d.RenamedColumns {
+		if len(rename.Changes) > 0 {
+			return true
+		}
+	}
+	for _, idxDiff := range d.ModifiedIndexes {
+		if isSQLiteAutoIndex(idxDiff.Source) || isSQLiteAutoIndex(idxDiff.Target) {
+			return true
+		}
+	}
+	for _, rename := range d.RenamedIndexes {
+		if isSQLiteAutoIndex(rename.Source) || isSQLiteAutoIndex(rename.Target) {
+			return true
+		}
+	}
+	// 唯一约束只能通过重建表删除（删除约束不会丢失数据，因此正向迁移也直接执行）
+	for _, idx := range d.RemovedIndexes {
+		if isSQLiteAutoIndex(idx) {
+			return true
+		}
+	}
+
+	if rollback {
+		for _, col := range d.RemovedColumns {
+			if sqliteColumnInUse(d.source, col.Name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, col := range d.AddedColumns {
+		if !sqliteCanAddColumn(d.target, col) {
+			return true
+		}
+	}
+	for _, idx := range d.AddedIndexes {
+		if isSQLiteAutoIndex(idx) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTable 按官方的 12 步流程重建表：
+// 关闭外键检查 → 开启事务 → 创建新表 → 复制数据 → 删除旧表 → 新表改名 → 重建索引 → 核对行数 → 检查外键 → 提交 → 恢复外键检查
+//
+// 正向迁移时被删除的列和索引仍保留在新表中，随后输出注释掉的删除语句，与其他方言一致；
+// 回滚时新增列（即正向迁移中未真正删除的列）视为仍然存在，数据从旧表复制
+func (s sqliteDialect) rebuildTable(d *Diff, table string, rollback bool) []string {
+	layout := *d.target
+	layout.Columns = append([]*parser.Column(nil), d.target.Columns...)
+	layout.Indexes = append([]*parser.Index(nil), d.target.Indexes...)
+	if !rollback {
+		layout.Columns = append(layout.Columns, d.RemovedColumns...)
+		for _, idx := range d.RemovedIndexes {
+			if !isSQLiteAutoIndex(idx) {
+				layout.Indexes = append(layout.Indexes, idx)
+			}
+		}
+	}
+
+	// 新表的列 -> 旧表中对应的列（小写列名）
+	sources := make(map[string]string)
+	for _, col := range d.source.Columns {
+		sources[strings.ToLower(col.Name)] = col.Name
+	}
+	if rollback {
+		for _, col := range d.AddedColumns {
+			sources[strings.ToLower(col.Name)] = col.Name
+		}
+	}
+	for _, rename := range d.RenamedColumns {
+		delete(sources, strings.ToLower(rename.From))
+	}
+	for _, rename := range d.RenamedColumns {
+		sources[strings.ToLower(rename.To)] = rename.From
+	}
+
+	// 生成列的值由表达式计算，不能插入；改为 NOT NULL 的列，原有的 NULL 取默认值
+	var columns, values, warnings []string
+	for _, col := range layout.Columns {
+		if col.Generated != "" {
+			continue
+		}
+		from, ok := sources[strings.ToLower(col.Name)]
+		if !ok {
+			if col.NotNull && !sqliteHasDefault(col) {
+				warnings = append(warnings, fmt.Sprintf("新增的列 %s 为 NOT NULL 且没有默认值，表中已有数据时复制会失败", col.Name))
+			}
+			continue
+		}
+		value := s.quote(from)
+		if col.NotNull && !sqliteNotNull(d.source, from) {
+			if sqliteHasDefault(col) {
+				value = fmt.Sprintf("COALESCE(%s, %s)", value, formatSQLiteDefault(col))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("列 %s 改为 NOT NULL 且没有默认值，已有 NULL 数据时复制会失败", col.Name))
+			}
+		}
+		columns = append(columns, s.quote(col.Name))
+		values = append(values, value)
+	}
+
+	temp := "new_" + table
+	ddls := []string{
+		"PRAGMA foreign_keys = OFF",
+		"BEGIN TRANSACTION",
+		s.tableDefinition(temp, &layout),
+	}
+	if len(columns) > 0 {
+		ddls = append(ddls, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			s.quote(temp), strings.Join(columns, ", "), strings.Join(values, ", "), s.quote(table)))
+	}
+	// sqlite3 命令行遇到错误后会继续执行后续语句：删除旧表前记录行数，提交前核对，
+	// 复制失败导致行数不一致时 UPDATE OR ROLLBACK 违反 NOT NULL 约束，回滚整个事务，旧表保持不变
+	ddls = append(ddls,
+		fmt.Sprintf("CREATE TEMP TABLE %s (row_count INTEGER NOT NULL)", sqliteRebuildCheck),
+		fmt.Sprintf("INSERT INTO %s SELECT COUNT(*) FROM %s", sqliteRebuildCheck, s.quote(table)),
+		fmt.Sprintf("DROP TABLE %s", s.quote(table)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", s.quote(temp), s.quote(table)),
+	)
+	ddls = append(ddls, s.createIndexes(table, &layout)...)
+	ddls = append(ddls,
+		fmt.Sprintf("UPDATE OR ROLLBACK %s SET row_count = NULL WHERE row_count <> (SELECT COUNT(*) FROM %s)", sqliteRebuildCheck, s.quote(table)),
+		fmt.Sprintf("DROP TABLE %s", sqliteRebuildCheck),
+		"PRAGMA foreign_key_check", "COMMIT", "PRAGMA foreign_keys = ON")
+
+	header := fmt.Sprintf("-- SQLite 无法直接修改表 %s 的结构，通过重建表完成\n", table)
+	for _, warning := range warnings {
+		header += "-- 警告: " + warning + "，事务将回滚\n"
+	}
+	if rollback {
+		for _, col := range d.RemovedColumns {
+			header += dataLossWarning(fmt.Sprintf("列 %s 中的数据将丢失", col.Name))
+		}
+	}
+	ddls[0] = header + ddls[0]
+
+	if !rollback {
+		for _, idx := range d.RemovedIndexes {
+			if !isSQLiteAutoIndex(idx) {
+				ddls = append(ddls, "-- "+s.dropIndex(table, idx))
+			}
+		}
+		for _, col := range d.RemovedColumns {
+			ddls = append(ddls, "-- "+s.dropColumn(table, col.Name))
+		}
+	}
+	return ddls
+}
+
+// isSQLiteAutoIndex 判断索引是否为唯一约束对应的自动索引
+func isSQLiteAutoIndex(idx *parser.Index) bool {
+	return idx != nil && strings.HasPrefix(idx.Name, sqliteAutoIndexPrefix)
+}
+
+// sqliteCanAddColumn 判断列能否通过 ALTER TABLE ADD COLUMN 直接新增
+// SQLite 新增的列不能是主键、唯一约束或存储生成列，默认值不能是 CURRENT_TIMESTAMP 等或括号表达式，
+// NOT NULL 列必须有默认值（虚拟生成列除外）
+func sqliteCanAddColumn(table *parser.TableSchema, col *parser.Column) bool {
+	if col.AutoInc || containsColumn(table.PrimaryKeys, col.Name) || (col.Generated != "" && col.Stored) {
+		return false
+	}
+	if col.Generated != "" {
+		return true
+	}
+	for _, idx := range table.Indexes {
+		if isSQLiteAutoIndex(idx) && containsColumn(idx.Columns, col.Name) {
+			return false
+		}
+	}
+	if !col.DefaultQuoted && (strings.HasPrefix(col.DefaultValue, "CURRENT_") || strings.HasPrefix(col.DefaultValue, "(") ||
+		defaultFunctionCall.MatchString(col.DefaultValue)) {
+		return false
+	}
+	return !col.NotNull || sqliteHasDefault(col)
+}
+
+// sqliteHasDefault 判断列是否有非 NULL 的默认值
+func sqliteHasDefault(col *parser.Column) bool {
+	return col.DefaultQuoted || (col.DefaultValue != "" && col.DefaultValue != "NULL")
+}
+
+// sqliteNotNull 判断表中的列是否为 NOT NULL（主键列同样不会为 NULL）
+func sqliteNotNull(table *parser.TableSchema, name string) bool {
+	col := table.Column(name)
+	return col != nil && (col.NotNull || col.AutoInc)
+}
+
+// sqliteColumnInUse 判断列是否被主键、索引、外键、其他列的 CHECK 约束或生成列使用
+// （此时无法通过 ALTER TABLE DROP COLUMN 删除）
+func sqliteColumnInUse(table *parser.TableSchema, name string) bool {
+	if containsColumn(table.PrimaryKeys, name) {
+		return true
+	}
+	for _, idx := range table.Indexes {
+		if containsColumn(idx.Columns, name) {
+			return true
+		}
+	}
+	for _, fk := range table.ForeignKeys() {
+		if containsColumn(fk.Columns, name) {
+			return true
+		}
+	}
+	for _, check := range table.Checks() {
+		if !containsColumn(check.Columns, name) && containsColumn(parser.ExpressionColumns(check.Definition, table, parser.SQLite), name) {
+			return true
+		}
+	}
+	for _, col := range table.Columns {
+		if col.Generated != "" && containsColumn(parser.ExpressionColumns(col.Generated, table, parser.SQLite), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSQLiteDefault 格式化默认值：字符串字面量加引号，数字、关键字和括号表达式保持原样，
+// 函数调用需要用括号包裹（SQLite 的要求）
+func formatSQLiteDefault(col *parser.Column) string {
+	switch {
+	case col.DefaultQuoted:
+		return quoteStringLiteral(col.DefaultValue)
+	case defaultFunctionCall.MatchString(col.DefaultValue):
+		return "(" + col.DefaultValue + ")"
+	}
+	return col.DefaultValue
+}
@@ -0,0 +1,224 @@
+package differ
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	_ "modernc.org/sqlite"
+)
+
+// openSQLite 打开内存中的 SQLite 数据库并执行初始化脚本
+func openSQLite(t *testing.T, script string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只能使用同一个连接
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	execSQLite(t, db, []string{script})
+	return db
+}
+
+// execSQLite 依次执行语句
+func execSQLite(t *testing.T, db *sql.DB, statements []string) {
+	t.Helper()
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("执行失败: %v\n%s", err, stmt)
+		}
+	}
+}
+
+// dumpSQLite 读取数据库中的建表、建索引语句
+func dumpSQLite(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	rows, err := db.Query("SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY type DESC, name")
+	if err != nil {
+		t.Fatalf("读取结构失败: %v", err)
+	}
+	defer rows.Close()
+	var statements []string
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			t.Fatalf("读取结构失败: %v", err)
+		}
+		statements = append(statements, stmt)
+	}
+	return strings.Join(statements, ";\n")
+}
+
+func TestSQLiteGenerateDDL(t *testing.T) {
+	sourceSQL := `
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, nick TEXT);
+CREATE INDEX idx_nick ON users (nick);
+`
+	targetSQL := `
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, nick TEXT, "group" TEXT DEFAULT 'a');
+CREATE INDEX idx_nickname ON users (nick);
+CREATE INDEX idx_group ON users ("group");
+`
+	diff := compareDialect(t, parser.SQLite, sourceSQL, targetSQL)
+
+	expected := []string{
+		"DROP INDEX idx_nick",
+		"CREATE INDEX idx_nickname ON users (nick)",
+		`ALTER TABLE users ADD COLUMN "group" TEXT DEFAULT 'a'`,
+		`CREATE INDEX idx_group ON users ("group")`,
+	}
+	if ddls := diff.GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestSQLiteRebuildTable(t *testing.T) {
+	sourceSQL := `
+CREATE TABLE orgs (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL,
+  name VARCHAR(50),
+  age INT
+);
+CREATE INDEX idx_name ON users (name);
+`
+	targetSQL := `
+CREATE TABLE orgs (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL UNIQUE,
+  name VARCHAR(100),
+  age INTEGER NOT NULL DEFAULT 0,
+  org_id INTEGER REFERENCES orgs (id) ON DELETE CASCADE,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_name ON users (name, age);
+`
+	diff := compareDialect(t, parser.SQLite, sourceSQL, targetSQL)
+	ddls := diff.GenerateDDL()
+
+	expected := []string{
+		"-- SQLite 无法直接修改表 users 的结构，通过重建表完成\nPRAGMA foreign_keys = OFF",
+		"BEGIN TRANSACTION",
+		`CREATE TABLE new_users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL,
+  name VARCHAR(100),
+  age INTEGER NOT NULL DEFAULT 0,
+  org_id INTEGER,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (email),
+  CONSTRAINT users_ibfk_1 FOREIGN KEY (org_id) REFERENCES orgs (id) ON DELETE CASCADE
+)`,
+		"INSERT INTO new_users (id, email, name, age) SELECT id, email, name, COALESCE(age, 0) FROM users",
+		"CREATE TEMP TABLE sql_diff_rebuild_check (row_count INTEGER NOT NULL)",
+		"INSERT INTO sql_diff_rebuild_check SELECT COUNT(*) FROM users",
+		"DROP TABLE users",
+		"ALTER TABLE new_users RENAME TO users",
+		"CREATE INDEX idx_name ON users (name, age)",
+		"UPDATE OR ROLLBACK sql_diff_rebuild_check SET row_count = NULL WHERE row_count <> (SELECT COUNT(*) FROM users)",
+		"DROP TABLE sql_diff_rebuild_check",
+		"PRAGMA foreign_key_check",
+		"COMMIT",
+		"PRAGMA foreign_keys = ON",
+	}
+	if strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("重建表 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	// 在真实的 SQLite 中执行迁移，数据应保留，结构应与目标一致
+	db := openSQLite(t, sourceSQL)
+	execSQLite(t, db, []string{
+		"PRAGMA foreign_keys = ON",
+		"INSERT INTO orgs (id, name) VALUES (1, 'acme')",
+		"INSERT INTO users (email, name, age) VALUES ('a@example.com', 'alice', 30), ('b@example.com', 'bob', 25)",
+	})
+	execSQLite(t, db, ddls)
+
+	var count, ageSum int
+	if err := db.QueryRow("SELECT COUNT(*), SUM(age) FROM users").Scan(&count, &ageSum); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if count != 2 || ageSum != 55 {
+		t.Errorf("重建后数据不正确: %d 行，age 合计 %d", count, ageSum)
+	}
+	if _, err := db.Exec("INSERT INTO users (email) VALUES ('a@example.com')"); err == nil {
+		t.Error("重建后的唯一约束没有生效")
+	}
+
+	migrated := compareDialect(t, parser.SQLite, dumpSQLite(t, db), targetSQL)
+	if migrated.HasChanges() {
+		t.Errorf("迁移后的结构与目标不一致:\n%s", strings.Join(migrated.GenerateDDL(), "\n"))
+	}
+
+	// 回滚后结构应与源一致
+	execSQLite(t, db, diff.GenerateRollbackDDL())
+	rolledBack := compareDialect(t, parser.SQLite, dumpSQLite(t, db), sourceSQL)
+	if rolledBack.HasChanges() {
+		t.Errorf("回滚后的结构与源不一致:\n%s", strings.Join(rolledBack.GenerateDDL(), "\n"))
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE name IS NOT NULL").Scan(&count); err != nil || count != 2 {
+		t.Errorf("回滚后数据不正确: %d 行（%v）", count, err)
+	}
+}
+
+func TestSQLiteRebuildKeepsRemovedColumns(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, a INT, legacy TEXT);`
+	targetSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, a BIGINT NOT NULL DEFAULT 0);`
+
+	ddls := compareDialect(t, parser.SQLite, sourceSQL, targetSQL).GenerateDDL()
+
+	joined := strings.Join(ddls, "\n")
+	if !strings.Contains(joined, "INSERT INTO new_t (id, a, legacy) SELECT id, COALESCE(a, 0), legacy FROM t") {
+		t.Errorf("重建表时应保留被删除的列，改为 NOT NULL 的列取默认值:\n%s", joined)
+	}
+	if last := ddls[len(ddls)-1]; last != "-- ALTER TABLE t DROP COLUMN legacy" {
+		t.Errorf("删除列的语句应在重建之后以注释形式输出，实际为 %s", last)
+	}
+
+	db := openSQLite(t, sourceSQL)
+	execSQLite(t, db, []string{"INSERT INTO t (a, legacy) VALUES (NULL, 'x')"})
+	execSQLite(t, db, ddls)
+	var a int
+	if err := db.QueryRow("SELECT a FROM t").Scan(&a); err != nil || a != 0 {
+		t.Errorf("原有的 NULL 应取默认值 0，实际为 %d（%v）", a, err)
+	}
+}
+
+func TestSQLiteRebuildRollsBackOnCopyFailure(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, a INT);`
+	for _, targetSQL := range []string{
+		`CREATE TABLE t (id INTEGER PRIMARY KEY, a INT, b TEXT NOT NULL);`,
+		`CREATE TABLE t (id INTEGER PRIMARY KEY, a BIGINT NOT NULL);`,
+	} {
+		ddls := compareDialect(t, parser.SQLite, sourceSQL, targetSQL).GenerateDDL()
+		if !strings.Contains(ddls[0], "-- 警告: ") {
+			t.Errorf("NOT NULL 且没有默认值的列应输出警告:\n%s", ddls[0])
+		}
+
+		// 与 sqlite3 命令行一致：语句出错后继续执行后续语句
+		db := openSQLite(t, sourceSQL)
+		execSQLite(t, db, []string{"INSERT INTO t (a) VALUES (1), (NULL)"})
+		failed := 0
+		for _, ddl := range ddls {
+			if _, err := db.Exec(ddl); err != nil {
+				failed++
+			}
+		}
+		if failed == 0 {
+			t.Errorf("复制数据应失败:\n%s", strings.Join(ddls, "\n"))
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM t").Scan(&count); err != nil || count != 2 {
+			t.Errorf("复制失败时应回滚，原表数据不应丢失: %d 行（%v）", count, err)
+		}
+		if rolledBack := compareDialect(t, parser.SQLite, dumpSQLite(t, db), sourceSQL); rolledBack.HasChanges() {
+			t.Errorf("复制失败时表结构应保持不变:\n%s", dumpSQLite(t, db))
+		}
+	}
+}
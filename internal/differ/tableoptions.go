@@ -0,0 +1,385 @@
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// TableOptionDiff 表选项的差异详情
+type TableOptionDiff struct {
+	Name   string // 选项名，如 ENGINE、CHARSET、ROW_FORMAT
+	Source string // 原值（未设置时为空）
+	Target string // 新值（未设置时为空，生成语句时改回选项的默认值）
+	Change string // 变更描述
+}
+
+// mysqlTableOptionDefaults MySQL 表选项未设置时的取值，也是生成语句时重置选项使用的值
+// 不在其中的选项（如 TABLESPACE、DATA DIRECTORY）只有一侧设置时无法判断是否变化，不做比对
+var mysqlTableOptionDefaults = map[string]string{
+	"ENGINE": "InnoDB", "COMMENT": "", "ROW_FORMAT": "DEFAULT", "KEY_BLOCK_SIZE": "0",
+	"STATS_PERSISTENT": "DEFAULT", "STATS_AUTO_RECALC": "DEFAULT", "STATS_SAMPLE_PAGES": "DEFAULT",
+	"CHECKSUM": "0", "DELAY_KEY_WRITE": "0", "PACK_KEYS": "DEFAULT",
+	"AVG_ROW_LENGTH": "0", "MAX_ROWS": "0", "MIN_ROWS": "0",
+}
+
+// postgresTableOptionDefaults PostgreSQL 表选项未设置时的取值
+var postgresTableOptionDefaults = map[string]string{
+	"COMMENT": "", "TABLESPACE": "pg_default", "WITH": "",
+}
+
+// mysqlDefaultCollations MySQL 8.0 中各字符集的默认排序规则（只指定字符集时据此判断排序规则是否变化）
+var mysqlDefaultCollations = map[string]string{
+	"utf8mb4": "utf8mb4_0900_ai_ci", "utf8mb3": "utf8mb3_general_ci", "utf8": "utf8mb3_general_ci",
+	"latin1": "latin1_swedish_ci", "ascii": "ascii_general_ci", "binary": "binary",
+	"gbk": "gbk_chinese_ci", "gb2312": "gb2312_chinese_ci", "gb18030": "gb18030_chinese_ci",
+	"big5": "big5_chinese_ci", "ucs2": "ucs2_general_ci", "utf16": "utf16_general_ci", "utf32": "utf32_general_ci",
+}
+
+// tableOptionDefaults 返回方言中表选项未设置时的取值
+func tableOptionDefaults(dialect parser.Dialect) map[string]string {
+	switch dialect {
+	case parser.PostgreSQL:
+		return postgresTableOptionDefaults
+	case parser.SQLite:
+		return nil
+	}
+	return mysqlTableOptionDefaults
+}
+
+// compareTableOptions 比较两张表的表选项
+// AUTO_INCREMENT 计数器默认不比较；只有一侧设置且没有已知默认值的选项不比较；
+// 无值的选项（如 SQLite 的 WITHOUT ROWID）按是否设置比较
+func compareTableOptions(source, target *parser.TableSchema, options Options) []*TableOptionDiff {
+	diffs := compareCharsetOptions(source.Options, target.Options)
+	defaults := tableOptionDefaults(options.Dialect)
+
+	for _, name := range tableOptionNames(source.Options, target.Options) {
+		if name == "CHARSET" || name == "COLLATE" || (name == "AUTO_INCREMENT" && !options.CompareAutoIncrement) {
+			continue
+		}
+		sourceValue, inSource := source.Options[name]
+		targetValue, inTarget := target.Options[name]
+
+		def, hasDefault := defaults[name]
+		switch {
+		case inSource && inTarget:
+			if equalTableOption(name, sourceValue, targetValue) {
+				continue
+			}
+		case hasDefault:
+			if (!inSource && equalTableOption(name, def, targetValue)) || (!inTarget && equalTableOption(name, sourceValue, def)) {
+				continue
+			}
+		case sourceValue != "" || targetValue != "":
+			// 只有一侧设置且没有已知默认值
+			continue
+		}
+
+		diffs = append(diffs, &TableOptionDiff{
+			Name:   name,
+			Source: sourceValue,
+			Target: targetValue,
+			Change: describeTableOption(name, sourceValue, inSource, targetValue, inTarget),
+		})
+	}
+	return diffs
+}
+
+// compareCharsetOptions 比较表的默认字符集和排序规则
+// 只指定排序规则时字符集取排序规则的前缀，只指定字符集时排序规则取该字符集的默认值；
+// 任一侧无法确定字符集时（使用数据库默认值）不比较
+func compareCharsetOptions(source, target map[string]string) []*TableOptionDiff {
+	sourceCharset, targetCharset := effectiveCharset(source), effectiveCharset(target)
+	if sourceCharset == "" || targetCharset == "" {
+		return nil
+	}
+
+	var diffs []*TableOptionDiff
+	if sourceCharset != targetCharset {
+		diffs = append(diffs, &TableOptionDiff{
+			Name:   "CHARSET",
+			Source: sourceCharset,
+			Target: targetCharset,
+			Change: fmt.Sprintf("默认字符集从 %s 改为 %s", sourceCharset, targetCharset),
+		})
+	}
+	sourceCollation, targetCollation := effectiveCollation(source), effectiveCollation(target)
+	if sourceCollation != "" && targetCollation != "" && sourceCollation != targetCollation {
+		diffs = append(diffs, &TableOptionDiff{
+			Name:   "COLLATE",
+			Source: sourceCollation,
+			Target: targetCollation,
+			Change: fmt.Sprintf("默认排序规则从 %s 改为 %s", sourceCollation, targetCollation),
+		})
+	}
+	return diffs
+}
+
+// effectiveCharset 返回表的默认字符集（小写，无法确定时为空）
+func effectiveCharset(options map[string]string) string {
+	if charset := options["CHARSET"]; charset != "" {
+		return strings.ToLower(charset)
+	}
+	if collation := options["COLLATE"]; collation != "" {
+		charset, _, _ := strings.Cut(strings.ToLower(collation), "_")
+		return charset
+	}
+	return ""
+}
+
+// effectiveCollation 返回表的默认排序规则（小写，无法确定时为空）
+func effectiveCollation(options map[string]string) string {
+	if collation := options["COLLATE"]; collation != "" {
+		return strings.ToLower(collation)
+	}
+	return mysqlDefaultCollations[effectiveCharset(options)]
+}
+
+// tableOptionNames 返回两侧出现过的表选项名称，按 CREATE TABLE 的输出顺序排列
+func tableOptionNames(source, target map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range tableOptionOrder {
+		_, inSource := source[name]
+		_, inTarget := target[name]
+		if inSource || inTarget {
+			names = append(names, name)
+		}
+		seen[name] = true
+	}
+
+	var rest []string
+	for _, options := range []map[string]string{source, target} {
+		for name := range options {
+			if !seen[name] {
+				rest = append(rest, name)
+				seen[name] = true
+			}
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// equalTableOption 比较表选项的值（注释区分大小写，其余选项不区分）
+func equalTableOption(name, a, b string) bool {
+	if name == "COMMENT" {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+// describeTableOption 生成表选项变更的描述
+func describeTableOption(name, source string, inSource bool, target string, inTarget bool) string {
+	switch {
+	case inSource && inTarget:
+		return fmt.Sprintf("表选项 %s 从 %s 改为 %s", name, formatOptionValue(name, source), formatOptionValue(name, target))
+	case inTarget && target == "":
+		return fmt.Sprintf("添加了表选项 %s", name)
+	case inSource && source == "":
+		return fmt.Sprintf("移除了表选项 %s", name)
+	case inTarget:
+		return fmt.Sprintf("表选项 %s 从 默认值 改为 %s", name, formatOptionValue(name, target))
+	default:
+		return fmt.Sprintf("表选项 %s 从 %s 改为 默认值", name, formatOptionValue(name, source))
+	}
+}
+
+// formatOptionValue 格式化描述中的选项值（注释加引号）
+func formatOptionValue(name, value string) string {
+	if name == "COMMENT" {
+		return quoteStringLiteral(value)
+	}
+	return value
+}
+
+// tableOption 返回表选项差异中指定名称的一项（不存在时为 nil）
+func (d *Diff) tableOption(name string) *TableOptionDiff {
+	for _, option := range d.TableOptions {
+		if option.Name == name {
+			return option
+		}
+	}
+	return nil
+}
+
+// convertsCharset 判断修改表的默认字符集、排序规则时是否需要转换已有列（CONVERT TO）
+// 源表和目标表中都使用表默认字符集的字符列，其实际字符集会随表的默认值变化，
+// 只修改 DEFAULT CHARSET 不会转换这些列，必须使用 CONVERT TO CHARACTER SET
+func (d *Diff) convertsCharset() bool {
+	if d.tableOption("CHARSET") == nil && d.tableOption("COLLATE") == nil {
+		return false
+	}
+
+	sourceColumns := make(map[string]*parser.Column)
+	for _, col := range d.source.Columns {
+		sourceColumns[col.Name] = col
+	}
+	for _, rename := range d.RenamedColumns {
+		sourceColumns[rename.To] = rename.Source
+	}
+	for _, col := range d.target.Columns {
+		source, ok := sourceColumns[col.Name]
+		if ok && inheritsTableCharset(col) && inheritsTableCharset(source) {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritsTableCharset 判断列是否为使用表默认字符集的字符列
+func inheritsTableCharset(col *parser.Column) bool {
+	return col.UsesCharset() && col.Charset == "" && col.Collation == ""
+}
+
+// tableOptionsRisk 评估表选项变更的风险
+// 转换字符集可能截断或丢失无法表示的字符，更换存储引擎会复制整张表并改变事务、外键等行为
+func (d *Diff) tableOptionsRisk() RiskLevel {
+	if d.tableOption("ENGINE") != nil || (d.tableOption("CHARSET") != nil && d.convertsCharset()) {
+		return RiskWarning
+	}
+	return RiskSafe
+}
+
+// formatTableOptionDiffs 格式化表选项差异中一侧的取值，如 ENGINE=MyISAM DEFAULT CHARSET=latin1
+func formatTableOptionDiffs(diffs []*TableOptionDiff, target bool) string {
+	options := make(map[string]string)
+	for _, diff := range diffs {
+		value := diff.Source
+		if target {
+			value = diff.Target
+		}
+		if value != "" {
+			options[diff.Name] = value
+		}
+	}
+	return formatTableOptions(options)
+}
+
+// alterTableOptions 生成 MySQL 修改表选项的语句
+// 字符集、排序规则变化时，若已有列使用表默认字符集，使用 CONVERT TO 转换这些列，
+// 转换会覆盖显式指定了字符集、排序规则的列，随后逐列改回目标定义；否则只修改 DEFAULT CHARSET
+func (m mysqlDialect) alterTableOptions(d *Diff, table string) []string {
+	var clauses []string
+	convert := d.convertsCharset()
+
+	if d.tableOption("CHARSET") != nil || d.tableOption("COLLATE") != nil {
+		charset := effectiveCharset(d.target.Options)
+		collation := strings.ToLower(d.target.Options["COLLATE"])
+		if d.tableOption("CHARSET") == nil && collation == "" {
+			collation = effectiveCollation(d.target.Options)
+		}
+		switch {
+		case convert && collation != "":
+			clauses = append(clauses, fmt.Sprintf("CONVERT TO CHARACTER SET %s COLLATE %s", charset, collation))
+		case convert:
+			clauses = append(clauses, "CONVERT TO CHARACTER SET "+charset)
+		case collation != "":
+			clauses = append(clauses, fmt.Sprintf("DEFAULT CHARSET=%s COLLATE=%s", charset, collation))
+		default:
+			clauses = append(clauses, "DEFAULT CHARSET="+charset)
+		}
+	}
+
+	for _, option := range d.TableOptions {
+		if option.Name == "CHARSET" || option.Name == "COLLATE" {
+			continue
+		}
+		value := option.Target
+		if _, ok := d.target.Options[option.Name]; !ok {
+			value = mysqlTableOptionDefaults[option.Name]
+		}
+		clauses = append(clauses, formatTableOption(option.Name, value))
+	}
+
+	if len(clauses) == 0 {
+		return nil
+	}
+	ddls := []string{fmt.Sprintf("ALTER TABLE %s %s", quoteMySQLIdent(table), strings.Join(clauses, ", "))}
+	if !convert {
+		return ddls
+	}
+
+	// 恢复显式指定了字符集、排序规则的已有列（新增列和修改列会按目标定义单独生成语句）
+	skip := make(map[string]bool)
+	for _, col := range d.AddedColumns {
+		skip[col.Name] = true
+	}
+	for _, colDiff := range d.ModifiedColumns {
+		skip[colDiff.Name] = true
+	}
+	for _, col := range d.target.Columns {
+		if !skip[col.Name] && col.UsesCharset() && (col.Charset != "" || col.Collation != "") {
+			ddls = append(ddls, m.modifyColumn(table, col, col, "")...)
+		}
+	}
+	return ddls
+}
+
+// alterTableOptions 生成 PostgreSQL 修改表选项的语句
+// 支持表注释、表空间和存储参数，其余选项（如 INHERITS）需要手动处理
+func (p postgresDialect) alterTableOptions(d *Diff, table string) []string {
+	var ddls []string
+	for _, option := range d.TableOptions {
+		_, inTarget := d.target.Options[option.Name]
+		switch option.Name {
+		case "COMMENT":
+			comment := "NULL"
+			if option.Target != "" {
+				comment = quoteStringLiteral(option.Target)
+			}
+			ddls = append(ddls, fmt.Sprintf("COMMENT ON TABLE %s IS %s", p.quote(table), comment))
+		case "TABLESPACE":
+			tablespace := postgresTableOptionDefaults["TABLESPACE"]
+			if inTarget {
+				tablespace = option.Target
+			}
+			ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s SET TABLESPACE %s", p.quote(table), p.quote(tablespace)))
+		case "WITH":
+			ddls = append(ddls, p.alterStorageParameters(table, option)...)
+		default:
+			ddls = append(ddls, fmt.Sprintf("-- PostgreSQL 不支持直接修改表 %s 的 %s 选项，需要手动处理: %s", table, option.Name, option.Change))
+		}
+	}
+	return ddls
+}
+
+// alterStorageParameters 生成修改存储参数的语句，目标中不再设置的参数恢复默认值
+func (p postgresDialect) alterStorageParameters(table string, option *TableOptionDiff) []string {
+	targetParams := storageParameterNames(option.Target)
+	var reset []string
+	for _, name := range storageParameterNames(option.Source) {
+		if !containsColumn(targetParams, name) {
+			reset = append(reset, name)
+		}
+	}
+
+	var ddls []string
+	if len(reset) > 0 {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s RESET (%s)", p.quote(table), strings.Join(reset, ", ")))
+	}
+	if option.Target != "" {
+		ddls = append(ddls, fmt.Sprintf("ALTER TABLE %s SET (%s)", p.quote(table), option.Target))
+	}
+	return ddls
+}
+
+// storageParameterNames 从 WITH 子句的原文（如 "fillfactor=70, autovacuum_enabled=false"）中提取参数名
+func storageParameterNames(raw string) []string {
+	var names []string
+	for _, param := range strings.Split(raw, ",") {
+		name, _, _ := strings.Cut(param, "=")
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	return names
+}
+
+// alterTableOptions SQLite 的表选项只能通过重建表修改（needsRebuild 会先处理），不会单独生成语句
+func (s sqliteDialect) alterTableOptions(d *Diff, table string) []string {
+	return nil
+}
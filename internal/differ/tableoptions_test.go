@@ -0,0 +1,173 @@
+package differ
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+func TestDiffTableOptions(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INT PRIMARY KEY) ENGINE=MyISAM AUTO_INCREMENT=10 ROW_FORMAT=COMPACT COMMENT='old'`
+	targetSQL := `CREATE TABLE t (id INT PRIMARY KEY) ENGINE=InnoDB AUTO_INCREMENT=100 KEY_BLOCK_SIZE=8 COMMENT='new'`
+
+	diff := compareSQL(t, sourceSQL, targetSQL, DefaultOptions())
+
+	expectedChanges := []string{
+		"表选项 ENGINE 从 MyISAM 改为 InnoDB",
+		"表选项 ROW_FORMAT 从 COMPACT 改为 默认值",
+		"表选项 COMMENT 从 'old' 改为 'new'",
+		"表选项 KEY_BLOCK_SIZE 从 默认值 改为 8",
+	}
+	var changes []string
+	for _, option := range diff.TableOptions {
+		changes = append(changes, option.Change)
+	}
+	if strings.Join(changes, "\n") != strings.Join(expectedChanges, "\n") {
+		t.Errorf("表选项变更不符合预期:\n%s", strings.Join(changes, "\n"))
+	}
+
+	expected := "ALTER TABLE t ENGINE=InnoDB, ROW_FORMAT=DEFAULT, COMMENT='new', KEY_BLOCK_SIZE=8"
+	if ddls := diff.GenerateDDL("t"); len(ddls) != 1 || ddls[0] != expected {
+		t.Errorf("DDL 不符合预期: %v", ddls)
+	}
+	if risk := diff.Risk(); risk != RiskWarning {
+		t.Errorf("更换存储引擎的风险应为 warning，实际为 %s", risk)
+	}
+
+	options := DefaultOptions()
+	options.CompareAutoIncrement = true
+	diff = compareSQL(t, sourceSQL, targetSQL, options)
+	if ddls := diff.GenerateDDL("t"); len(ddls) != 1 || !strings.Contains(ddls[0], "AUTO_INCREMENT=100") {
+		t.Errorf("启用后应比较 AUTO_INCREMENT: %v", ddls)
+	}
+}
+
+func TestDiffTableOptionsDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target string
+	}{
+		{"未指定引擎即为 InnoDB", "ENGINE=InnoDB", ""},
+		{"大小写不同", "ENGINE=innodb ROW_FORMAT=dynamic", "ENGINE=InnoDB ROW_FORMAT=DYNAMIC"},
+		{"空注释", "COMMENT=''", ""},
+		{"一侧未指定字符集", "DEFAULT CHARSET=latin1", ""},
+		{"只指定字符集时使用默认排序规则", "DEFAULT CHARSET=utf8mb4", "CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci"},
+		{"只指定排序规则时推断字符集", "COLLATE=utf8mb4_bin", "DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"},
+		{"没有已知默认值的选项", "TABLESPACE=ts1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := compareSQL(t, "CREATE TABLE t (id INT) "+tt.source, "CREATE TABLE t (id INT) "+tt.target, DefaultOptions())
+			if diff.HasChanges() {
+				t.Errorf("不应有差异: %v", diff.GenerateDDL("t"))
+			}
+		})
+	}
+}
+
+func TestDiffTableCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		target   string
+		expected []string
+	}{
+		{
+			name:     "没有使用表默认字符集的列时只修改默认值",
+			source:   `CREATE TABLE t (id INT, code VARCHAR(10) CHARACTER SET ascii) DEFAULT CHARSET=latin1`,
+			target:   `CREATE TABLE t (id INT, code VARCHAR(10) CHARACTER SET ascii) DEFAULT CHARSET=utf8mb4`,
+			expected: []string{"ALTER TABLE t DEFAULT CHARSET=utf8mb4"},
+		},
+		{
+			name:   "已有列使用表默认字符集时转换列，并恢复显式指定的列",
+			source: `CREATE TABLE t (name VARCHAR(50), code VARCHAR(10) CHARACTER SET ascii) DEFAULT CHARSET=latin1`,
+			target: `CREATE TABLE t (name VARCHAR(50), code VARCHAR(10) CHARACTER SET ascii) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+			expected: []string{
+				"ALTER TABLE t CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci",
+				"ALTER TABLE t MODIFY COLUMN code VARCHAR(10) CHARACTER SET ascii",
+			},
+		},
+		{
+			name:     "只修改排序规则",
+			source:   `CREATE TABLE t (name TEXT) DEFAULT CHARSET=utf8mb4`,
+			target:   `CREATE TABLE t (name TEXT) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin`,
+			expected: []string{"ALTER TABLE t CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_bin"},
+		},
+		{
+			name:   "列改为显式字符集时不需要转换",
+			source: `CREATE TABLE t (name TEXT) DEFAULT CHARSET=latin1`,
+			target: `CREATE TABLE t (name TEXT CHARACTER SET latin1) DEFAULT CHARSET=utf8mb4`,
+			expected: []string{
+				"ALTER TABLE t DEFAULT CHARSET=utf8mb4",
+				"ALTER TABLE t MODIFY COLUMN name TEXT CHARACTER SET latin1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := compareSQL(t, tt.source, tt.target, DefaultOptions())
+			if ddls := diff.GenerateDDL("t"); strings.Join(ddls, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+			}
+		})
+	}
+}
+
+func TestRollbackTableCharset(t *testing.T) {
+	diff := compareSQL(t,
+		`CREATE TABLE t (name VARCHAR(50)) DEFAULT CHARSET=latin1`,
+		`CREATE TABLE t (name VARCHAR(50)) DEFAULT CHARSET=utf8mb4`,
+		DefaultOptions())
+
+	if risk := diff.Risk(); risk != RiskWarning {
+		t.Errorf("转换字符集的风险应为 warning，实际为 %s", risk)
+	}
+
+	expected := []string{
+		DataLossWarning + "表 t 的字符集改回 latin1 可能丢失无法表示的字符\n" +
+			"ALTER TABLE t CONVERT TO CHARACTER SET latin1",
+	}
+	if ddls := diff.GenerateRollbackDDL("t"); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("回滚 DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestPostgresTableOptions(t *testing.T) {
+	sourceSQL := `
+CREATE TABLE t (id INT) WITH (fillfactor=70, autovacuum_enabled=false);
+COMMENT ON TABLE t IS 'old';
+`
+	targetSQL := `CREATE TABLE t (id INT) WITH (fillfactor=90) TABLESPACE fast;`
+
+	expected := []string{
+		"COMMENT ON TABLE t IS NULL",
+		"ALTER TABLE t SET TABLESPACE fast",
+		"ALTER TABLE t RESET (autovacuum_enabled)",
+		"ALTER TABLE t SET (fillfactor=90)",
+	}
+	if ddls := compareDialect(t, parser.PostgreSQL, sourceSQL, targetSQL).GenerateDDL(); strings.Join(ddls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DDL 不符合预期:\n%s", strings.Join(ddls, "\n"))
+	}
+}
+
+func TestSQLiteTableOptionsRebuild(t *testing.T) {
+	sourceSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, name TEXT);`
+	targetSQL := `CREATE TABLE t (id INTEGER PRIMARY KEY, name TEXT) WITHOUT ROWID;`
+
+	diff := compareDialect(t, parser.SQLite, sourceSQL, targetSQL)
+	ddls := diff.GenerateDDL()
+	if !strings.Contains(strings.Join(ddls, "\n"), ") WITHOUT ROWID") {
+		t.Fatalf("修改 WITHOUT ROWID 应重建表:\n%s", strings.Join(ddls, "\n"))
+	}
+
+	db := openSQLite(t, sourceSQL)
+	execSQLite(t, db, []string{"INSERT INTO t (id, name) VALUES (1, 'a')"})
+	execSQLite(t, db, ddls)
+	if migrated := compareDialect(t, parser.SQLite, dumpSQLite(t, db), targetSQL); migrated.HasChanges() {
+		t.Errorf("迁移后的结构与目标不一致:\n%s", strings.Join(migrated.GenerateDDL(), "\n"))
+	}
+}
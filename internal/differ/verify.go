@@ -0,0 +1,129 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// VerifyError 验证失败：应用生成的 DDL 后与目标结构仍存在差异
+type VerifyError struct {
+	Residual *DatabaseDiff // 剩余的差异
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("验证失败，应用生成的 DDL 后仍存在差异:\n%s剩余的 DDL:\n%s",
+		e.Residual.Summary(), strings.Join(e.Residual.GenerateDDL(), ";\n")+";")
+}
+
+// Verify 验证差异生成的 DDL 能否将源结构变为目标结构：
+// 在源结构的副本上执行 DDL，再与目标结构比对，仍有差异时返回 *VerifyError
+// PostgreSQL 无法把已有列改为 SERIAL，生成的 DDL 使用等价的标识列，验证时两者视为相同
+func (d *DatabaseDiffer) Verify(diff *DatabaseDiff) error {
+	applied, err := applyDDL(d.source, diff.GenerateDDL(), d.options.Dialect, d.target)
+	if err != nil {
+		return err
+	}
+	target := d.target
+	if d.options.Dialect == parser.PostgreSQL {
+		applied, target = serialAsIdentity(applied), serialAsIdentity(target.Clone())
+	}
+	if residual := NewDatabaseDifferWithOptions(applied, target, d.options).Compare(); residual.HasChanges() {
+		return &VerifyError{Residual: residual}
+	}
+	return nil
+}
+
+// ApplyDDL 在结构的副本上按顺序执行 DDL 语句，返回执行后的结构（不修改 db），dialect 为空表示 MySQL
+// 出于安全考虑被注释掉的删除语句（-- DROP ...、-- ALTER TABLE ... DROP ...）按已执行处理，
+// 其余注释只是提示，会被忽略
+func ApplyDDL(db *parser.DatabaseSchema, ddls []string, dialect parser.Dialect) (*parser.DatabaseSchema, error) {
+	return applyDDL(db, ddls, dialect, nil)
+}
+
+// applyDDL 同 ApplyDDL；target 不为空时，语句新增的外键如果引用目标结构中的表，
+// 执行时被引用的表和列必须已经存在（引用目标结构之外的表视为外部依赖，不检查）
+func applyDDL(db *parser.DatabaseSchema, ddls []string, dialect parser.Dialect, target *parser.DatabaseSchema) (*parser.DatabaseSchema, error) {
+	if dialect == "" {
+		dialect = parser.MySQL
+	}
+	applied := db.Clone()
+	p := parser.NewParserWithDialect(dialect).(*parser.DDLParser)
+	for _, ddl := range ddls {
+		existing := foreignKeySet(applied)
+		if statement, ok := disabledStatement(ddl); ok {
+			ddl = statement
+		}
+		if err := p.Apply(applied, ddl); err != nil {
+			return nil, fmt.Errorf("验证失败，无法执行生成的语句:\n%s\n%w", ddl, err)
+		}
+		if target == nil {
+			continue
+		}
+		for _, table := range applied.Tables {
+			for _, fk := range table.ForeignKeys() {
+				if existing[fk] || target.Table(fk.RefTable) == nil {
+					continue
+				}
+				if err := checkForeignKeyReference(applied, fk); err != nil {
+					return nil, fmt.Errorf("验证失败，无法执行生成的语句:\n%s\n%w", ddl, err)
+				}
+			}
+		}
+	}
+	return applied, nil
+}
+
+// foreignKeySet 返回结构中已有的全部外键
+func foreignKeySet(db *parser.DatabaseSchema) map[*parser.Constraint]bool {
+	set := make(map[*parser.Constraint]bool)
+	for _, table := range db.Tables {
+		for _, fk := range table.ForeignKeys() {
+			set[fk] = true
+		}
+	}
+	return set
+}
+
+// checkForeignKeyReference 检查外键引用的表和列是否存在
+func checkForeignKeyReference(db *parser.DatabaseSchema, fk *parser.Constraint) error {
+	ref := db.Table(fk.RefTable)
+	if ref == nil {
+		return fmt.Errorf("外键 %s 引用的表 %s 不存在", fk.Name, fk.RefTable)
+	}
+	for _, name := range fk.RefColumns {
+		if ref.Column(name) == nil {
+			return fmt.Errorf("外键 %s 引用的列 %s.%s 不存在", fk.Name, fk.RefTable, name)
+		}
+	}
+	return nil
+}
+
+// serialAsIdentity 将 SERIAL 列改写为等价的 GENERATED BY DEFAULT AS IDENTITY 整数列
+func serialAsIdentity(db *parser.DatabaseSchema) *parser.DatabaseSchema {
+	for _, table := range db.Tables {
+		for _, col := range table.Columns {
+			if base, ok := postgresSerialTypes[col.Type]; ok {
+				col.Type, col.Identity = base, "BY DEFAULT"
+			}
+		}
+	}
+	return db
+}
+
+// disabledStatement 识别被注释掉的删除语句，返回去掉注释前缀后的语句
+func disabledStatement(ddl string) (string, bool) {
+	lines := strings.Split(ddl, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "-- ") {
+			return "", false
+		}
+		lines[i] = strings.TrimPrefix(line, "-- ")
+	}
+	statement := strings.Join(lines, "\n")
+	if strings.HasPrefix(statement, "DROP ") || (strings.HasPrefix(statement, "ALTER TABLE ") && strings.Contains(statement, " DROP ")) {
+		return statement, true
+	}
+	return "", false
+}
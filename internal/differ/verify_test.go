@@ -0,0 +1,337 @@
+package differ
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+)
+
+// verifyDialects 随机结构测试覆盖的方言
+var verifyDialects = []parser.Dialect{parser.MySQL, parser.PostgreSQL, parser.SQLite}
+
+// randomColumnKind 随机生成列时使用的类型和默认值（均为各方言 SQL 原文）
+type randomColumnKind struct {
+	types    map[parser.Dialect][]string
+	defaults map[parser.Dialect][]string
+}
+
+var randomColumnKinds = []randomColumnKind{
+	{
+		types: map[parser.Dialect][]string{
+			parser.MySQL:      {"INT", "BIGINT UNSIGNED", "TINYINT(1)"},
+			parser.PostgreSQL: {"integer", "bigint", "smallint"},
+			parser.SQLite:     {"INTEGER", "INT"},
+		},
+		defaults: map[parser.Dialect][]string{
+			parser.MySQL:      {"0", "-1", "42", "NULL"},
+			parser.PostgreSQL: {"0", "-1", "42", "NULL"},
+			parser.SQLite:     {"0", "-1", "42", "NULL"},
+		},
+	},
+	{
+		types: map[parser.Dialect][]string{
+			parser.MySQL:      {"VARCHAR(20)", "VARCHAR(255)", "CHAR(10)", "TEXT"},
+			parser.PostgreSQL: {"varchar(20)", "varchar(255)", "text"},
+			parser.SQLite:     {"TEXT", "VARCHAR(20)"},
+		},
+		defaults: map[parser.Dialect][]string{
+			parser.MySQL:      append([]string{`'C:\\temp\\'`, `'a\'b'`}, randomStringDefaults...),
+			parser.PostgreSQL: append([]string{`'C:\temp\'`}, randomStringDefaults...),
+			parser.SQLite:     append([]string{`'C:\temp\'`}, randomStringDefaults...),
+		},
+	},
+	{
+		types: map[parser.Dialect][]string{
+			parser.MySQL:      {"DECIMAL(10,2)", "DOUBLE"},
+			parser.PostgreSQL: {"numeric(10,2)", "double precision"},
+			parser.SQLite:     {"REAL", "NUMERIC"},
+		},
+		defaults: map[parser.Dialect][]string{
+			parser.MySQL:      {"0.00", "1.5", "1e3"},
+			parser.PostgreSQL: {"0.00", "1.5", "1e3"},
+			parser.SQLite:     {"0.00", "1.5", "1e3"},
+		},
+	},
+	{
+		types: map[parser.Dialect][]string{
+			parser.MySQL:      {"DATETIME", "TIMESTAMP", "DATE"},
+			parser.PostgreSQL: {"timestamp", "date"},
+			parser.SQLite:     {"DATETIME", "DATE"},
+		},
+		defaults: map[parser.Dialect][]string{
+			parser.MySQL:      {"CURRENT_TIMESTAMP", "'2024-01-01 00:00:00'"},
+			parser.PostgreSQL: {"CURRENT_TIMESTAMP", "now()", "'2024-01-01 00:00:00'"},
+			parser.SQLite:     {"CURRENT_TIMESTAMP", "'2024-01-01 00:00:00'"},
+		},
+	},
+	{
+		types: map[parser.Dialect][]string{
+			parser.MySQL:      {"ENUM('a','b')", "ENUM('a','b','it''s')", `SET('x','y\\z')`},
+			parser.PostgreSQL: {"boolean"},
+			parser.SQLite:     {"BOOLEAN"},
+		},
+		defaults: map[parser.Dialect][]string{
+			parser.MySQL:      {"'a'"},
+			parser.PostgreSQL: {"true", "false"},
+			parser.SQLite:     {"1", "0"},
+		},
+	},
+}
+
+// randomStringDefaults 各方言通用的字符串默认值，包括形似数字、关键字和函数调用的字符串
+var randomStringDefaults = []string{
+	"'it''s'", "'1.2.3'", "'2024-01-01'", "'abc(def)'", "'ABC(1)'", "'null'", "'NULL'", "'CURRENT_TIMESTAMP'",
+	"'12abc'", "'007'", "'1e3'", "'-1'", "'-'", "''",
+}
+
+// randomComments 随机列注释（含需要转义的字符）
+var randomComments = map[parser.Dialect][]string{
+	parser.MySQL:      {"'用户名'", "'it''s'", `'C:\\path'`, `'tab\there'`, "'50%'"},
+	parser.PostgreSQL: {"'用户名'", "'it''s'", `'C:\path'`, "'50%'"},
+}
+
+// randomSchema 随机生成建表脚本：表名、列名取自固定的小集合，使两次生成的结构之间有交集
+func randomSchema(rng *rand.Rand, dialect parser.Dialect) string {
+	var script strings.Builder
+	pick := func(values []string) string { return values[rng.Intn(len(values))] }
+
+	for i := 0; i < 3; i++ {
+		table := fmt.Sprintf("t%d", i)
+		if i > 0 && rng.Intn(3) == 0 {
+			continue
+		}
+
+		var defs, extras, columns, intColumns []string
+		switch dialect {
+		case parser.MySQL:
+			defs = append(defs, "id INT NOT NULL"+pick([]string{"", " AUTO_INCREMENT"})+" PRIMARY KEY")
+		case parser.PostgreSQL:
+			defs = append(defs, "id "+pick([]string{"integer NOT NULL", "serial", "bigint GENERATED BY DEFAULT AS IDENTITY"})+" PRIMARY KEY")
+		case parser.SQLite:
+			defs = append(defs, "id INTEGER PRIMARY KEY")
+		}
+
+		for _, c := range rng.Perm(6)[:1+rng.Intn(5)] {
+			name := fmt.Sprintf("c%d", c)
+			kind := randomColumnKinds[rng.Intn(len(randomColumnKinds))]
+			def := name + " " + pick(kind.types[dialect])
+			if rng.Intn(2) == 0 {
+				def += " NOT NULL"
+			}
+			if rng.Intn(2) == 0 {
+				def += " DEFAULT " + pick(kind.defaults[dialect])
+			}
+			if comments := randomComments[dialect]; len(comments) > 0 && rng.Intn(3) == 0 {
+				comment := pick(comments)
+				if dialect == parser.MySQL {
+					def += " COMMENT " + comment
+				} else {
+					extras = append(extras, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", table, name, comment))
+				}
+			}
+			defs = append(defs, def)
+			columns = append(columns, name)
+			if kind.defaults[dialect][0] == "0" {
+				intColumns = append(intColumns, name)
+			}
+		}
+
+		// 索引名与列名无关，同名索引在两次生成的结构中可能建在不同的列上，也可能包含多列
+		for _, n := range rng.Perm(3)[:rng.Intn(3)] {
+			indexed := make([]string, 0, 3)
+			for _, c := range rng.Perm(len(columns))[:1+rng.Intn(min(3, len(columns)))] {
+				indexed = append(indexed, columns[c])
+			}
+			unique := pick([]string{"", "UNIQUE "})
+			extras = append(extras, fmt.Sprintf("CREATE %sINDEX idx_%s_%d ON %s (%s)", unique, table, n, table, strings.Join(indexed, ", ")))
+		}
+
+		if i > 0 && rng.Intn(2) == 0 {
+			defs = append(defs, "ref_id INT", fmt.Sprintf("CONSTRAINT fk_%s_ref FOREIGN KEY (ref_id) REFERENCES t0 (id)%s",
+				table, pick([]string{"", " ON DELETE CASCADE", " ON DELETE SET NULL"})))
+		}
+		if len(intColumns) > 0 && rng.Intn(2) == 0 {
+			defs = append(defs, fmt.Sprintf("CONSTRAINT chk_%s CHECK (%s > %d)", table, pick(intColumns), rng.Intn(2)-1))
+		}
+
+		script.WriteString(fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table, strings.Join(defs, ",\n  ")))
+		if dialect == parser.MySQL && rng.Intn(2) == 0 {
+			script.WriteString(" " + pick([]string{"ENGINE=InnoDB", "COMMENT='it''s'", `COMMENT='a\\b'`, "DEFAULT CHARSET=utf8mb4"}))
+		}
+		script.WriteString(";\n")
+		for _, extra := range extras {
+			script.WriteString(extra + ";\n")
+		}
+	}
+	return script.String()
+}
+
+// verifyRandomPair 随机生成一对结构，检查生成的 DDL 能将源结构变为目标结构
+func verifyRandomPair(t *testing.T, seed int64, dialect parser.Dialect) {
+	rng := rand.New(rand.NewSource(seed))
+	sourceSQL, targetSQL := randomSchema(rng, dialect), randomSchema(rng, dialect)
+
+	p := parser.NewParserWithDialect(dialect)
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v\n%s", err, sourceSQL)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v\n%s", err, targetSQL)
+	}
+
+	options := DefaultOptions()
+	options.Dialect = dialect
+	options.DetectRenames = rng.Intn(2) == 0
+	d := NewDatabaseDifferWithOptions(source, target, options)
+	diff := d.Compare()
+	if err := d.Verify(diff); err != nil {
+		t.Fatalf("种子 %d（%s）验证失败: %v\n源结构:\n%s\n目标结构:\n%s\n生成的 DDL:\n%s",
+			seed, dialect, err, sourceSQL, targetSQL, strings.Join(diff.GenerateDDL(), ";\n"))
+	}
+}
+
+func FuzzVerify(f *testing.F) {
+	for seed := int64(0); seed < 300; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		for _, dialect := range verifyDialects {
+			verifyRandomPair(t, seed, dialect)
+		}
+	})
+}
+
+func TestVerify(t *testing.T) {
+	source, err := parser.NewParser().ParseScript("CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(20));")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	target, err := parser.NewParser().ParseScript(`CREATE TABLE t (
+		id INT PRIMARY KEY,
+		name VARCHAR(20) DEFAULT 'NOW is the time' COMMENT 'it''s',
+		path VARCHAR(50) NOT NULL DEFAULT 'C:\\temp\\',
+		version VARCHAR(10) DEFAULT '1.2.3',
+		day DATE DEFAULT '2024-01-01'
+	) COMMENT='a\\b';`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	d := NewDatabaseDiffer(source, target)
+	diff := d.Compare()
+	if err := d.Verify(diff); err != nil {
+		t.Fatalf("验证失败: %v", err)
+	}
+	ddl := strings.Join(diff.GenerateDDL(), "\n")
+	for _, expected := range []string{
+		"DEFAULT 'NOW is the time' COMMENT 'it''s'",
+		`DEFAULT 'C:\\temp\\'`,
+		"DEFAULT '1.2.3'",
+		"DEFAULT '2024-01-01'",
+		`COMMENT='a\\b'`,
+	} {
+		if !strings.Contains(ddl, expected) {
+			t.Errorf("DDL 中缺少 %s:\n%s", expected, ddl)
+		}
+	}
+	if source.Table("t").Column("path") != nil {
+		t.Errorf("验证不应修改源结构")
+	}
+
+	// 生成的 DDL 不完整时报告剩余差异
+	diff.ModifiedTables[0].Diff.AddedColumns = diff.ModifiedTables[0].Diff.AddedColumns[1:]
+	var verifyErr *VerifyError
+	if err := d.Verify(diff); !errors.As(err, &verifyErr) || !strings.Contains(err.Error(), "ADD COLUMN path") {
+		t.Errorf("期望报告剩余差异，实际: %v", err)
+	}
+}
+
+func TestVerifyStringDefaults(t *testing.T) {
+	// 形似数字、关键字或函数调用的字符串默认值必须保留引号
+	defaults := []string{"'007'", "'1e3'", "'NULL'", "'null'", "'CURRENT_TIMESTAMP'", "'ABC(1)'", "''"}
+	for _, dialect := range verifyDialects {
+		p := parser.NewParserWithDialect(dialect)
+		source, err := p.ParseScript("CREATE TABLE t (id INT PRIMARY KEY);")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		columns := []string{"id INT PRIMARY KEY"}
+		for i, value := range defaults {
+			columns = append(columns, fmt.Sprintf("c%d VARCHAR(20) DEFAULT %s", i, value))
+		}
+		target, err := p.ParseScript("CREATE TABLE t (" + strings.Join(columns, ", ") + ");")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+
+		options := DefaultOptions()
+		options.Dialect = dialect
+		d := NewDatabaseDifferWithOptions(source, target, options)
+		diff := d.Compare()
+		if err := d.Verify(diff); err != nil {
+			t.Errorf("%s 验证失败: %v", dialect, err)
+		}
+		ddl := strings.Join(diff.GenerateDDL(), "\n")
+		for _, value := range defaults {
+			if !strings.Contains(ddl, "DEFAULT "+value) {
+				t.Errorf("%s DDL 中缺少 DEFAULT %s:\n%s", dialect, value, ddl)
+			}
+		}
+	}
+
+	// DEFAULT NULL 与 DEFAULT 'NULL' 不同，数字加不加引号相同，可为空的列上 DEFAULT NULL 与没有默认值相同
+	for _, tt := range []struct {
+		source, target string
+		changed        bool
+	}{
+		{"DEFAULT NULL", "DEFAULT 'NULL'", true},
+		{"", "DEFAULT NULL", false},
+		{"NOT NULL", "NOT NULL DEFAULT 'x'", true},
+		{"DEFAULT CURRENT_TIMESTAMP", "DEFAULT 'CURRENT_TIMESTAMP'", true},
+		{"DEFAULT 0", "DEFAULT '0'", false},
+		{"", "DEFAULT ''", true},
+	} {
+		source, err := parser.NewParser().Parse("CREATE TABLE t (c VARCHAR(20) " + tt.source + ")")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		target, err := parser.NewParser().Parse("CREATE TABLE t (c VARCHAR(20) " + tt.target + ")")
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if changed := NewDiffer(source, target).Compare().HasChanges(); changed != tt.changed {
+			t.Errorf("%s → %s: 期望有变更 %v，实际 %v", tt.source, tt.target, tt.changed, changed)
+		}
+	}
+}
+
+func TestApplyDDL(t *testing.T) {
+	source, err := parser.NewParser().ParseScript("CREATE TABLE t (id INT PRIMARY KEY, a INT, b INT, KEY idx_a (a));")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	applied, err := ApplyDDL(source, []string{
+		"-- 警告: 提示信息\nALTER TABLE t MODIFY COLUMN b BIGINT",
+		"-- ALTER TABLE t DROP INDEX idx_a",
+		"-- ALTER TABLE t DROP COLUMN a",
+		"-- 无法自动处理，需要手动修改",
+	}, parser.MySQL)
+	if err != nil {
+		t.Fatalf("执行失败: %v", err)
+	}
+	table := applied.Table("t")
+	if len(table.Columns) != 2 || table.Column("b").Type != "BIGINT" || len(table.Indexes) != 0 {
+		t.Errorf("执行结果错误: %+v %v", table.Columns, table.Indexes)
+	}
+
+	if _, err := ApplyDDL(source, []string{"ALTER TABLE t DROP COLUMN missing"}, parser.MySQL); err == nil || !strings.Contains(err.Error(), "DROP COLUMN missing") {
+		t.Errorf("执行失败时应指出出错的语句: %v", err)
+	}
+}
@@ -0,0 +1,1111 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// peekIfNotExists 判断语句开头 limit 个词法单元之内是否出现 IF NOT EXISTS
+func (g *grammar) peekIfNotExists(limit int) bool {
+	for i := 1; i <= limit; i++ {
+		if isKeyword(g.peekAt(i), "IF") && isKeyword(g.peekAt(i+1), "NOT") && isKeyword(g.peekAt(i+2), "EXISTS") {
+			return true
+		}
+	}
+	return false
+}
+
+// skipAlterAction 跳过当前的 ALTER TABLE 子句，停在同一层级的逗号或语句结尾
+func (g *grammar) skipAlterAction() {
+	depth := 0
+	for !g.atEOF() {
+		switch {
+		case g.peekSymbol("("):
+			depth++
+		case g.peekSymbol(")"):
+			depth--
+		case depth <= 0 && (g.peekSymbol(",") || g.peekSymbol(";")):
+			return
+		}
+		g.next()
+	}
+}
+
+// parseAlterTable 解析 ALTER TABLE 语句并应用到对应的表
+//
+//	ALTER TABLE [IF EXISTS] [ONLY] tbl_name alter_action [, alter_action] ...
+//
+// 不影响表结构的子句（如 ALGORITHM、LOCK、DISABLE KEYS、OWNER TO）会被跳过，其他无法识别的子句报告语法错误
+func (g *grammar) parseAlterTable(db *DatabaseSchema) error {
+	start := g.peek()
+	g.acceptSeq("ALTER", "TABLE")
+	ifExists := g.acceptSeq("IF", "EXISTS")
+	g.acceptKeyword("ONLY")
+	name, err := g.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	table := db.Table(name)
+	if table == nil {
+		if ifExists {
+			g.skipStatement()
+			return nil
+		}
+		return g.semanticErrorf(start, "表 %s 未定义", name)
+	}
+
+	for !g.atEOF() && !g.peekSymbol(";") {
+		if err := g.parseAlterAction(db, table); err != nil {
+			return err
+		}
+		if !g.acceptSymbol(",") {
+			break
+		}
+	}
+
+	for i, col := range table.Columns {
+		col.Position = i + 1
+	}
+	ensureIndexNames(table, g.dialect)
+	ensureForeignKeyNames(table, g.dialect)
+	ensureCheckNames(table, g.dialect)
+	normalizeColumnCharsets(table)
+	return nil
+}
+
+// parseAlterAction 解析并应用一个 ALTER TABLE 子句
+func (g *grammar) parseAlterAction(db *DatabaseSchema, table *TableSchema) error {
+	start, pos := g.peek(), g.pos
+	switch {
+	case g.peekSeq("PARTITION", "BY"), g.peekSeq("REMOVE", "PARTITIONING"),
+		g.peekSeq("ADD", "PARTITION"), g.peekSeq("DROP", "PARTITION"),
+		g.peekSeq("COALESCE", "PARTITION"), g.peekSeq("REORGANIZE", "PARTITION"):
+		return g.parseAlterPartitions(table)
+	case g.acceptKeyword("ADD"):
+		return g.parseAddDefinition(table)
+	case g.acceptKeyword("DROP"):
+		return g.parseDropDefinition(table)
+	case g.acceptKeyword("MODIFY"):
+		g.acceptKeyword("COLUMN")
+		return g.parseReplaceColumn(db, table, "")
+	case g.acceptKeyword("CHANGE"):
+		g.acceptKeyword("COLUMN")
+		oldName, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		return g.parseReplaceColumn(db, table, oldName)
+	case g.acceptKeyword("ALTER"):
+		return g.parseAlterDefinition(table)
+	case g.acceptKeyword("RENAME"):
+		return g.parseRenameDefinition(db, table)
+	case g.peekSeq("CONVERT", "TO"):
+		return g.parseConvertCharset(table)
+	case g.dialect == MySQL && g.peekTableOption():
+		return g.parseAlterTableOptions(table)
+	case g.acceptKeyword("ALGORITHM", "LOCK"):
+		g.acceptSymbol("=")
+		g.next()
+	case g.peekIgnoredAlterAction():
+		g.skipAlterAction()
+	}
+	if g.pos == pos {
+		return g.errorf(start, "无法识别的 ALTER TABLE 子句")
+	}
+	return nil
+}
+
+// ignoredAlterActions 不影响表结构的 ALTER TABLE 子句（按开头的关键字识别）
+var ignoredAlterActions = [][]string{
+	// MySQL
+	{"FORCE"}, {"ENABLE"}, {"DISABLE"}, {"DISCARD"}, {"IMPORT"}, {"ORDER", "BY"},
+	{"WITH", "VALIDATION"}, {"WITHOUT", "VALIDATION"}, {"UPGRADE", "PARTITIONING"},
+	{"ANALYZE", "PARTITION"}, {"CHECK", "PARTITION"}, {"OPTIMIZE", "PARTITION"}, {"REBUILD", "PARTITION"},
+	{"REPAIR", "PARTITION"}, {"TRUNCATE", "PARTITION"}, {"EXCHANGE", "PARTITION"},
+	// PostgreSQL
+	{"OWNER", "TO"}, {"NO", "FORCE"}, {"VALIDATE", "CONSTRAINT"}, {"SET"}, {"RESET"}, {"CLUSTER", "ON"},
+	{"INHERIT"}, {"NO", "INHERIT"}, {"OF"}, {"NOT", "OF"}, {"REPLICA", "IDENTITY"},
+	{"ATTACH", "PARTITION"}, {"DETACH", "PARTITION"},
+}
+
+// peekIgnoredAlterAction 判断当前子句是否为不影响表结构的子句
+func (g *grammar) peekIgnoredAlterAction() bool {
+	for _, keywords := range ignoredAlterActions {
+		if g.peekSeq(keywords...) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseColumnClause 解析 ALTER TABLE 中的单个列定义，返回只包含该列（以及列级约束）的临时表结构
+func (g *grammar) parseColumnClause(table *TableSchema) (*TableSchema, error) {
+	tmp := newTableSchema(table.Name)
+	if err := g.parseColumnDefinition(tmp); err != nil {
+		return nil, err
+	}
+	return tmp, nil
+}
+
+// mergeColumnClause 将列定义中的主键、索引和约束合并到表中
+func (g *grammar) mergeColumnClause(start Token, table, clause *TableSchema) error {
+	if len(clause.PrimaryKeys) > 0 {
+		if len(table.PrimaryKeys) > 0 && !(len(table.PrimaryKeys) == 1 && strings.EqualFold(table.PrimaryKeys[0], clause.PrimaryKeys[0])) {
+			return g.semanticErrorf(start, "表 %s 已定义主键", table.Name)
+		}
+		table.PrimaryKeys, table.PrimaryKeyName = clause.PrimaryKeys, clause.PrimaryKeyName
+	}
+	table.Indexes = append(table.Indexes, clause.Indexes...)
+	table.Constraints = append(table.Constraints, clause.Constraints...)
+	return nil
+}
+
+// parseColumnPlacement 解析列定义之后的 FIRST / AFTER col 子句并移动列
+func (g *grammar) parseColumnPlacement(table *TableSchema, col *Column) error {
+	switch {
+	case g.acceptKeyword("FIRST"):
+		moveColumn(table, col, 0)
+	case g.acceptKeyword("AFTER"):
+		tok := g.peek()
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		after := table.Column(name)
+		if after == nil || after == col {
+			return g.semanticErrorf(tok, "列 %s.%s 不存在", table.Name, name)
+		}
+		moveColumn(table, col, columnIndex(table, after)+1)
+	}
+	return nil
+}
+
+// parseAddDefinition 解析 ADD 子句：新增列、主键、索引或约束
+//
+//	ADD [COLUMN] [IF NOT EXISTS] col_name column_definition [FIRST | AFTER col_name]
+//	ADD [COLUMN] (col_name column_definition, ...)
+//	ADD {INDEX | KEY | UNIQUE | FULLTEXT | SPATIAL | PRIMARY KEY | FOREIGN KEY | CHECK} ...
+//	ADD CONSTRAINT [symbol] {PRIMARY KEY | UNIQUE | FOREIGN KEY | CHECK} ...
+func (g *grammar) parseAddDefinition(table *TableSchema) error {
+	column := g.acceptKeyword("COLUMN")
+	ifNotExists := g.acceptSeq("IF", "NOT", "EXISTS")
+
+	if !column && (g.peekKeyword("CONSTRAINT", "PRIMARY", "FOREIGN", "CHECK") || g.peekIndexDefinition()) {
+		return g.parseAddConstraint(table)
+	}
+	if g.acceptSymbol("(") {
+		for {
+			if err := g.parseAddColumn(table, false); err != nil {
+				return err
+			}
+			if !g.acceptSymbol(",") {
+				break
+			}
+		}
+		return g.expectSymbol(")")
+	}
+	return g.parseAddColumn(table, ifNotExists)
+}
+
+// parseAddColumn 解析并新增一列（带 IF NOT EXISTS 且列已存在时忽略）
+func (g *grammar) parseAddColumn(table *TableSchema, ifNotExists bool) error {
+	start := g.peek()
+	clause, err := g.parseColumnClause(table)
+	if err != nil {
+		return err
+	}
+	col := clause.Columns[0]
+	if table.Column(col.Name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return g.semanticErrorf(start, "列 %s.%s 已存在", table.Name, col.Name)
+	}
+	if err := g.mergeColumnClause(start, table, clause); err != nil {
+		return err
+	}
+	table.Columns = append(table.Columns, col)
+	return g.parseColumnPlacement(table, col)
+}
+
+// parseAddConstraint 解析新增的主键、索引或约束，名称与已有的索引、约束重复时返回错误
+func (g *grammar) parseAddConstraint(table *TableSchema) error {
+	start := g.peek()
+	if isKeyword(start, "CONSTRAINT") && isKeyword(g.peekAt(2), "EXCLUDE") {
+		// PostgreSQL 的 EXCLUDE 约束不影响比对
+		g.skipAlterAction()
+		return nil
+	}
+
+	primaryKeys, indexes, constraints := len(table.PrimaryKeys), len(table.Indexes), len(table.Constraints)
+	if err := g.parseCreateDefinition(table); err != nil {
+		return err
+	}
+	g.acceptSeq("NOT", "VALID")
+
+	if primaryKeys > 0 && len(table.PrimaryKeys) > primaryKeys {
+		return g.semanticErrorf(start, "表 %s 已定义主键", table.Name)
+	}
+	for _, idx := range table.Indexes[indexes:] {
+		if idx.Name != "" && table.Index(idx.Name) != idx {
+			return g.semanticErrorf(start, "索引 %s 已存在", idx.Name)
+		}
+	}
+	for _, c := range table.Constraints[constraints:] {
+		for _, existing := range table.Constraints[:constraints] {
+			if c.Name != "" && strings.EqualFold(existing.Name, c.Name) {
+				return g.semanticErrorf(start, "约束 %s 已存在", c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// parseReplaceColumn 解析 MODIFY / CHANGE 子句，以新的列定义替换原有的列
+// oldName 为空时（MODIFY）按新定义中的列名查找原有的列
+//
+//	MODIFY [COLUMN] col_name column_definition [FIRST | AFTER col_name]
+//	CHANGE [COLUMN] old_col_name new_col_name column_definition [FIRST | AFTER col_name]
+func (g *grammar) parseReplaceColumn(db *DatabaseSchema, table *TableSchema, oldName string) error {
+	start := g.peek()
+	clause, err := g.parseColumnClause(table)
+	if err != nil {
+		return err
+	}
+	col := clause.Columns[0]
+	if oldName == "" {
+		oldName = col.Name
+	}
+	old := table.Column(oldName)
+	if old == nil {
+		return g.semanticErrorf(start, "列 %s.%s 不存在", table.Name, oldName)
+	}
+	if existing := table.Column(col.Name); existing != nil && existing != old {
+		return g.semanticErrorf(start, "列 %s.%s 已存在", table.Name, col.Name)
+	}
+	if err := g.mergeColumnClause(start, table, clause); err != nil {
+		return err
+	}
+
+	table.Columns[columnIndex(table, old)] = col
+	if old.Name != col.Name {
+		renameColumnReferences(db, table, old.Name, col.Name)
+	}
+	return g.parseColumnPlacement(table, col)
+}
+
+// parseDropDefinition 解析 DROP 子句：删除列、主键、索引或约束
+//
+//	DROP [COLUMN] [IF EXISTS] col_name [CASCADE | RESTRICT]
+//	DROP PRIMARY KEY | DROP {INDEX | KEY} index_name
+//	DROP FOREIGN KEY fk_symbol | DROP CHECK symbol | DROP CONSTRAINT [IF EXISTS] symbol [CASCADE | RESTRICT]
+func (g *grammar) parseDropDefinition(table *TableSchema) error {
+	start := g.peek()
+	switch {
+	case g.acceptSeq("PRIMARY", "KEY"):
+		if len(table.PrimaryKeys) == 0 {
+			return g.semanticErrorf(start, "表 %s 没有主键", table.Name)
+		}
+		table.PrimaryKeys, table.PrimaryKeyName = nil, ""
+		return nil
+	case g.acceptKeyword("INDEX", "KEY"):
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		if !removeIndex(table, name) {
+			return g.semanticErrorf(start, "索引 %s.%s 不存在", table.Name, name)
+		}
+		return nil
+	case g.acceptSeq("FOREIGN", "KEY"), g.acceptKeyword("CHECK", "CONSTRAINT"):
+		ifExists := g.acceptSeq("IF", "EXISTS")
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		g.acceptKeyword("CASCADE", "RESTRICT")
+		if !dropConstraint(table, name, isKeyword(start, "CONSTRAINT")) && !ifExists {
+			return g.semanticErrorf(start, "约束 %s.%s 不存在", table.Name, name)
+		}
+		return nil
+	}
+
+	g.acceptKeyword("COLUMN")
+	ifExists := g.acceptSeq("IF", "EXISTS")
+	name, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+	g.acceptKeyword("CASCADE", "RESTRICT")
+	col := table.Column(name)
+	if col == nil {
+		if ifExists {
+			return nil
+		}
+		return g.semanticErrorf(start, "列 %s.%s 不存在", table.Name, name)
+	}
+	dropColumn(table, col, g.dialect)
+	return nil
+}
+
+// parseAlterDefinition 解析 ALTER 子句：修改列的默认值、可空性、类型等属性，或修改索引、约束
+//
+//	ALTER [COLUMN] col_name {SET DEFAULT expr | DROP DEFAULT | SET {VISIBLE | INVISIBLE}}
+//	ALTER [COLUMN] col_name {SET | DROP} NOT NULL                               -- PostgreSQL
+//	ALTER [COLUMN] col_name [SET DATA] TYPE data_type [COLLATE collation] [USING expr]
+//	ALTER [COLUMN] col_name {SET EXPRESSION AS (expr) | DROP EXPRESSION [IF EXISTS]}
+//	ALTER [COLUMN] col_name {ADD GENERATED {ALWAYS | BY DEFAULT} AS IDENTITY | SET GENERATED ... | DROP IDENTITY [IF EXISTS]}
+//	ALTER INDEX index_name {VISIBLE | INVISIBLE}
+//	ALTER {CHECK | CONSTRAINT} symbol [NOT] ENFORCED
+func (g *grammar) parseAlterDefinition(table *TableSchema) error {
+	start := g.peek()
+	switch {
+	case g.acceptKeyword("INDEX"):
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		idx := table.Index(name)
+		if idx == nil {
+			return g.semanticErrorf(start, "索引 %s.%s 不存在", table.Name, name)
+		}
+		switch {
+		case g.acceptKeyword("VISIBLE"):
+			idx.Invisible = false
+		case g.acceptKeyword("INVISIBLE"):
+			idx.Invisible = true
+		default:
+			return g.errorf(g.peek(), "期望 VISIBLE 或 INVISIBLE")
+		}
+		return nil
+	case g.peekKeyword("CHECK", "CONSTRAINT") && g.dialect == MySQL:
+		g.next()
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		var check *Constraint
+		for _, c := range table.Checks() {
+			if strings.EqualFold(c.Name, name) {
+				check = c
+			}
+		}
+		if check == nil {
+			return g.semanticErrorf(start, "约束 %s.%s 不存在", table.Name, name)
+		}
+		check.NotEnforced = g.acceptKeyword("NOT")
+		return g.expectKeyword("ENFORCED")
+	}
+
+	g.acceptKeyword("COLUMN")
+	name, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+	col := table.Column(name)
+	if col == nil {
+		return g.semanticErrorf(start, "列 %s.%s 不存在", table.Name, name)
+	}
+
+	switch {
+	case g.acceptSeq("SET", "DEFAULT"):
+		if col.DefaultValue, col.DefaultQuoted, err = g.parseDefaultValue(); err != nil {
+			return err
+		}
+	case g.acceptSeq("DROP", "DEFAULT"):
+		col.DefaultValue, col.DefaultQuoted = "", false
+		// SERIAL 的自增来自序列默认值，去掉默认值后成为普通整数列
+		if base, ok := serialTypes[col.Type]; ok && g.dialect == PostgreSQL {
+			col.Type, col.AutoInc = base, false
+		}
+	case g.acceptSeq("SET", "NOT", "NULL"):
+		col.NotNull = true
+	case g.acceptSeq("DROP", "NOT", "NULL"):
+		col.NotNull = false
+	case g.acceptSeq("SET", "VISIBLE"):
+		col.Invisible = false
+	case g.acceptSeq("SET", "INVISIBLE"):
+		col.Invisible = true
+	case g.acceptSeq("SET", "DATA", "TYPE"), g.acceptKeyword("TYPE"):
+		changed := &Column{Name: col.Name}
+		if err := g.parseDataType(changed); err != nil {
+			return err
+		}
+		col.Type, col.Length, col.Values = changed.Type, changed.Length, changed.Values
+		col.Collation = ""
+		if g.acceptKeyword("COLLATE") {
+			if col.Collation, err = g.parseCharsetName(); err != nil {
+				return err
+			}
+		}
+		if g.acceptKeyword("USING") {
+			g.skipAlterAction()
+		}
+	case g.acceptSeq("SET", "EXPRESSION"):
+		if err := g.expectKeyword("AS"); err != nil {
+			return err
+		}
+		if col.Generated, err = g.parseParenthesized(); err != nil {
+			return err
+		}
+	case g.acceptSeq("DROP", "EXPRESSION"):
+		g.acceptSeq("IF", "EXISTS")
+		col.Generated, col.Stored = "", false
+	case g.acceptKeyword("ADD"):
+		if err := g.parseGeneratedColumn(col); err != nil {
+			return err
+		}
+	case g.acceptSeq("SET", "GENERATED"):
+		switch {
+		case g.acceptKeyword("ALWAYS"):
+			col.Identity = "ALWAYS"
+		case g.acceptSeq("BY", "DEFAULT"):
+			col.Identity = "BY DEFAULT"
+		default:
+			return g.errorf(g.peek(), "期望 ALWAYS 或 BY DEFAULT")
+		}
+	case g.acceptSeq("DROP", "IDENTITY"):
+		g.acceptSeq("IF", "EXISTS")
+		col.Identity, col.AutoInc = "", false
+	case g.peekSeq("SET", "STATISTICS"), g.peekSeq("SET", "STORAGE"), g.peekSeq("SET", "COMPRESSION"),
+		g.peekKeyword("SET", "RESET") && isSymbol(g.peekAt(1), "("):
+		// 不影响表结构
+		g.skipAlterAction()
+	default:
+		return g.errorf(g.peek(), "无法识别的 ALTER TABLE 子句")
+	}
+	return nil
+}
+
+// parseRenameDefinition 解析 RENAME 子句：重命名列、索引、约束或表
+//
+//	RENAME COLUMN old_col_name TO new_col_name
+//	RENAME {INDEX | KEY} old_index_name TO new_index_name
+//	RENAME CONSTRAINT old_name TO new_name                                       -- PostgreSQL
+//	RENAME [TO | AS] new_tbl_name
+//	RENAME old_col_name TO new_col_name                                          -- PostgreSQL、SQLite
+func (g *grammar) parseRenameDefinition(db *DatabaseSchema, table *TableSchema) error {
+	start := g.peek()
+	kind := ""
+	switch {
+	case g.acceptKeyword("COLUMN"):
+		kind = "COLUMN"
+	case g.acceptKeyword("INDEX", "KEY"):
+		kind = "INDEX"
+	case g.acceptKeyword("CONSTRAINT"):
+		kind = "CONSTRAINT"
+	case g.acceptKeyword("TO", "AS"), g.dialect == MySQL:
+		name, err := g.parseQualifiedName()
+		if err != nil {
+			return err
+		}
+		if err := renameTable(db, table, name); err != nil {
+			return g.semanticErrorf(start, "%v", err)
+		}
+		return nil
+	default:
+		kind = "COLUMN"
+	}
+
+	oldName, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+	if err := g.expectKeyword("TO"); err != nil {
+		return err
+	}
+	newName, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "COLUMN":
+		col := table.Column(oldName)
+		if col == nil {
+			return g.semanticErrorf(start, "列 %s.%s 不存在", table.Name, oldName)
+		}
+		if existing := table.Column(newName); existing != nil && existing != col {
+			return g.semanticErrorf(start, "列 %s.%s 已存在", table.Name, newName)
+		}
+		renameColumnReferences(db, table, col.Name, newName)
+		col.Name = newName
+	case "INDEX":
+		idx := table.Index(oldName)
+		if idx == nil {
+			return g.semanticErrorf(start, "索引 %s.%s 不存在", table.Name, oldName)
+		}
+		if existing := table.Index(newName); existing != nil && existing != idx {
+			return g.semanticErrorf(start, "索引 %s 已存在", newName)
+		}
+		idx.Name = newName
+	default:
+		renamed := false
+		for _, c := range table.Constraints {
+			if strings.EqualFold(c.Name, oldName) {
+				c.Name, renamed = newName, true
+			}
+		}
+		// 唯一约束在 PostgreSQL 中同时是索引
+		if idx := table.Index(oldName); idx != nil && idx.Type == "UNIQUE" {
+			idx.Name, renamed = newName, true
+		}
+		if len(table.PrimaryKeys) > 0 && strings.EqualFold(table.PrimaryKeyConstraintName(), oldName) {
+			table.PrimaryKeyName, renamed = newName, true
+		}
+		if !renamed {
+			return g.semanticErrorf(start, "约束 %s.%s 不存在", table.Name, oldName)
+		}
+	}
+	return nil
+}
+
+// parseConvertCharset 解析 CONVERT TO CHARACTER SET charset [COLLATE collation]
+// 修改表的默认字符集，并将所有字符列转换为该字符集
+func (g *grammar) parseConvertCharset(table *TableSchema) error {
+	g.acceptSeq("CONVERT", "TO")
+	if !g.acceptSeq("CHARACTER", "SET") && !g.acceptKeyword("CHARSET") {
+		return g.errorf(g.peek(), "期望 CHARACTER SET")
+	}
+	charset, err := g.parseCharsetName()
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(charset, "DEFAULT") {
+		// 转换为数据库的默认字符集，默认字符集未知，保持不变
+		if g.acceptKeyword("COLLATE") {
+			_, err = g.parseCharsetName()
+		}
+		return err
+	}
+	table.Options["CHARSET"] = strings.ToLower(charset)
+	delete(table.Options, "COLLATE")
+	if g.acceptKeyword("COLLATE") {
+		collation, err := g.parseCharsetName()
+		if err != nil {
+			return err
+		}
+		table.Options["COLLATE"] = strings.ToLower(collation)
+	}
+	for _, col := range table.Columns {
+		if col.UsesCharset() {
+			col.Charset, col.Collation = "", ""
+		}
+	}
+	return nil
+}
+
+// parseAlterTableOptions 解析 ALTER TABLE 中的表选项（以空格分隔的多个选项属于同一子句）
+// 只修改默认字符集、排序规则时已有的列保持原来的设置，因此先将其显式记录在列上
+func (g *grammar) parseAlterTableOptions(table *TableSchema) error {
+	charset, collation := table.Options["CHARSET"], table.Options["COLLATE"]
+	for g.peekTableOption() {
+		if err := g.parseTableOption(table); err != nil {
+			return err
+		}
+	}
+	if table.Options["CHARSET"] == charset && table.Options["COLLATE"] == collation {
+		return nil
+	}
+	for _, col := range table.Columns {
+		if !col.UsesCharset() {
+			continue
+		}
+		if col.Charset == "" {
+			col.Charset = strings.ToLower(charset)
+		}
+		if col.Collation == "" && strings.EqualFold(col.Charset, charset) {
+			col.Collation = strings.ToLower(collation)
+		}
+	}
+	return nil
+}
+
+// parseAlterPartitions 解析 MySQL 的分区维护子句（不能与其他子句同时使用，因此解析到语句结尾）
+//
+//	PARTITION BY ... | REMOVE PARTITIONING
+//	ADD PARTITION {(partition_definition, ...) | PARTITIONS n}
+//	DROP PARTITION name, ... | COALESCE PARTITION n
+//	REORGANIZE PARTITION name, ... INTO (partition_definition, ...)
+func (g *grammar) parseAlterPartitions(table *TableSchema) error {
+	start := g.peek()
+	switch {
+	case g.peekSeq("PARTITION", "BY"):
+		return g.parsePartitionOptions(table)
+	case g.acceptSeq("REMOVE", "PARTITIONING"):
+		table.Partitioning = nil
+		return nil
+	}
+
+	partitioning := table.Partitioning
+	if partitioning == nil {
+		return g.semanticErrorf(start, "表 %s 未分区", table.Name)
+	}
+	switch {
+	case g.acceptSeq("ADD", "PARTITION"):
+		if g.acceptKeyword("PARTITIONS") {
+			n, err := g.parsePartitionNumber()
+			if err != nil {
+				return err
+			}
+			partitioning.Count = partitioning.PartitionCount() + n
+			return nil
+		}
+		partitions, err := g.parsePartitionDefinitions()
+		if err != nil {
+			return err
+		}
+		for _, partition := range partitions {
+			if partitionIndex(partitioning, partition.Name) >= 0 {
+				return g.semanticErrorf(start, "分区 %s 已存在", partition.Name)
+			}
+		}
+		partitioning.Partitions = append(partitioning.Partitions, partitions...)
+	case g.acceptSeq("DROP", "PARTITION"):
+		names, err := g.parseNameList()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			i := partitionIndex(partitioning, name)
+			if i < 0 {
+				return g.semanticErrorf(start, "分区 %s 不存在", name)
+			}
+			partitioning.Partitions = append(partitioning.Partitions[:i:i], partitioning.Partitions[i+1:]...)
+		}
+	case g.acceptSeq("COALESCE", "PARTITION"):
+		n, err := g.parsePartitionNumber()
+		if err != nil {
+			return err
+		}
+		if n >= partitioning.PartitionCount() {
+			return g.semanticErrorf(start, "合并后至少需要保留一个分区")
+		}
+		if len(partitioning.Partitions) > 0 {
+			partitioning.Partitions = partitioning.Partitions[:len(partitioning.Partitions)-n]
+		} else {
+			partitioning.Count -= n
+		}
+	case g.acceptSeq("REORGANIZE", "PARTITION"):
+		if g.atEOF() || g.peekSymbol(";") {
+			return nil
+		}
+		names, err := g.parseNameList()
+		if err != nil {
+			return err
+		}
+		if err := g.expectKeyword("INTO"); err != nil {
+			return err
+		}
+		partitions, err := g.parsePartitionDefinitions()
+		if err != nil {
+			return err
+		}
+		first := -1
+		for _, name := range names {
+			i := partitionIndex(partitioning, name)
+			if i < 0 {
+				return g.semanticErrorf(start, "分区 %s 不存在", name)
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+		var kept []*Partition
+		for i, partition := range partitioning.Partitions {
+			if i == first {
+				kept = append(kept, partitions...)
+			}
+			if !containsName(names, partition.Name) {
+				kept = append(kept, partition)
+			}
+		}
+		partitioning.Partitions = kept
+	}
+	return nil
+}
+
+// parsePartitionDefinitions 解析括号内以逗号分隔的分区定义
+func (g *grammar) parsePartitionDefinitions() ([]*Partition, error) {
+	if err := g.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var partitions []*Partition
+	for {
+		partition, err := g.parsePartitionDefinition()
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, partition)
+		if !g.acceptSymbol(",") {
+			break
+		}
+	}
+	return partitions, g.expectSymbol(")")
+}
+
+// parsePartitionNumber 解析分区数量
+func (g *grammar) parsePartitionNumber() (int, error) {
+	tok := g.next()
+	n, err := strconv.Atoi(tok.Value)
+	if tok.Kind != TokenNumber || err != nil || n <= 0 {
+		return 0, g.errorf(tok, "无效的分区数量")
+	}
+	return n, nil
+}
+
+// parseNameList 解析以逗号分隔的名称列表（不带括号）
+func (g *grammar) parseNameList() ([]string, error) {
+	var names []string
+	for {
+		name, err := g.parseQualifiedName()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !g.acceptSymbol(",") {
+			return names, nil
+		}
+	}
+}
+
+// parseDropIndex 解析 DROP INDEX 语句并从所属的表中删除索引
+// 未指定 ON tbl_name 时（PostgreSQL、SQLite）在所有表中按名称查找
+//
+//	DROP INDEX [CONCURRENTLY] [IF EXISTS] index_name [, ...] [ON tbl_name] [CASCADE | RESTRICT] [ALGORITHM ...] [LOCK ...]
+func (g *grammar) parseDropIndex(db *DatabaseSchema) error {
+	start := g.peek()
+	g.acceptSeq("DROP", "INDEX")
+	g.acceptKeyword("CONCURRENTLY")
+	ifExists := g.acceptSeq("IF", "EXISTS")
+	names, err := g.parseNameList()
+	if err != nil {
+		return err
+	}
+
+	var table *TableSchema
+	if g.acceptKeyword("ON") {
+		name, err := g.parseQualifiedName()
+		if err != nil {
+			return err
+		}
+		if table = db.Table(name); table == nil {
+			return g.semanticErrorf(start, "表 %s 未定义", name)
+		}
+	}
+
+	for _, name := range names {
+		owner := table
+		if owner == nil {
+			owner = indexOwner(db, name)
+		}
+		if (owner == nil || !removeIndex(owner, name)) && !ifExists {
+			return g.semanticErrorf(start, "索引 %s 不存在", name)
+		}
+	}
+	g.skipStatement()
+	return nil
+}
+
+// parseAlterIndex 解析 PostgreSQL 的 ALTER INDEX 语句，只处理重命名，其他子句（如 SET TABLESPACE）不影响表结构
+//
+//	ALTER INDEX [IF EXISTS] name RENAME TO new_name
+func (g *grammar) parseAlterIndex(db *DatabaseSchema) error {
+	start := g.peek()
+	g.acceptSeq("ALTER", "INDEX")
+	ifExists := g.acceptSeq("IF", "EXISTS")
+	name, err := g.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	if !g.acceptSeq("RENAME", "TO") {
+		g.skipStatement()
+		return nil
+	}
+	newName, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+
+	owner := indexOwner(db, name)
+	if owner == nil {
+		if ifExists {
+			return nil
+		}
+		return g.semanticErrorf(start, "索引 %s 不存在", name)
+	}
+	if indexOwner(db, newName) != nil {
+		return g.semanticErrorf(start, "索引 %s 已存在", newName)
+	}
+	owner.Index(name).Name = newName
+	return nil
+}
+
+// indexOwner 按索引名查找所属的表，不存在时返回 nil
+func indexOwner(db *DatabaseSchema, name string) *TableSchema {
+	for _, table := range db.Tables {
+		if table.Index(name) != nil {
+			return table
+		}
+	}
+	return nil
+}
+
+// parseDropTable 解析 DROP TABLE 语句并删除表
+// 带 CASCADE 时（PostgreSQL）同时删除其他表中引用被删除表的外键
+//
+//	DROP [TEMPORARY] TABLE [IF EXISTS] tbl_name [, tbl_name] ... [RESTRICT | CASCADE]
+func (g *grammar) parseDropTable(db *DatabaseSchema) error {
+	start := g.peek()
+	g.acceptKeyword("DROP")
+	g.acceptKeyword("TEMPORARY")
+	g.acceptKeyword("TABLE")
+	ifExists := g.acceptSeq("IF", "EXISTS")
+	names, err := g.parseNameList()
+	if err != nil {
+		return err
+	}
+	cascade := g.acceptKeyword("CASCADE")
+	g.acceptKeyword("RESTRICT")
+
+	for _, name := range names {
+		i := tableIndex(db, name)
+		if i < 0 {
+			if ifExists {
+				continue
+			}
+			return g.semanticErrorf(start, "表 %s 未定义", name)
+		}
+		db.Tables = append(db.Tables[:i:i], db.Tables[i+1:]...)
+		if !cascade {
+			continue
+		}
+		for _, table := range db.Tables {
+			kept := table.Constraints[:0:0]
+			for _, c := range table.Constraints {
+				if c.Type != "FOREIGN KEY" || c.RefTable != name {
+					kept = append(kept, c)
+				}
+			}
+			table.Constraints = kept
+		}
+	}
+	return nil
+}
+
+// parseRenameTable 解析 MySQL 的 RENAME TABLE 语句
+//
+//	RENAME TABLE tbl_name TO new_tbl_name [, tbl_name2 TO new_tbl_name2] ...
+func (g *grammar) parseRenameTable(db *DatabaseSchema) error {
+	g.acceptSeq("RENAME", "TABLE")
+	for {
+		start := g.peek()
+		oldName, err := g.parseQualifiedName()
+		if err != nil {
+			return err
+		}
+		if err := g.expectKeyword("TO"); err != nil {
+			return err
+		}
+		newName, err := g.parseQualifiedName()
+		if err != nil {
+			return err
+		}
+		table := db.Table(oldName)
+		if table == nil {
+			return g.semanticErrorf(start, "表 %s 未定义", oldName)
+		}
+		if err := renameTable(db, table, newName); err != nil {
+			return g.semanticErrorf(start, "%v", err)
+		}
+		if !g.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+// renameTable 重命名表，并更新其他表中引用该表的外键
+func renameTable(db *DatabaseSchema, table *TableSchema, name string) error {
+	if existing := db.Table(name); existing != nil && existing != table {
+		return fmt.Errorf("表 %s 已存在", name)
+	}
+	for _, t := range db.Tables {
+		for _, fk := range t.ForeignKeys() {
+			if fk.RefTable == table.Name {
+				fk.RefTable = name
+			}
+		}
+	}
+	table.Name = name
+	return nil
+}
+
+// renameColumnReferences 将主键、索引、约束以及其他表外键中对列 oldName 的引用改为 newName
+func renameColumnReferences(db *DatabaseSchema, table *TableSchema, oldName, newName string) {
+	rename := func(names []string) {
+		for i, name := range names {
+			if strings.EqualFold(name, oldName) {
+				names[i] = newName
+			}
+		}
+	}
+	rename(table.PrimaryKeys)
+	for _, idx := range table.Indexes {
+		rename(idx.Columns)
+	}
+	for _, c := range table.Constraints {
+		rename(c.Columns)
+	}
+	for _, t := range db.Tables {
+		for _, fk := range t.ForeignKeys() {
+			if fk.RefTable == table.Name {
+				rename(fk.RefColumns)
+			}
+		}
+	}
+}
+
+// dropColumn 删除列，并从主键、索引和约束中移除该列
+// MySQL、SQLite 从索引中移除该列（索引不再包含任何列时删除索引），PostgreSQL 删除包含该列的索引和主键
+func dropColumn(table *TableSchema, col *Column, dialect Dialect) {
+	i := columnIndex(table, col)
+	table.Columns = append(table.Columns[:i:i], table.Columns[i+1:]...)
+
+	if containsName(table.PrimaryKeys, col.Name) {
+		if dialect == PostgreSQL {
+			table.PrimaryKeys, table.PrimaryKeyName = nil, ""
+		} else {
+			table.PrimaryKeys = removeName(table.PrimaryKeys, col.Name)
+		}
+	}
+
+	indexes := table.Indexes[:0:0]
+	for _, idx := range table.Indexes {
+		for j := len(idx.Columns) - 1; j >= 0; j-- {
+			if strings.EqualFold(idx.Columns[j], col.Name) {
+				if dialect == PostgreSQL {
+					idx.Columns = nil
+					break
+				}
+				idx.removeKeyPart(j)
+			}
+		}
+		if len(idx.Columns) > 0 {
+			indexes = append(indexes, idx)
+		}
+	}
+	table.Indexes = indexes
+
+	constraints := table.Constraints[:0:0]
+	for _, c := range table.Constraints {
+		if !containsName(c.Columns, col.Name) {
+			constraints = append(constraints, c)
+		}
+	}
+	table.Constraints = constraints
+}
+
+// dropConstraint 按名称删除外键或 CHECK 约束，anyKind 为 true（DROP CONSTRAINT）时还会删除同名的唯一约束或主键
+func dropConstraint(table *TableSchema, name string, anyKind bool) bool {
+	for i, c := range table.Constraints {
+		if strings.EqualFold(c.Name, name) {
+			table.Constraints = append(table.Constraints[:i:i], table.Constraints[i+1:]...)
+			return true
+		}
+	}
+	if !anyKind {
+		return false
+	}
+	if idx := table.Index(name); idx != nil && idx.Type == "UNIQUE" {
+		return removeIndex(table, name)
+	}
+	if len(table.PrimaryKeys) > 0 && (strings.EqualFold(name, "PRIMARY") || strings.EqualFold(name, table.PrimaryKeyConstraintName())) {
+		table.PrimaryKeys, table.PrimaryKeyName = nil, ""
+		return true
+	}
+	return false
+}
+
+// removeIndex 按名称删除索引，返回索引是否存在
+func removeIndex(table *TableSchema, name string) bool {
+	for i, idx := range table.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			table.Indexes = append(table.Indexes[:i:i], table.Indexes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// removeKeyPart 删除索引的第 i 列
+func (idx *Index) removeKeyPart(i int) {
+	idx.Columns = append(idx.Columns[:i:i], idx.Columns[i+1:]...)
+	if idx.Lengths != nil {
+		idx.Lengths = append(idx.Lengths[:i:i], idx.Lengths[i+1:]...)
+	}
+	if idx.Descending != nil {
+		idx.Descending = append(idx.Descending[:i:i], idx.Descending[i+1:]...)
+	}
+}
+
+// moveColumn 将列移动到第 to 个位置（按移除该列之后的顺序计算）
+func moveColumn(table *TableSchema, col *Column, to int) {
+	from := columnIndex(table, col)
+	columns := append(table.Columns[:from:from], table.Columns[from+1:]...)
+	if to > from {
+		to--
+	}
+	table.Columns = append(columns[:to:to], append([]*Column{col}, columns[to:]...)...)
+}
+
+// columnIndex 返回列在表中的下标
+func columnIndex(table *TableSchema, col *Column) int {
+	for i, c := range table.Columns {
+		if c == col {
+			return i
+		}
+	}
+	return -1
+}
+
+// tableIndex 返回表在数据库结构中的下标，不存在时返回 -1
+func tableIndex(db *DatabaseSchema, name string) int {
+	for i, table := range db.Tables {
+		if table.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// partitionIndex 返回分区的下标，不存在时返回 -1
+func partitionIndex(partitioning *Partitioning, name string) int {
+	for i, partition := range partitioning.Partitions {
+		if strings.EqualFold(partition.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsName 判断名称列表中是否包含 name（不区分大小写）
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeName 从名称列表中移除 name（不区分大小写）
+func removeName(names []string, name string) []string {
+	var kept []string
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
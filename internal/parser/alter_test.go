@@ -0,0 +1,276 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// columnNames 返回表中按顺序排列的列名
+func columnNames(table *TableSchema) []string {
+	var names []string
+	for _, col := range table.Columns {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+func TestApplyMigrations(t *testing.T) {
+	migrations := []string{
+		// V1__init.sql
+		`CREATE TABLE users (
+			id INT NOT NULL AUTO_INCREMENT,
+			name VARCHAR(50),
+			email VARCHAR(100),
+			PRIMARY KEY (id),
+			KEY idx_name_email (name, email)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+		CREATE TABLE orders (id INT PRIMARY KEY, user_id INT, note TEXT);`,
+		// V2__add_phone.sql
+		`ALTER TABLE users ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '' AFTER name, ADD UNIQUE KEY uk_phone (phone);
+		ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;`,
+		// V3__refactor.sql
+		`ALTER TABLE users CHANGE COLUMN name full_name VARCHAR(100) NOT NULL, MODIFY email VARCHAR(255) FIRST,
+			ALGORITHM=INPLACE, LOCK=NONE;
+		ALTER TABLE users RENAME INDEX uk_phone TO uk_users_phone, DROP COLUMN phone;
+		ALTER TABLE orders DROP COLUMN note, ADD INDEX idx_user (user_id), COMMENT '订单';
+		CREATE INDEX idx_email ON users (email);
+		DROP INDEX idx_email ON users;
+		RENAME TABLE users TO accounts;
+		/*!40000 ALTER TABLE orders DISABLE KEYS */;`,
+	}
+
+	p := NewParser().(*DDLParser)
+	db := NewDatabaseSchema()
+	for i, sql := range migrations {
+		if err := p.Apply(db, sql); err != nil {
+			t.Fatalf("应用第 %d 个迁移失败: %v", i+1, err)
+		}
+	}
+
+	accounts := db.Table("accounts")
+	if accounts == nil || db.Table("users") != nil {
+		t.Fatalf("表应被重命名为 accounts: %v", db.Tables)
+	}
+	if names := columnNames(accounts); !reflect.DeepEqual(names, []string{"email", "id", "full_name"}) {
+		t.Errorf("列顺序错误: %v", names)
+	}
+	for i, col := range accounts.Columns {
+		if col.Position != i+1 {
+			t.Errorf("列 %s 的序号应为 %d，实际为 %d", col.Name, i+1, col.Position)
+		}
+	}
+	if col := accounts.Column("full_name"); col.Length != "100" || !col.NotNull {
+		t.Errorf("CHANGE 之后的列定义错误: %+v", col)
+	}
+
+	var indexes []string
+	for _, idx := range accounts.Indexes {
+		indexes = append(indexes, fmt.Sprintf("%s(%s)", idx.Name, strings.Join(idx.Columns, ",")))
+	}
+	if strings.Join(indexes, " ") != "idx_name_email(full_name,email)" {
+		t.Errorf("索引错误: %v", indexes)
+	}
+
+	orders := db.Table("orders")
+	if names := columnNames(orders); !reflect.DeepEqual(names, []string{"id", "user_id"}) {
+		t.Errorf("orders 的列错误: %v", names)
+	}
+	fks := orders.ForeignKeys()
+	if len(fks) != 1 || fks[0].RefTable != "accounts" || orders.Index("idx_user") == nil || orders.Options["COMMENT"] != "订单" {
+		t.Errorf("orders 的外键、索引或注释错误: %+v %+v %v", fks, orders.Indexes, orders.Options)
+	}
+}
+
+func TestApplyPostgresMigrations(t *testing.T) {
+	sql := `
+CREATE TABLE public.users (id integer NOT NULL, name text, status varchar(10), created_at timestamp);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE public.users OWNER TO app;
+ALTER TABLE users ALTER COLUMN status SET DEFAULT 'active'::character varying, ALTER COLUMN name SET NOT NULL;
+ALTER TABLE users ALTER COLUMN status TYPE varchar(20) USING status::varchar(20);
+ALTER TABLE users ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE users RENAME created_at TO inserted_at;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS name text;
+ALTER TABLE users DROP COLUMN IF EXISTS missing;
+CREATE INDEX users_name_idx ON users (name);
+CREATE INDEX IF NOT EXISTS users_name_idx ON users (name);
+CREATE TABLE posts (id int PRIMARY KEY, user_id int REFERENCES users (id));
+DROP INDEX IF EXISTS users_name_idx, other_idx;
+ALTER TABLE users RENAME TO members;
+ALTER TABLE IF EXISTS missing ADD COLUMN x int;
+DROP TABLE posts CASCADE;
+`
+	db, err := NewParserWithDialect(PostgreSQL).ParseScript(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(db.Tables) != 1 || db.Tables[0].Name != "members" {
+		t.Fatalf("表错误: %v", db.Tables)
+	}
+	members := db.Tables[0]
+	if !reflect.DeepEqual(members.PrimaryKeys, []string{"id"}) || len(members.Indexes) != 0 {
+		t.Errorf("主键或索引错误: %v %v", members.PrimaryKeys, members.Indexes)
+	}
+	status := members.Column("status")
+	if status.DefaultValue != "active" || status.Type != "VARCHAR" || status.Length != "20" {
+		t.Errorf("status 列错误: %+v", status)
+	}
+	if id := members.Column("id"); id.Identity != "BY DEFAULT" || !id.AutoInc {
+		t.Errorf("id 列应为标识列: %+v", id)
+	}
+	if !members.Column("name").NotNull || members.Column("inserted_at") == nil {
+		t.Errorf("列属性或重命名错误: %v", columnNames(members))
+	}
+}
+
+func TestApplyPostgresIndexAndSerial(t *testing.T) {
+	db, err := NewParserWithDialect(PostgreSQL).ParseScript(`
+CREATE TABLE users (id serial PRIMARY KEY, name text);
+CREATE INDEX users_name_idx ON users (name);
+ALTER INDEX users_name_idx RENAME TO idx_users_name;
+ALTER INDEX IF EXISTS missing RENAME TO other;
+ALTER INDEX idx_users_name SET TABLESPACE fast;
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	users := db.Table("users")
+	if users.Index("idx_users_name") == nil || users.Index("users_name_idx") != nil {
+		t.Errorf("索引重命名错误: %+v", users.Indexes)
+	}
+	// 去掉序列默认值后 SERIAL 成为普通整数列
+	if id := users.Column("id"); id.Type != "INTEGER" || id.AutoInc {
+		t.Errorf("id 列错误: %+v", id)
+	}
+
+	if _, err := NewParserWithDialect(PostgreSQL).ParseScript("CREATE TABLE t (id int);\nALTER INDEX missing RENAME TO other;"); err == nil {
+		t.Errorf("重命名不存在的索引应报错")
+	}
+}
+
+func TestApplyTableCharset(t *testing.T) {
+	db, err := NewParser().ParseScript(`
+CREATE TABLE t (a VARCHAR(10), b VARCHAR(10) CHARACTER SET latin1, n INT) DEFAULT CHARSET=utf8mb4;
+ALTER TABLE t DEFAULT CHARSET=latin1;
+CREATE TABLE u (a VARCHAR(10), b VARCHAR(10) COLLATE utf8mb4_bin) DEFAULT CHARSET=utf8mb4;
+ALTER TABLE u CONVERT TO CHARACTER SET latin1 COLLATE latin1_bin;`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	// 只修改默认字符集时已有的列保持原来的字符集
+	table := db.Table("t")
+	if table.Options["CHARSET"] != "latin1" || table.Column("a").Charset != "utf8mb4" || table.Column("b").Charset != "" || table.Column("n").Charset != "" {
+		t.Errorf("修改默认字符集后列的字符集错误: %+v %+v", table.Column("a"), table.Column("b"))
+	}
+
+	// CONVERT TO 转换所有字符列
+	table = db.Table("u")
+	if table.Options["COLLATE"] != "latin1_bin" || table.Column("a").Charset != "" || table.Column("b").Collation != "" {
+		t.Errorf("CONVERT TO 之后列的字符集错误: %+v %+v", table.Column("a"), table.Column("b"))
+	}
+}
+
+func TestApplyPartitions(t *testing.T) {
+	db, err := NewParser().ParseScript(`
+CREATE TABLE logs (id INT NOT NULL, y INT NOT NULL) PARTITION BY RANGE (y) (
+	PARTITION p1 VALUES LESS THAN (2020),
+	PARTITION p2 VALUES LESS THAN (2021),
+	PARTITION p3 VALUES LESS THAN (2022)
+);
+ALTER TABLE logs ADD PARTITION (PARTITION p4 VALUES LESS THAN (2023));
+ALTER TABLE logs DROP PARTITION p1;
+ALTER TABLE logs REORGANIZE PARTITION p2, p3 INTO (PARTITION p23 VALUES LESS THAN (2022));
+CREATE TABLE buckets (id INT NOT NULL) PARTITION BY HASH (id) PARTITIONS 4;
+ALTER TABLE buckets COALESCE PARTITION 2;
+ALTER TABLE buckets ADD PARTITION PARTITIONS 3;
+CREATE TABLE plain (id INT);
+ALTER TABLE plain PARTITION BY KEY (id) PARTITIONS 2;
+ALTER TABLE plain REMOVE PARTITIONING;`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	var names []string
+	for _, partition := range db.Table("logs").Partitioning.Partitions {
+		names = append(names, partition.Name)
+	}
+	if strings.Join(names, ",") != "p23,p4" {
+		t.Errorf("分区错误: %v", names)
+	}
+	if count := db.Table("buckets").Partitioning.PartitionCount(); count != 5 {
+		t.Errorf("HASH 分区数量应为 5，实际 %d", count)
+	}
+	if db.Table("plain").Partitioning != nil {
+		t.Errorf("REMOVE PARTITIONING 之后不应有分区")
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	base := "CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(10), KEY idx_name (name));\n"
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"表不存在", "ALTER TABLE missing ADD COLUMN x INT;"},
+		{"新增已存在的列", "ALTER TABLE t ADD COLUMN name INT;"},
+		{"删除不存在的列", "ALTER TABLE t DROP COLUMN missing;"},
+		{"修改不存在的列", "ALTER TABLE t MODIFY missing INT;"},
+		{"AFTER 不存在的列", "ALTER TABLE t ADD COLUMN x INT AFTER missing;"},
+		{"重复的索引", "ALTER TABLE t ADD INDEX idx_name (id);"},
+		{"重复的主键", "ALTER TABLE t ADD PRIMARY KEY (name);"},
+		{"删除不存在的索引", "DROP INDEX missing ON t;"},
+		{"删除不存在的表", "DROP TABLE missing;"},
+		{"重命名为已存在的表", "CREATE TABLE u (id INT); RENAME TABLE t TO u;"},
+		{"重复建索引", "CREATE INDEX idx_name ON t (id);"},
+		{"未分区的表", "ALTER TABLE t DROP PARTITION p1;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseScript(base + tt.sql)
+			if err == nil {
+				t.Fatalf("期望应用失败: %s", tt.sql)
+			}
+			// 对象不存在或重复定义属于语义错误，只报告位置，不附带当前的词法单元
+			if strings.Contains(err.Error(), "实际为") {
+				t.Errorf("语义错误不应附带词法单元: %v", err)
+			}
+		})
+	}
+
+	_, err := NewParser().ParseScript(base + "ALTER TABLE t DROP COLUMN missing;")
+	if err == nil || err.Error() != "第 2 行第 20 列: 列 t.missing 不存在" {
+		t.Errorf("错误信息不正确: %v", err)
+	}
+
+	// 无法识别的子句是语法错误，不能当作不影响表结构的子句跳过
+	for _, sql := range []string{
+		"ALTER TABLE t MODIFI COLUMN name VARCHAR(20) NOT NULL;",
+		"ALTER TABLE t DROPP COLUMN name;",
+		"ALTER TABLE t ALTER COLUMN name SETT DEFAULT 'a';",
+		"ALTER TABLE t ALTER INDEX idx_name HIDDEN;",
+	} {
+		if _, err := NewParser().ParseScript(base + sql); err == nil || !strings.Contains(err.Error(), "实际为") {
+			t.Errorf("期望 %s 报告语法错误，实际为 %v", sql, err)
+		}
+	}
+
+	// 不影响表结构的子句会被跳过
+	if _, err := NewParser().ParseScript(base + "ALTER TABLE t FORCE, ALGORITHM=INPLACE, LOCK=NONE, ENABLE KEYS;"); err != nil {
+		t.Errorf("期望跳过不影响表结构的子句，实际为 %v", err)
+	}
+	if _, err := NewParserWithDialect(PostgreSQL).ParseScript(base +
+		"ALTER TABLE t ALTER COLUMN name SET STATISTICS 100, SET (fillfactor = 70), VALIDATE CONSTRAINT c, OWNER TO app;"); err != nil {
+		t.Errorf("期望跳过不影响表结构的子句，实际为 %v", err)
+	}
+
+	// 带 IF EXISTS / IF NOT EXISTS 的语句在对象不存在或已存在时跳过
+	db, err := NewParser().ParseScript(base +
+		"DROP TABLE IF EXISTS missing; CREATE TABLE IF NOT EXISTS t (other INT); ALTER TABLE IF EXISTS missing DROP COLUMN x;")
+	if err != nil || len(db.Tables) != 1 || db.Tables[0].Column("other") != nil {
+		t.Errorf("IF [NOT] EXISTS 处理错误: %v", err)
+	}
+}
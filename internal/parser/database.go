@@ -0,0 +1,330 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DatabaseSchema 数据库结构（多张表）
+type DatabaseSchema struct {
+	Tables []*TableSchema // 表定义列表，保持脚本中的出现顺序
+}
+
+// NewDatabaseSchema 创建空的数据库结构
+func NewDatabaseSchema() *DatabaseSchema {
+	return &DatabaseSchema{
+		Tables: make([]*TableSchema, 0),
+	}
+}
+
+// Table 按名称查找表，不存在时返回 nil
+func (d *DatabaseSchema) Table(name string) *TableSchema {
+	for _, table := range d.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Clone 返回数据库结构的深拷贝
+func (d *DatabaseSchema) Clone() *DatabaseSchema {
+	clone := &DatabaseSchema{Tables: make([]*TableSchema, len(d.Tables))}
+	for i, table := range d.Tables {
+		clone.Tables[i] = table.Clone()
+	}
+	return clone
+}
+
+// AddTable 添加表定义，表名重复时返回错误
+func (d *DatabaseSchema) AddTable(table *TableSchema) error {
+	if d.Table(table.Name) != nil {
+		return fmt.Errorf("表 %s 重复定义", table.Name)
+	}
+	d.Tables = append(d.Tables, table)
+	return nil
+}
+
+// ParseScript 解析多语句 SQL 脚本，提取其中所有 CREATE TABLE 语句
+// 独立的 CREATE INDEX 语句和 COMMENT ON TABLE/COLUMN 语句会合并到对应的表中（表需先定义），
+// ALTER TABLE、ALTER INDEX、DROP INDEX、RENAME TABLE、DROP TABLE 按出现顺序应用到已定义的表上（见 Apply）；
+// SET、LOCK TABLES、/*!40101 ... */ 等其他语句会被跳过，
+// 因此 mysqldump --no-data 的输出和按顺序合并的迁移脚本都可以直接加载
+func (p *DDLParser) ParseScript(sql string) (*DatabaseSchema, error) {
+	db := NewDatabaseSchema()
+	if err := p.Apply(db, sql); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Apply 按顺序执行脚本中的 DDL 语句，在已有的数据库结构上演进（用于回放迁移脚本）
+// 语句引用了不存在的表、列或索引时返回错误，带 IF EXISTS / IF NOT EXISTS 的语句除外；
+// 出错时 db 可能已应用了出错语句之前的部分变更
+func (p *DDLParser) Apply(db *DatabaseSchema, sql string) error {
+	return p.apply(db, sql, nil)
+}
+
+// SkippedStatements 返回脚本中被跳过、无法由表结构重新生成的语句（如 INSERT、视图、触发器、存储过程），
+// SET、USE、LOCK TABLES 等只影响当前会话的语句除外
+func (p *DDLParser) SkippedStatements(sql string) ([]string, error) {
+	var skipped []string
+	err := p.apply(NewDatabaseSchema(), sql, func(statement string) {
+		skipped = append(skipped, statement)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return skipped, nil
+}
+
+// sessionStatements 只影响当前会话的语句（按开头的关键字识别），跳过时不会丢失结构信息
+var sessionStatements = []string{"SET", "USE", "LOCK", "UNLOCK", "BEGIN", "START", "COMMIT", "PRAGMA"}
+
+// apply 执行脚本中的 DDL 语句，skip 不为空时对每条被跳过的非会话语句调用（传入语句原文）
+func (p *DDLParser) apply(db *DatabaseSchema, sql string, skip func(statement string)) error {
+	g, err := newGrammar(sql, p.dialect)
+	if err != nil {
+		return err
+	}
+
+	for !g.atEOF() {
+		var err error
+		switch {
+		case g.acceptSymbol(";"):
+			continue
+		case g.peekCreateTable():
+			err = g.applyCreateTable(db)
+		case g.peekCreateIndex():
+			err = g.applyCreateIndex(db)
+		case g.peekSeq("COMMENT", "ON"):
+			err = g.parseCommentOn(db)
+		case g.peekSeq("ALTER", "TABLE"):
+			err = g.parseAlterTable(db)
+		case g.peekSeq("DROP", "INDEX"):
+			err = g.parseDropIndex(db)
+		case g.peekSeq("ALTER", "INDEX"):
+			err = g.parseAlterIndex(db)
+		case g.peekSeq("DROP", "TABLE"), g.peekSeq("DROP", "TEMPORARY", "TABLE"):
+			err = g.parseDropTable(db)
+		case g.peekSeq("RENAME", "TABLE"):
+			err = g.parseRenameTable(db)
+		case g.peekKeyword("DELIMITER"):
+			start := g.peek()
+			if g.skipDelimiterBlock() && skip != nil {
+				skip(g.rawFrom(start))
+			}
+			continue
+		default:
+			start := g.peek()
+			g.skipStatement()
+			if skip != nil && !isKeyword(start, sessionStatements...) {
+				skip(g.rawFrom(start))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !g.atEOF() && !g.peekSymbol(";") {
+			return g.errorf(g.peek(), "期望 ';'")
+		}
+	}
+	return nil
+}
+
+// peekCreateTable 判断当前是否为 CREATE [TEMPORARY | TEMP] TABLE 语句
+func (g *grammar) peekCreateTable() bool {
+	return g.peekSeq("CREATE", "TABLE") || g.peekSeq("CREATE", "TEMPORARY", "TABLE") || g.peekSeq("CREATE", "TEMP", "TABLE")
+}
+
+// applyCreateTable 解析 CREATE TABLE 语句并加入数据库结构
+// 带 IF NOT EXISTS 且表已存在时跳过该语句
+func (g *grammar) applyCreateTable(db *DatabaseSchema) error {
+	start := g.peek()
+	ifNotExists := g.peekIfNotExists(3)
+	table, err := g.parseCreateTable()
+	if err != nil {
+		return err
+	}
+	if ifNotExists && db.Table(table.Name) != nil {
+		return nil
+	}
+	if err := db.AddTable(table); err != nil {
+		return g.semanticErrorf(start, "%v", err)
+	}
+	return nil
+}
+
+// applyCreateIndex 解析独立的 CREATE INDEX 语句并将索引加入所属的表
+// 带 IF NOT EXISTS 且同名索引已存在时跳过该语句
+func (g *grammar) applyCreateIndex(db *DatabaseSchema) error {
+	start := g.peek()
+	ifNotExists := g.peekIfNotExists(4)
+	tableName, index, err := g.parseCreateIndex()
+	if err != nil {
+		return err
+	}
+	table := db.Table(tableName)
+	if table == nil {
+		return g.semanticErrorf(start, "索引所属的表 %s 未定义", tableName)
+	}
+	if index.Name != "" && table.Index(index.Name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return g.semanticErrorf(start, "索引 %s 已存在", index.Name)
+	}
+	if index.Name == "" && g.dialect == PostgreSQL {
+		// 独立的 CREATE INDEX 无论是否唯一，PostgreSQL 均以 _idx 结尾命名
+		used := make(map[string]bool)
+		for _, idx := range table.Indexes {
+			used[idx.Name] = true
+		}
+		index.Name = uniqueName(postgresConstraintName(table.Name, index.Columns, "idx"), "%s%d", 1, used)
+	}
+	table.Indexes = append(table.Indexes, index)
+	ensureIndexNames(table, g.dialect)
+	return nil
+}
+
+// skipDelimiterBlock 跳过 DELIMITER ;; ... DELIMITER ; 包裹的存储过程、触发器定义
+// 返回是否跳过了语句块（单独的 DELIMITER ; 返回 false）
+func (g *grammar) skipDelimiterBlock() bool {
+	g.next()
+	if g.peekSymbol(";") && !isSymbol(g.peekAt(1), ";") {
+		g.next()
+		return false
+	}
+	for !g.atEOF() {
+		if g.peekKeyword("DELIMITER") && isSymbol(g.peekAt(1), ";") && !isSymbol(g.peekAt(2), ";") {
+			g.pos += 2
+			return true
+		}
+		g.next()
+	}
+	return true
+}
+
+// peekCreateIndex 判断当前是否为 CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX 语句
+func (g *grammar) peekCreateIndex() bool {
+	if g.peekSeq("CREATE", "INDEX") {
+		return true
+	}
+	return isKeyword(g.peekAt(0), "CREATE") &&
+		isKeyword(g.peekAt(1), "UNIQUE", "FULLTEXT", "SPATIAL") &&
+		isKeyword(g.peekAt(2), "INDEX")
+}
+
+// parseCreateIndex 解析独立的建索引语句，返回表名和索引定义
+//
+//	CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX [CONCURRENTLY] [IF NOT EXISTS] [index_name]
+//	    [USING type] ON [ONLY] tbl_name [USING method] (key_part, ...) [index_option] ...
+//
+// INCLUDE、WHERE、ALGORITHM、LOCK 等其余子句不影响索引结构的比对，会被跳过
+func (g *grammar) parseCreateIndex() (string, *Index, error) {
+	g.next()
+	index := &Index{Type: "INDEX"}
+	if g.peekKeyword("UNIQUE", "FULLTEXT", "SPATIAL") {
+		index.Type = strings.ToUpper(g.next().Value)
+	}
+	g.next()
+	g.acceptKeyword("CONCURRENTLY")
+	g.acceptSeq("IF", "NOT", "EXISTS")
+
+	if !g.peekKeyword("ON", "USING") {
+		name, err := g.parseQualifiedName()
+		if err != nil {
+			return "", nil, err
+		}
+		index.Name = name
+	}
+	if g.acceptKeyword("USING") {
+		index.Algorithm = g.parseIndexAlgorithm()
+	}
+	if err := g.expectKeyword("ON"); err != nil {
+		return "", nil, err
+	}
+	g.acceptKeyword("ONLY")
+	table, err := g.parseQualifiedName()
+	if err != nil {
+		return "", nil, err
+	}
+	if g.acceptKeyword("USING") {
+		index.Algorithm = g.parseIndexAlgorithm()
+	}
+
+	if err := g.parseKeyParts(index); err != nil {
+		return "", nil, err
+	}
+	if err := g.parseIndexOptions(index); err != nil {
+		return "", nil, err
+	}
+	g.skipStatement()
+	return table, index, nil
+}
+
+// parseCommentOn 解析 COMMENT ON 语句（PostgreSQL），将注释写入对应的表或列
+//
+//	COMMENT ON {TABLE tbl_name | COLUMN tbl_name.col_name} IS {'text' | NULL}
+//
+// 其他对象（索引、约束、函数等）的注释会被跳过
+func (g *grammar) parseCommentOn(db *DatabaseSchema) error {
+	start := g.peek()
+	g.acceptSeq("COMMENT", "ON")
+	object := g.next()
+	if !isKeyword(object, "TABLE", "COLUMN") {
+		g.skipStatement()
+		return nil
+	}
+
+	var names []string
+	for {
+		name, err := g.parseIdent()
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		if !g.acceptSymbol(".") {
+			break
+		}
+	}
+	if err := g.expectKeyword("IS"); err != nil {
+		return err
+	}
+	comment := ""
+	if !g.acceptKeyword("NULL") {
+		var err error
+		if comment, err = g.parseString(); err != nil {
+			return err
+		}
+	}
+
+	tableName := names[len(names)-1]
+	if isKeyword(object, "COLUMN") {
+		if len(names) < 2 {
+			return g.errorf(start, "COMMENT ON COLUMN 需要指定表名")
+		}
+		tableName = names[len(names)-2]
+	}
+	table := db.Table(tableName)
+	if table == nil {
+		return g.semanticErrorf(start, "注释所属的表 %s 未定义", tableName)
+	}
+
+	if isKeyword(object, "TABLE") {
+		if comment == "" {
+			delete(table.Options, "COMMENT")
+		} else {
+			table.Options["COMMENT"] = comment
+		}
+		return nil
+	}
+	columnName := names[len(names)-1]
+	for _, col := range table.Columns {
+		if col.Name == columnName {
+			col.Comment = comment
+			return nil
+		}
+	}
+	return g.semanticErrorf(start, "注释所属的列 %s.%s 不存在", tableName, columnName)
+}
@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScriptMysqldump(t *testing.T) {
+	sql := "-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)\n" +
+		"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+		"/*!50503 SET NAMES utf8mb4 */;\n" +
+		"/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;\n" +
+		"\n" +
+		"--\n" +
+		"-- Table structure for table `orders`\n" +
+		"--\n" +
+		"\n" +
+		"DROP TABLE IF EXISTS `orders`;\n" +
+		"/*!40101 SET @saved_cs_client     = @@character_set_client */;\n" +
+		"/*!50503 SET character_set_client = utf8mb4 */;\n" +
+		"CREATE TABLE `orders` (\n" +
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `user_id` int NOT NULL,\n" +
+		"  `note` varchar(255) DEFAULT 'a;b' COMMENT 'semi; colon',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_user` (`user_id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n" +
+		"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+		"\n" +
+		"DROP TABLE IF EXISTS `users`;\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4\n" +
+		"/*!50100 PARTITION BY HASH (`id`) PARTITIONS 4 */;\n" +
+		"\n" +
+		"LOCK TABLES `users` WRITE;\n" +
+		"INSERT INTO `users` VALUES (1);\n" +
+		"UNLOCK TABLES;\n" +
+		"DELIMITER ;;\n" +
+		"CREATE TRIGGER `trg` BEFORE INSERT ON `users` FOR EACH ROW BEGIN SET NEW.id = 1; END ;;\n" +
+		"DELIMITER ;\n" +
+		"/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;\n" +
+		"-- Dump completed on 2024-01-01 00:00:00\n"
+
+	db, err := NewParser().ParseScript(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	if len(db.Tables) != 2 {
+		t.Fatalf("表数量错误，期望 2，得到 %d", len(db.Tables))
+	}
+
+	orders := db.Table("orders")
+	if orders == nil || len(orders.Columns) != 3 || len(orders.Indexes) != 1 {
+		t.Fatalf("orders 表解析错误: %+v", orders)
+	}
+
+	if orders.Columns[2].DefaultValue != "a;b" {
+		t.Errorf("字符串中的分号不应切分语句，得到默认值 %q", orders.Columns[2].DefaultValue)
+	}
+
+	if db.Tables[1].Name != "users" || len(db.Tables[1].PrimaryKeys) != 1 {
+		t.Errorf("users 表解析错误: %+v", db.Tables[1])
+	}
+	// 数据和触发器无法由表结构重新生成，SET、LOCK TABLES 等会话语句不计入
+	skipped, err := NewParser().(*DDLParser).SkippedStatements(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(skipped) != 2 || skipped[0] != "INSERT INTO `users` VALUES (1)" || !strings.HasPrefix(skipped[1], "DELIMITER ;;\nCREATE TRIGGER `trg`") {
+		t.Errorf("被跳过的语句不正确: %q", skipped)
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"重复表名", "CREATE TABLE t (id INT); CREATE TABLE t (id INT);"},
+		{"缺少分号", "CREATE TABLE a (id INT) CREATE TABLE b (id INT)"},
+		{"语法错误", "SET NAMES utf8; CREATE TABLE a (id INT,);"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewParser().ParseScript(tt.sql); err == nil {
+				t.Errorf("期望解析失败: %s", tt.sql)
+			}
+		})
+	}
+}
+
+func TestParseScriptPostgreSQL(t *testing.T) {
+	sql := `
+CREATE TABLE public."Users" (
+	id BIGSERIAL PRIMARY KEY,
+	Email character varying(255) NOT NULL UNIQUE,
+	status varchar(20) DEFAULT 'active'::character varying,
+	tags text[] DEFAULT '{}',
+	created_at timestamp(3) with time zone NOT NULL DEFAULT now(),
+	org_id integer REFERENCES orgs (id) ON DELETE CASCADE NOT NULL,
+	code integer GENERATED ALWAYS AS IDENTITY (START WITH 10),
+	CONSTRAINT users_code_check CHECK (code > 0)
+) WITH (fillfactor = 70);
+
+CREATE UNIQUE INDEX CONCURRENTLY users_lower_email ON ONLY public."Users" USING btree (lower(email) text_pattern_ops DESC NULLS LAST) WHERE status = 'active';
+CREATE INDEX ON "Users" (status);
+COMMENT ON COLUMN public."Users".email IS 'Login e-mail';
+COMMENT ON TABLE "Users" IS 'users';
+
+CREATE FUNCTION touch() RETURNS trigger AS $$ BEGIN NEW.updated_at = now(); RETURN NEW; END; $$ LANGUAGE plpgsql;
+`
+	db, err := NewParserWithDialect(PostgreSQL).ParseScript(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(db.Tables) != 1 {
+		t.Fatalf("期望 1 张表，实际为 %d 张", len(db.Tables))
+	}
+
+	table := db.Tables[0]
+	if table.Name != "Users" {
+		t.Errorf("带引号的表名应保留大小写，实际为 %s", table.Name)
+	}
+	if table.Options["COMMENT"] != "users" || table.Options["WITH"] != "fillfactor = 70" {
+		t.Errorf("表选项不正确: %v", table.Options)
+	}
+
+	expectedColumns := []Column{
+		{Name: "id", Type: "BIGSERIAL", AutoInc: true},
+		{Name: "email", Type: "CHARACTER VARYING", Length: "255", NotNull: true, Comment: "Login e-mail"},
+		{Name: "status", Type: "VARCHAR", Length: "20", DefaultValue: "active", DefaultQuoted: true},
+		{Name: "tags", Type: "TEXT[]", DefaultValue: "{}", DefaultQuoted: true},
+		{Name: "created_at", Type: "TIMESTAMPTZ", Length: "3", NotNull: true, DefaultValue: "NOW()"},
+		{Name: "org_id", Type: "INTEGER", NotNull: true},
+		{Name: "code", Type: "INTEGER", AutoInc: true, Identity: "ALWAYS"},
+	}
+	if len(table.Columns) != len(expectedColumns) {
+		t.Fatalf("期望 %d 列，实际为 %d 列", len(expectedColumns), len(table.Columns))
+	}
+	for i, exp := range expectedColumns {
+		col := *table.Columns[i]
+		col.Position = 0
+		if !reflect.DeepEqual(col, exp) {
+			t.Errorf("第 %d 列不正确，期望 %+v，实际为 %+v", i+1, exp, col)
+		}
+	}
+
+	expectedIndexes := []string{
+		"UNIQUE Users_email_key (email)",
+		"UNIQUE users_lower_email ((lower(email)))",
+		"INDEX Users_status_idx (status)",
+	}
+	if len(table.Indexes) != len(expectedIndexes) {
+		t.Fatalf("期望 %d 个索引，实际为 %d 个", len(expectedIndexes), len(table.Indexes))
+	}
+	for i, idx := range table.Indexes {
+		if got := idx.Type + " " + idx.Name + " (" + strings.Join(idx.Columns, ", ") + ")"; got != expectedIndexes[i] {
+			t.Errorf("第 %d 个索引不正确，期望 %s，实际为 %s", i+1, expectedIndexes[i], got)
+		}
+	}
+
+	fks := table.ForeignKeys()
+	if len(fks) != 1 || fks[0].Name != "Users_org_id_fkey" || fks[0].RefTable != "orgs" || fks[0].OnDelete != "CASCADE" {
+		t.Errorf("列级外键不正确: %+v", fks)
+	}
+}
+
+func TestParseScriptCreateIndex(t *testing.T) {
+	db, err := NewParser().ParseScript(`
+		CREATE TABLE users (id INT, name VARCHAR(50));
+		CREATE UNIQUE INDEX uk_name USING BTREE ON users (name(10)) ALGORITHM = INPLACE;
+	`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	indexes := db.Table("users").Indexes
+	if len(indexes) != 1 || indexes[0].Name != "uk_name" || indexes[0].Type != "UNIQUE" || indexes[0].PrefixLength(0) != 10 {
+		t.Errorf("CREATE INDEX 解析结果不正确: %+v", indexes)
+	}
+
+	if _, err := NewParser().ParseScript("CREATE INDEX idx ON missing (id);"); err == nil {
+		t.Error("期望索引所属的表未定义时返回错误")
+	}
+}
+
+func TestParseScriptSQLite(t *testing.T) {
+	sql := `
+CREATE TABLE IF NOT EXISTS [users] (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email TEXT NOT NULL UNIQUE ON CONFLICT REPLACE,
+	"Name" VARCHAR(50) COLLATE NOCASE DEFAULT 'x',
+	misc,
+	big UNSIGNED BIG INT,
+	created DATETIME DEFAULT (datetime('now')),
+	org_id INTEGER REFERENCES orgs ON DELETE CASCADE,
+	CONSTRAINT uq_name UNIQUE (Name, misc),
+	CHECK (big > 0)
+) WITHOUT ROWID, STRICT;
+CREATE INDEX IF NOT EXISTS main.idx_lower ON users (lower(email) DESC) WHERE email IS NOT NULL;
+CREATE TRIGGER touch AFTER UPDATE ON users BEGIN UPDATE users SET misc = 1; END;
+`
+	db, err := NewParserWithDialect(SQLite).ParseScript(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	table := db.Table("users")
+	if table == nil {
+		t.Fatal("未解析出 users 表")
+	}
+	if _, ok := table.Options["WITHOUT ROWID"]; !ok {
+		t.Errorf("缺少 WITHOUT ROWID 选项: %v", table.Options)
+	}
+
+	expectedColumns := []Column{
+		{Name: "id", Type: "INTEGER", AutoInc: true},
+		{Name: "email", Type: "TEXT", NotNull: true},
+		{Name: "Name", Type: "VARCHAR", Length: "50", DefaultValue: "x", DefaultQuoted: true, Collation: "NOCASE"},
+		{Name: "misc"},
+		{Name: "big", Type: "UNSIGNED BIG INT"},
+		{Name: "created", Type: "DATETIME", DefaultValue: "(datetime('now'))"},
+		{Name: "org_id", Type: "INTEGER"},
+	}
+	if len(table.Columns) != len(expectedColumns) {
+		t.Fatalf("期望 %d 列，实际为 %d 列", len(expectedColumns), len(table.Columns))
+	}
+	for i, exp := range expectedColumns {
+		col := *table.Columns[i]
+		col.Position = 0
+		if !reflect.DeepEqual(col, exp) {
+			t.Errorf("第 %d 列不正确，期望 %+v，实际为 %+v", i+1, exp, col)
+		}
+	}
+
+	expectedIndexes := []string{
+		"UNIQUE sqlite_autoindex_users_1 (email)",
+		"UNIQUE sqlite_autoindex_users_2 (Name, misc)",
+		"INDEX idx_lower ((lower(email)))",
+	}
+	if len(table.Indexes) != len(expectedIndexes) {
+		t.Fatalf("期望 %d 个索引，实际为 %d 个", len(expectedIndexes), len(table.Indexes))
+	}
+	for i, idx := range table.Indexes {
+		if got := idx.Type + " " + idx.Name + " (" + strings.Join(idx.Columns, ", ") + ")"; got != expectedIndexes[i] {
+			t.Errorf("第 %d 个索引不正确，期望 %s，实际为 %s", i+1, expectedIndexes[i], got)
+		}
+	}
+
+	if fks := table.ForeignKeys(); len(fks) != 1 || fks[0].RefTable != "orgs" || fks[0].OnDelete != "CASCADE" {
+		t.Errorf("列级外键不正确: %+v", fks)
+	}
+}
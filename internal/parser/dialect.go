@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect SQL 方言，决定词法规则（引号、转义、注释）和可识别的语法
+type Dialect string
+
+const (
+	MySQL      Dialect = "mysql"    // MySQL / MariaDB（默认）
+	PostgreSQL Dialect = "postgres" // PostgreSQL
+	SQLite     Dialect = "sqlite"   // SQLite
+)
+
+// dialectAliases 方言名称及其别名
+var dialectAliases = map[string]Dialect{
+	"mysql":      MySQL,
+	"mariadb":    MySQL,
+	"postgres":   PostgreSQL,
+	"postgresql": PostgreSQL,
+	"pg":         PostgreSQL,
+	"sqlite":     SQLite,
+	"sqlite3":    SQLite,
+}
+
+// ParseDialect 解析方言名称（不区分大小写，空字符串表示默认的 MySQL）
+func ParseDialect(name string) (Dialect, error) {
+	if name == "" {
+		return MySQL, nil
+	}
+	if dialect, ok := dialectAliases[strings.ToLower(name)]; ok {
+		return dialect, nil
+	}
+	return "", fmt.Errorf("不支持的方言: %s（可选 mysql、postgres、sqlite）", name)
+}
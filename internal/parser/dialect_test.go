@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Dialect
+		wantErr  bool
+	}{
+		{"", MySQL, false},
+		{"MySQL", MySQL, false},
+		{"mariadb", MySQL, false},
+		{"postgres", PostgreSQL, false},
+		{"PostgreSQL", PostgreSQL, false},
+		{"pg", PostgreSQL, false},
+		{"sqlite3", SQLite, false},
+		{"oracle", "", true},
+	}
+
+	for _, tt := range tests {
+		dialect, err := ParseDialect(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: 期望返回错误", tt.name)
+			}
+			continue
+		}
+		if err != nil || dialect != tt.expected {
+			t.Errorf("%q: 期望 %s，实际为 %s（%v）", tt.name, tt.expected, dialect, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"strings"
+)
+
+// NormalizeExpression 将生成列、CHECK 约束等表达式规范化，用于比较两个表达式是否等价
+// MySQL 会改写保存的表达式（如 a+b 保存为 (`a` + `b`)），因此比较前：
+//   - 去除注释、多余空白以及包裹整个表达式的括号
+//   - 标识符（含引号包裹的）和关键字统一为小写
+//   - 去除字符串前的字符集前缀（如 _utf8mb4'x'）
+//
+// 无法解析的表达式原样返回去除首尾空白后的文本
+func NormalizeExpression(expr string, dialect Dialect) string {
+	tokens, err := TokenizeDialect(expr, dialect)
+	if err != nil {
+		return strings.TrimSpace(expr)
+	}
+
+	var parts []string
+	for i, tok := range tokens {
+		switch tok.Kind {
+		case TokenEOF, TokenComment:
+			continue
+		case TokenIdent:
+			if strings.HasPrefix(tok.Value, "_") && i+1 < len(tokens) && tokens[i+1].Kind == TokenString && tokens[i+1].Pos == tok.End {
+				continue
+			}
+			parts = append(parts, strings.ToLower(tok.Value))
+		case TokenQuotedIdent:
+			parts = append(parts, strings.ToLower(tok.Value))
+		case TokenString:
+			parts = append(parts, "'"+strings.ReplaceAll(tok.Value, "'", "''")+"'")
+		default:
+			parts = append(parts, strings.ToLower(tok.Value))
+		}
+	}
+
+	for len(parts) >= 2 && parts[0] == "(" && parts[len(parts)-1] == ")" && enclosedByParens(parts) {
+		parts = parts[1 : len(parts)-1]
+	}
+	return strings.Join(parts, " ")
+}
+
+// enclosedByParens 判断首尾的括号是否互相匹配（即括号包裹了整个表达式）
+func enclosedByParens(parts []string) bool {
+	depth := 0
+	for i, part := range parts {
+		switch part {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 && i < len(parts)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// ExpressionColumns 返回表达式中引用的表中列名（按首次出现的顺序，不重复）
+func ExpressionColumns(expr string, schema *TableSchema, dialect Dialect) []string {
+	tokens, err := TokenizeDialect(expr, dialect)
+	if err != nil {
+		return nil
+	}
+	var columns []string
+	for _, tok := range tokens {
+		if tok.Kind != TokenIdent && tok.Kind != TokenQuotedIdent {
+			continue
+		}
+		for _, col := range schema.Columns {
+			if strings.EqualFold(col.Name, tok.Value) && !containsFold(columns, col.Name) {
+				columns = append(columns, col.Name)
+			}
+		}
+	}
+	return columns
+}
+
+// containsFold 判断列表中是否包含指定名称（不区分大小写）
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package parser
+
+import "testing"
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		a, b    string
+		equal   bool
+	}{
+		{MySQL, "a+b", "(`a` + `b`)", true},
+		{MySQL, "price > 0", "((`Price` > 0))", true},
+		{MySQL, "concat(_utf8mb4'#', qty)", "CONCAT('#', `qty`)", true},
+		{MySQL, "status IN ('a', 'b') /* 状态 */", "`status` in ('a','b')", true},
+		{MySQL, "(a + 1) * (b + 1)", "a + 1 * b + 1", false},
+		{MySQL, "name = 'A'", "name = 'a'", false},
+		{PostgreSQL, `"price" > 0`, "(price > 0)", true},
+		{SQLite, "a > 0", "a >= 0", false},
+	}
+
+	for _, tt := range tests {
+		a, b := NormalizeExpression(tt.a, tt.dialect), NormalizeExpression(tt.b, tt.dialect)
+		if (a == b) != tt.equal {
+			t.Errorf("%s 与 %s 的比较结果错误: %q / %q", tt.a, tt.b, a, b)
+		}
+	}
+}
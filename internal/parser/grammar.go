@@ -0,0 +1,1400 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// grammar 递归下降语法分析器，在词法单元序列（已去除注释）上工作
+type grammar struct {
+	src     string
+	tokens  []Token
+	pos     int
+	dialect Dialect
+}
+
+// newGrammar 按指定方言对 SQL 文本做词法分析并创建语法分析器
+func newGrammar(src string, dialect Dialect) (*grammar, error) {
+	all, err := TokenizeDialect(src, dialect)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]Token, 0, len(all))
+	for _, tok := range all {
+		if tok.Kind != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	return &grammar{src: src, tokens: tokens, dialect: dialect}, nil
+}
+
+// peek 查看当前词法单元
+func (g *grammar) peek() Token {
+	return g.peekAt(0)
+}
+
+// peekAt 查看当前位置之后第 n 个词法单元
+func (g *grammar) peekAt(n int) Token {
+	if g.pos+n >= len(g.tokens) {
+		return g.tokens[len(g.tokens)-1]
+	}
+	return g.tokens[g.pos+n]
+}
+
+// next 读取并前移一个词法单元
+func (g *grammar) next() Token {
+	tok := g.peek()
+	if tok.Kind != TokenEOF {
+		g.pos++
+	}
+	return tok
+}
+
+// atEOF 判断是否已到达输入结尾
+func (g *grammar) atEOF() bool {
+	return g.peek().Kind == TokenEOF
+}
+
+// isKeyword 判断词法单元是否为指定关键字之一（引用标识符不视为关键字）
+func isKeyword(tok Token, keywords ...string) bool {
+	if tok.Kind != TokenIdent {
+		return false
+	}
+	for _, kw := range keywords {
+		if strings.EqualFold(tok.Value, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSymbol 判断词法单元是否为指定符号
+func isSymbol(tok Token, sym string) bool {
+	return tok.Kind == TokenSymbol && tok.Value == sym
+}
+
+// peekKeyword 判断当前词法单元是否为指定关键字之一
+func (g *grammar) peekKeyword(keywords ...string) bool {
+	return isKeyword(g.peek(), keywords...)
+}
+
+// peekSeq 判断接下来的词法单元是否依次为指定关键字
+func (g *grammar) peekSeq(keywords ...string) bool {
+	for i, kw := range keywords {
+		if !isKeyword(g.peekAt(i), kw) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptSeq 若接下来的词法单元依次为指定关键字则全部消费
+func (g *grammar) acceptSeq(keywords ...string) bool {
+	if !g.peekSeq(keywords...) {
+		return false
+	}
+	g.pos += len(keywords)
+	return true
+}
+
+// acceptKeyword 若当前词法单元为指定关键字之一则消费并返回 true
+func (g *grammar) acceptKeyword(keywords ...string) bool {
+	if g.peekKeyword(keywords...) {
+		g.next()
+		return true
+	}
+	return false
+}
+
+// expectKeyword 要求当前词法单元为指定关键字
+func (g *grammar) expectKeyword(keyword string) error {
+	if !g.acceptKeyword(keyword) {
+		return g.errorf(g.peek(), "期望 %s", keyword)
+	}
+	return nil
+}
+
+// peekSymbol 判断当前词法单元是否为指定符号
+func (g *grammar) peekSymbol(sym string) bool {
+	return isSymbol(g.peek(), sym)
+}
+
+// acceptSymbol 若当前词法单元为指定符号则消费并返回 true
+func (g *grammar) acceptSymbol(sym string) bool {
+	if g.peekSymbol(sym) {
+		g.next()
+		return true
+	}
+	return false
+}
+
+// expectSymbol 要求当前词法单元为指定符号
+func (g *grammar) expectSymbol(sym string) error {
+	if !g.acceptSymbol(sym) {
+		return g.errorf(g.peek(), "期望 '%s'", sym)
+	}
+	return nil
+}
+
+// parseIdent 解析标识符（普通或引号包裹）
+// PostgreSQL 中未加引号的标识符不区分大小写，统一转为小写
+func (g *grammar) parseIdent() (string, error) {
+	tok := g.peek()
+	if tok.Kind != TokenIdent && tok.Kind != TokenQuotedIdent {
+		return "", g.errorf(tok, "期望标识符")
+	}
+	g.next()
+	if tok.Kind == TokenIdent && g.dialect == PostgreSQL {
+		return strings.ToLower(tok.Value), nil
+	}
+	return tok.Value, nil
+}
+
+// parseQualifiedName 解析可带库名前缀的名称（db.tbl），返回最后一段
+func (g *grammar) parseQualifiedName() (string, error) {
+	name, err := g.parseIdent()
+	if err != nil {
+		return "", err
+	}
+	for g.acceptSymbol(".") {
+		if name, err = g.parseIdent(); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// parseString 解析字符串字面量
+func (g *grammar) parseString() (string, error) {
+	tok := g.peek()
+	if tok.Kind != TokenString {
+		return "", g.errorf(tok, "期望字符串")
+	}
+	g.next()
+	return tok.Value, nil
+}
+
+// parseParenthesized 解析一对括号（支持嵌套），返回括号内的原始文本
+func (g *grammar) parseParenthesized() (string, error) {
+	open := g.peek()
+	if err := g.expectSymbol("("); err != nil {
+		return "", err
+	}
+	depth := 1
+	for depth > 0 {
+		tok := g.next()
+		switch {
+		case tok.Kind == TokenEOF:
+			return "", g.errorf(open, "括号未闭合")
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")"):
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(g.src[open.End:tok.Pos]), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// parseArgList 解析括号内以逗号分隔的参数列表，返回每个参数的原始文本
+func (g *grammar) parseArgList() ([]string, error) {
+	if err := g.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var args []string
+	start := g.peek().Pos
+	depth := 0
+	for {
+		tok := g.next()
+		switch {
+		case tok.Kind == TokenEOF:
+			return nil, g.errorf(tok, "括号未闭合")
+		case isSymbol(tok, "("):
+			depth++
+		case isSymbol(tok, ")") && depth > 0:
+			depth--
+		case (isSymbol(tok, ",") || isSymbol(tok, ")")) && depth == 0:
+			args = append(args, strings.TrimSpace(g.src[start:tok.Pos]))
+			if isSymbol(tok, ")") {
+				return args, nil
+			}
+			start = g.peek().Pos
+		}
+	}
+}
+
+// parseStringList 解析括号内以逗号分隔的字符串列表，如 ENUM('a','b')，返回去除引号后的值
+func (g *grammar) parseStringList() ([]string, error) {
+	if err := g.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	values := make([]string, 0)
+	for {
+		value, err := g.parseString()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if !g.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := g.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// skipStatement 跳过到当前语句结尾（不消费分号）
+func (g *grammar) skipStatement() {
+	for !g.atEOF() && !g.peekSymbol(";") {
+		g.next()
+	}
+}
+
+// rawFrom 返回从 start 词法单元到上一个已消费词法单元的原始文本
+func (g *grammar) rawFrom(start Token) string {
+	if g.pos == 0 {
+		return ""
+	}
+	end := g.tokens[g.pos-1].End
+	if end < start.Pos {
+		return ""
+	}
+	return g.src[start.Pos:end]
+}
+
+// errorf 生成带行列号的语法错误
+func (g *grammar) errorf(tok Token, format string, args ...interface{}) error {
+	line, col := lineCol(g.src, tok.Pos)
+	found := "输入结尾"
+	if tok.Kind != TokenEOF {
+		found = fmt.Sprintf("%s '%s'", tok.Kind, g.src[tok.Pos:tok.End])
+	}
+	return fmt.Errorf("第 %d 行第 %d 列: %s，实际为 %s", line, col, fmt.Sprintf(format, args...), found)
+}
+
+// semanticErrorf 生成带行列号的语义错误（如表、列不存在或重复定义），
+// 语句本身符合语法，因此不附带当前的词法单元
+func (g *grammar) semanticErrorf(tok Token, format string, args ...interface{}) error {
+	line, col := lineCol(g.src, tok.Pos)
+	return fmt.Errorf("第 %d 行第 %d 列: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// parseCreateTable 解析 CREATE TABLE 语句
+//
+//	CREATE [TEMPORARY | TEMP] TABLE [IF NOT EXISTS] tbl_name
+//	    (create_definition, ...) [table_options] [partition_options]
+func (g *grammar) parseCreateTable() (*TableSchema, error) {
+	if !g.peekKeyword("CREATE") {
+		return nil, g.errorf(g.peek(), "期望 CREATE TABLE 语句")
+	}
+	g.next()
+	if !g.acceptKeyword("TEMPORARY") {
+		g.acceptKeyword("TEMP")
+	}
+	if err := g.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+	g.acceptSeq("IF", "NOT", "EXISTS")
+
+	name, err := g.parseQualifiedName()
+	if err != nil {
+		return nil, fmt.Errorf("无法提取表名: %w", err)
+	}
+
+	if g.peekKeyword("LIKE", "AS", "SELECT") {
+		return nil, g.errorf(g.peek(), "不支持 CREATE TABLE ... LIKE/SELECT 语法")
+	}
+
+	schema := newTableSchema(name)
+
+	if err := g.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for {
+		if err := g.parseCreateDefinition(schema); err != nil {
+			return nil, err
+		}
+		if g.acceptSymbol(",") {
+			continue
+		}
+		if err := g.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	parseOptions := g.parseTableOptions
+	switch g.dialect {
+	case PostgreSQL:
+		parseOptions = g.parsePostgresTableOptions
+	case SQLite:
+		parseOptions = g.parseSQLiteTableOptions
+	}
+	if err := parseOptions(schema); err != nil {
+		return nil, err
+	}
+
+	ensureIndexNames(schema, g.dialect)
+	ensureForeignKeyNames(schema, g.dialect)
+	ensureCheckNames(schema, g.dialect)
+	normalizeColumnCharsets(schema)
+	return schema, nil
+}
+
+// parseCreateDefinition 解析一个建表定义项：列、主键、索引或约束
+func (g *grammar) parseCreateDefinition(schema *TableSchema) error {
+	var constraintName string
+	if g.acceptKeyword("CONSTRAINT") {
+		if !g.peekKeyword("PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+			name, err := g.parseIdent()
+			if err != nil {
+				return err
+			}
+			constraintName = name
+		}
+	}
+
+	switch {
+	case g.peekSeq("PRIMARY", "KEY"):
+		if g.dialect != MySQL {
+			schema.PrimaryKeyName = constraintName
+		}
+		return g.parsePrimaryKey(schema)
+	case g.peekIndexDefinition():
+		index, err := g.parseIndexDefinition()
+		if err != nil {
+			return err
+		}
+		if index.Name == "" {
+			index.Name = constraintName
+		}
+		if g.dialect == SQLite && index.Type == "UNIQUE" {
+			// SQLite 忽略唯一约束的名称，由 ensureIndexNames 按 sqlite_autoindex 规则命名
+			index.Name = ""
+		}
+		schema.Indexes = append(schema.Indexes, index)
+		return nil
+	case g.peekSeq("FOREIGN", "KEY"):
+		fk, err := g.parseForeignKey()
+		if err != nil {
+			return err
+		}
+		fk.Name = constraintName
+		schema.Constraints = append(schema.Constraints, fk)
+		return nil
+	case g.peekKeyword("CHECK"):
+		check, err := g.parseCheckConstraint(constraintName)
+		if err != nil {
+			return err
+		}
+		schema.Constraints = append(schema.Constraints, check)
+		return nil
+	}
+
+	return g.parseColumnDefinition(schema)
+}
+
+// peekIndexDefinition 判断当前是否为表级索引定义
+// INDEX、KEY、FULLTEXT、SPATIAL 开头的索引定义是 MySQL 语法，其他方言中可以作为未加引号的列名
+func (g *grammar) peekIndexDefinition() bool {
+	return g.peekKeyword("UNIQUE") || (g.dialect == MySQL && g.peekKeyword("INDEX", "KEY", "FULLTEXT", "SPATIAL"))
+}
+
+// parsePrimaryKey 解析表级主键定义
+func (g *grammar) parsePrimaryKey(schema *TableSchema) error {
+	g.acceptSeq("PRIMARY", "KEY")
+	if g.acceptKeyword("USING") {
+		g.next()
+	}
+	key := &Index{}
+	if err := g.parseKeyParts(key); err != nil {
+		return err
+	}
+	schema.PrimaryKeys = append(schema.PrimaryKeys, key.Columns...)
+	return g.parseIndexOptions(nil)
+}
+
+// parseIndexDefinition 解析索引定义
+//
+//	{INDEX | KEY} [name] [USING type] (key_part, ...) [index_option] ...
+//	UNIQUE [INDEX | KEY] [name] [USING type] (key_part, ...) [index_option] ...
+//	{FULLTEXT | SPATIAL} [INDEX | KEY] [name] (key_part, ...) [index_option] ...
+func (g *grammar) parseIndexDefinition() (*Index, error) {
+	index := &Index{Type: "INDEX"}
+
+	switch {
+	case g.acceptKeyword("UNIQUE"):
+		index.Type = "UNIQUE"
+		g.acceptKeyword("INDEX", "KEY")
+	case g.acceptKeyword("FULLTEXT"):
+		index.Type = "FULLTEXT"
+		g.acceptKeyword("INDEX", "KEY")
+	case g.acceptKeyword("SPATIAL"):
+		index.Type = "SPATIAL"
+		g.acceptKeyword("INDEX", "KEY")
+	default:
+		g.next()
+	}
+
+	if !g.peekSymbol("(") && !g.peekKeyword("USING") {
+		name, err := g.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		index.Name = name
+	}
+	if g.acceptKeyword("USING") {
+		index.Algorithm = g.parseIndexAlgorithm()
+	}
+
+	if err := g.parseKeyParts(index); err != nil {
+		return nil, err
+	}
+	return index, g.parseIndexOptions(index)
+}
+
+// parseIndexAlgorithm 解析 USING 之后的索引算法（MySQL 统一为大写，PostgreSQL 的访问方法统一为小写）
+func (g *grammar) parseIndexAlgorithm() string {
+	algorithm := g.next().Value
+	if g.dialect == MySQL {
+		return strings.ToUpper(algorithm)
+	}
+	return strings.ToLower(algorithm)
+}
+
+// parseKeyParts 解析索引列列表：(col [(len)] [ASC | DESC], ... | (expr) [ASC | DESC])
+// 填写索引的列名、前缀长度（没有任何列指定前缀长度时为 nil）和排序方向（没有降序列时为 nil）
+func (g *grammar) parseKeyParts(index *Index) error {
+	if err := g.expectSymbol("("); err != nil {
+		return err
+	}
+	var parts []string
+	var lengths []int
+	var descending []bool
+	hasPrefix, hasDescending := false, false
+	for {
+		length := 0
+		if g.peekSymbol("(") {
+			start := g.peek()
+			if _, err := g.parseParenthesized(); err != nil {
+				return err
+			}
+			parts = append(parts, g.rawFrom(start))
+		} else if g.dialect != MySQL && g.peek().Kind == TokenIdent && isSymbol(g.peekAt(1), "(") {
+			// PostgreSQL、SQLite 的函数调用可以不加括号直接作为索引表达式，统一记为 (expr)
+			start := g.peek()
+			g.next()
+			if _, err := g.parseParenthesized(); err != nil {
+				return err
+			}
+			parts = append(parts, "("+g.rawFrom(start)+")")
+		} else {
+			name, err := g.parseIdent()
+			if err != nil {
+				return err
+			}
+			if g.peekSymbol("(") {
+				tok := g.peek()
+				raw, err := g.parseParenthesized()
+				if err != nil {
+					return err
+				}
+				if length, err = strconv.Atoi(strings.TrimSpace(raw)); err != nil || length <= 0 {
+					return g.errorf(tok, "无效的索引前缀长度 %s", raw)
+				}
+				hasPrefix = true
+			}
+			parts = append(parts, name)
+		}
+		lengths = append(lengths, length)
+		desc := g.parseKeyPartOptions()
+		descending = append(descending, desc)
+		hasDescending = hasDescending || desc
+
+		if g.acceptSymbol(",") {
+			continue
+		}
+		if !hasPrefix {
+			lengths = nil
+		}
+		if !hasDescending {
+			descending = nil
+		}
+		index.Columns, index.Lengths, index.Descending = parts, lengths, descending
+		return g.expectSymbol(")")
+	}
+}
+
+// parseKeyPartOptions 解析索引列之后的选项，返回是否降序
+// 排序规则、操作符类、NULLS FIRST / LAST 不影响索引结构的比对，直接跳过
+//
+//	[COLLATE collation] [opclass] [ASC | DESC] [NULLS {FIRST | LAST}]
+func (g *grammar) parseKeyPartOptions() bool {
+	descending := false
+	for {
+		switch {
+		case g.acceptKeyword("ASC"):
+			descending = false
+		case g.acceptKeyword("DESC"):
+			descending = true
+		case g.acceptKeyword("NULLS"):
+			g.acceptKeyword("FIRST", "LAST")
+		case g.acceptKeyword("COLLATE"):
+			g.next()
+		case g.dialect == PostgreSQL && g.peek().Kind == TokenIdent:
+			// 操作符类，如 text_pattern_ops
+			g.next()
+		default:
+			return descending
+		}
+	}
+}
+
+// parseForeignKey 解析外键定义
+//
+//	FOREIGN KEY [index_name] (col, ...) REFERENCES tbl (col, ...)
+//	    [MATCH FULL | PARTIAL | SIMPLE] [ON DELETE action] [ON UPDATE action]
+func (g *grammar) parseForeignKey() (*Constraint, error) {
+	start := g.peek()
+	g.acceptSeq("FOREIGN", "KEY")
+	if !g.peekSymbol("(") {
+		if _, err := g.parseIdent(); err != nil {
+			return nil, err
+		}
+	}
+
+	fk := &Constraint{Type: "FOREIGN KEY"}
+	columns, err := g.parseColumnList()
+	if err != nil {
+		return nil, err
+	}
+	fk.Columns = columns
+
+	if err := g.parseReferences(fk); err != nil {
+		return nil, err
+	}
+	fk.Definition = g.rawFrom(start)
+	return fk, nil
+}
+
+// parseReferences 解析外键的引用部分
+//
+//	REFERENCES tbl (col, ...) [MATCH FULL | PARTIAL | SIMPLE] [ON DELETE action] [ON UPDATE action]
+//
+// 列级外键（PostgreSQL、SQLite）可以省略引用列，表示引用对方的主键
+func (g *grammar) parseReferences(fk *Constraint) error {
+	if err := g.expectKeyword("REFERENCES"); err != nil {
+		return err
+	}
+	var err error
+	if fk.RefTable, err = g.parseQualifiedName(); err != nil {
+		return err
+	}
+	if g.peekSymbol("(") {
+		if fk.RefColumns, err = g.parseColumnList(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch {
+		case g.acceptKeyword("MATCH"):
+			g.next()
+		case g.acceptSeq("ON", "DELETE"):
+			if fk.OnDelete, err = g.parseReferenceAction(); err != nil {
+				return err
+			}
+		case g.acceptSeq("ON", "UPDATE"):
+			if fk.OnUpdate, err = g.parseReferenceAction(); err != nil {
+				return err
+			}
+		case g.dialect != MySQL && g.acceptKeyword("DEFERRABLE"):
+		case g.dialect != MySQL && g.acceptSeq("NOT", "DEFERRABLE"):
+		case g.dialect != MySQL && g.acceptKeyword("INITIALLY"):
+			g.acceptKeyword("DEFERRED", "IMMEDIATE")
+		default:
+			return nil
+		}
+	}
+}
+
+// parseReferenceAction 解析外键动作：RESTRICT | CASCADE | SET NULL | NO ACTION | SET DEFAULT
+func (g *grammar) parseReferenceAction() (string, error) {
+	switch {
+	case g.acceptKeyword("RESTRICT"):
+		return "RESTRICT", nil
+	case g.acceptKeyword("CASCADE"):
+		return "CASCADE", nil
+	case g.acceptSeq("SET", "NULL"):
+		return "SET NULL", nil
+	case g.acceptSeq("SET", "DEFAULT"):
+		return "SET DEFAULT", nil
+	case g.acceptSeq("NO", "ACTION"):
+		return "NO ACTION", nil
+	}
+	return "", g.errorf(g.peek(), "无效的外键动作")
+}
+
+// parseColumnList 解析括号内的列名列表
+func (g *grammar) parseColumnList() ([]string, error) {
+	if err := g.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var columns []string
+	for {
+		name, err := g.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+		if g.acceptSymbol(",") {
+			continue
+		}
+		return columns, g.expectSymbol(")")
+	}
+}
+
+// parseIndexOptions 解析索引选项（USING、KEY_BLOCK_SIZE、COMMENT、可见性、WITH PARSER 等）
+// index 为 nil 时（主键）仅跳过选项
+func (g *grammar) parseIndexOptions(index *Index) error {
+	for {
+		switch {
+		case g.acceptKeyword("USING"):
+			algorithm := g.parseIndexAlgorithm()
+			if index != nil {
+				index.Algorithm = algorithm
+			}
+		case g.acceptKeyword("KEY_BLOCK_SIZE", "ENGINE_ATTRIBUTE", "SECONDARY_ENGINE_ATTRIBUTE"):
+			g.acceptSymbol("=")
+			g.next()
+		case g.acceptKeyword("COMMENT"):
+			comment, err := g.parseString()
+			if err != nil {
+				return err
+			}
+			if index != nil {
+				index.Comment = comment
+			}
+		case g.acceptKeyword("VISIBLE"):
+			if index != nil {
+				index.Invisible = false
+			}
+		case g.acceptKeyword("INVISIBLE"):
+			if index != nil {
+				index.Invisible = true
+			}
+		case g.acceptSeq("WITH", "PARSER"):
+			parser, err := g.parseIdent()
+			if err != nil {
+				return err
+			}
+			if index != nil {
+				index.Parser = strings.ToLower(parser)
+			}
+		case g.dialect == SQLite && g.acceptSeq("ON", "CONFLICT"):
+			g.next()
+		default:
+			return nil
+		}
+	}
+}
+
+// parseColumnDefinition 解析列定义：col_name data_type [attribute ...]
+func (g *grammar) parseColumnDefinition(schema *TableSchema) error {
+	name, err := g.parseIdent()
+	if err != nil {
+		return err
+	}
+	column := &Column{Name: name}
+
+	if err := g.parseDataType(column); err != nil {
+		return err
+	}
+
+	// 列级 CONSTRAINT name 只作用于紧随其后的约束
+	var constraintName string
+
+	// ALTER TABLE 中列定义之后可以跟 FIRST / AFTER 子句，或直接结束语句
+	for !g.atEOF() && !g.peekSymbol(",") && !g.peekSymbol(")") && !g.peekSymbol(";") && !g.peekKeyword("FIRST", "AFTER") {
+		tok := g.peek()
+		switch {
+		case g.acceptSeq("NOT", "NULL"):
+			column.NotNull = true
+		case g.acceptKeyword("NULL"):
+			column.NotNull = false
+		case g.acceptKeyword("DEFAULT"):
+			value, quoted, err := g.parseDefaultValue()
+			if err != nil {
+				return err
+			}
+			column.DefaultValue, column.DefaultQuoted = value, quoted
+		case g.acceptKeyword("AUTO_INCREMENT"):
+			column.AutoInc = true
+		case g.acceptKeyword("UNSIGNED"):
+			column.Unsigned = true
+		case g.acceptKeyword("ZEROFILL"):
+			column.Zerofill = true
+			column.Unsigned = true
+		case g.acceptKeyword("BINARY"):
+			column.Binary = true
+		case g.acceptKeyword("ASCII"):
+			column.Charset = "latin1"
+		case g.acceptKeyword("UNICODE"):
+			column.Charset = "ucs2"
+		case g.acceptKeyword("VISIBLE"):
+			column.Invisible = false
+		case g.acceptKeyword("INVISIBLE"):
+			column.Invisible = true
+		case g.acceptKeyword("SIGNED"):
+		case g.acceptKeyword("COMMENT"):
+			comment, err := g.parseString()
+			if err != nil {
+				return err
+			}
+			column.Comment = comment
+		case g.acceptKeyword("UNIQUE"):
+			g.acceptKeyword("KEY")
+			index := &Index{Name: column.Name, Columns: []string{column.Name}, Type: "UNIQUE"}
+			if g.dialect != MySQL {
+				// 由 ensureIndexNames 按方言的规则命名
+				index.Name = ""
+			}
+			schema.Indexes = append(schema.Indexes, index)
+		case g.acceptSeq("PRIMARY", "KEY"), g.acceptKeyword("KEY"):
+			schema.PrimaryKeys = append(schema.PrimaryKeys, column.Name)
+			if g.dialect != MySQL {
+				schema.PrimaryKeyName = constraintName
+			}
+			if g.dialect == SQLite {
+				g.acceptKeyword("ASC", "DESC")
+			}
+		case g.dialect == SQLite && g.acceptKeyword("AUTOINCREMENT"):
+			column.AutoInc = true
+		case g.dialect == SQLite && g.acceptSeq("ON", "CONFLICT"):
+			g.next()
+		case g.acceptSeq("CHARACTER", "SET"), g.acceptKeyword("CHARSET"):
+			charset, err := g.parseCharsetName()
+			if err != nil {
+				return err
+			}
+			column.Charset = strings.ToLower(charset)
+		case g.acceptKeyword("COLLATE"):
+			collation, err := g.parseCharsetName()
+			if err != nil {
+				return err
+			}
+			if g.dialect == MySQL {
+				collation = strings.ToLower(collation)
+			}
+			column.Collation = collation
+		case g.acceptKeyword("SRID"):
+			tok := g.next()
+			if tok.Kind != TokenNumber {
+				return g.errorf(tok, "期望 SRID 数值")
+			}
+			column.SRID = tok.Value
+		case g.acceptKeyword("COLUMN_FORMAT", "STORAGE"):
+			g.next()
+		case g.acceptKeyword("ENGINE_ATTRIBUTE", "SECONDARY_ENGINE_ATTRIBUTE"):
+			g.acceptSymbol("=")
+			g.next()
+		case g.acceptSeq("ON", "UPDATE"):
+			value, _, err := g.parseDefaultValue()
+			if err != nil {
+				return err
+			}
+			column.OnUpdate = value
+		case g.peekKeyword("GENERATED", "AS"):
+			if err := g.parseGeneratedColumn(column); err != nil {
+				return err
+			}
+		case g.acceptKeyword("CONSTRAINT"):
+			if constraintName, err = g.parseIdent(); err != nil {
+				return err
+			}
+			continue
+		case g.peekKeyword("REFERENCES"):
+			fk := &Constraint{Name: constraintName, Type: "FOREIGN KEY", Columns: []string{column.Name}}
+			start := g.peek()
+			if err := g.parseReferences(fk); err != nil {
+				return err
+			}
+			// InnoDB 忽略列级外键，PostgreSQL 和 SQLite 则会创建外键约束
+			if g.dialect != MySQL {
+				fk.Definition = g.rawFrom(start)
+				schema.Constraints = append(schema.Constraints, fk)
+			}
+		case g.peekKeyword("CHECK"):
+			check, err := g.parseCheckConstraint(constraintName)
+			if err != nil {
+				return err
+			}
+			check.Columns = []string{column.Name}
+			schema.Constraints = append(schema.Constraints, check)
+		default:
+			return g.errorf(tok, "无法识别列 %s 的属性", column.Name)
+		}
+		constraintName = ""
+	}
+
+	column.Position = len(schema.Columns) + 1
+	schema.Columns = append(schema.Columns, column)
+	return nil
+}
+
+// parseCharsetName 解析字符集或排序规则名称（可以是标识符或字符串，如 COLLATE 'utf8mb4_bin'）
+func (g *grammar) parseCharsetName() (string, error) {
+	if g.peek().Kind == TokenString {
+		return g.parseString()
+	}
+	return g.parseIdent()
+}
+
+// parseGeneratedColumn 解析生成列或标识列
+//
+//	[GENERATED ALWAYS] AS (expr) [VIRTUAL | STORED]
+//	GENERATED {ALWAYS | BY DEFAULT} AS IDENTITY [(sequence_option ...)]
+func (g *grammar) parseGeneratedColumn(column *Column) error {
+	identity := ""
+	if g.acceptKeyword("GENERATED") {
+		switch {
+		case g.acceptKeyword("ALWAYS"):
+			identity = "ALWAYS"
+		case g.acceptSeq("BY", "DEFAULT"):
+			identity = "BY DEFAULT"
+		default:
+			return g.errorf(g.peek(), "期望 ALWAYS 或 BY DEFAULT")
+		}
+	}
+	if err := g.expectKeyword("AS"); err != nil {
+		return err
+	}
+
+	if identity != "" && g.acceptKeyword("IDENTITY") {
+		column.Identity = identity
+		column.AutoInc = true
+		if g.peekSymbol("(") {
+			if _, err := g.parseParenthesized(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	expr, err := g.parseParenthesized()
+	if err != nil {
+		return err
+	}
+	column.Generated = expr
+	column.Stored = g.acceptKeyword("STORED", "PERSISTENT")
+	g.acceptKeyword("VIRTUAL")
+	return nil
+}
+
+// parseCheckConstraint 解析 CHECK 约束
+//
+//	CHECK (expr) [[NOT] ENFORCED] [NO INHERIT]
+func (g *grammar) parseCheckConstraint(name string) (*Constraint, error) {
+	if err := g.expectKeyword("CHECK"); err != nil {
+		return nil, err
+	}
+	expr, err := g.parseParenthesized()
+	if err != nil {
+		return nil, err
+	}
+	check := &Constraint{Name: name, Type: "CHECK", Definition: expr}
+	for {
+		switch {
+		case g.acceptSeq("NOT", "ENFORCED"):
+			check.NotEnforced = true
+		case g.acceptKeyword("ENFORCED"):
+		case g.acceptSeq("NO", "INHERIT"):
+		default:
+			return check, nil
+		}
+	}
+}
+
+// multiWordTypes 由多个关键字组成的数据类型
+var multiWordTypes = [][]string{
+	{"DOUBLE", "PRECISION"},
+	{"LONG", "VARBINARY"},
+	{"LONG", "VARCHAR"},
+	{"NATIONAL", "VARCHAR"},
+	{"NATIONAL", "CHAR"},
+	{"CHAR", "VARYING"},
+	{"CHARACTER", "VARYING"},
+	{"BIT", "VARYING"},
+}
+
+// serialTypes PostgreSQL 的自增整数类型及其实际存储类型
+var serialTypes = map[string]string{
+	"SMALLSERIAL": "SMALLINT", "SERIAL2": "SMALLINT",
+	"SERIAL": "INTEGER", "SERIAL4": "INTEGER",
+	"BIGSERIAL": "BIGINT", "SERIAL8": "BIGINT",
+}
+
+// sqliteConstraintKeywords SQLite 列约束的起始关键字，类型名由多个单词组成时遇到这些关键字即结束
+var sqliteConstraintKeywords = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "NOT": true, "NULL": true, "UNIQUE": true, "CHECK": true,
+	"DEFAULT": true, "COLLATE": true, "REFERENCES": true, "GENERATED": true, "AS": true,
+}
+
+// parseSQLiteDataType 解析 SQLite 的类型名
+// SQLite 的类型名可以是任意单词序列（如 UNSIGNED BIG INT），也可以省略
+func (g *grammar) parseSQLiteDataType(column *Column) error {
+	var words []string
+	for g.peek().Kind == TokenIdent && !sqliteConstraintKeywords[strings.ToUpper(g.peek().Value)] {
+		words = append(words, strings.ToUpper(g.next().Value))
+	}
+	column.Type = strings.Join(words, " ")
+	if len(words) > 0 && g.peekSymbol("(") {
+		args, err := g.parseArgList()
+		if err != nil {
+			return err
+		}
+		column.Length = strings.Join(args, ",")
+	}
+	return nil
+}
+
+// parseDataType 解析数据类型及其长度/精度参数
+func (g *grammar) parseDataType(column *Column) error {
+	if g.dialect == SQLite {
+		return g.parseSQLiteDataType(column)
+	}
+	tok := g.peek()
+	if tok.Kind != TokenIdent {
+		return g.errorf(tok, "列 %s 缺少数据类型", column.Name)
+	}
+
+	column.Type = strings.ToUpper(tok.Value)
+	g.next()
+	for _, words := range multiWordTypes {
+		if strings.EqualFold(words[0], column.Type) && g.acceptKeyword(words[1]) {
+			column.Type = strings.Join(words, " ")
+			break
+		}
+	}
+
+	if (column.Type == "ENUM" || column.Type == "SET") && g.peekSymbol("(") {
+		values, err := g.parseStringList()
+		if err != nil {
+			return err
+		}
+		column.Values = values
+	} else if g.peekSymbol("(") {
+		args, err := g.parseArgList()
+		if err != nil {
+			return err
+		}
+		column.Length = strings.Join(args, ",")
+	}
+
+	// TIMESTAMP [(p)] WITH TIME ZONE 即 TIMESTAMPTZ，WITHOUT TIME ZONE 为默认行为
+	if column.Type == "TIMESTAMP" || column.Type == "TIME" {
+		if g.acceptSeq("WITH", "TIME", "ZONE") {
+			column.Type += "TZ"
+		} else {
+			g.acceptSeq("WITHOUT", "TIME", "ZONE")
+		}
+	}
+
+	// 数组类型（PostgreSQL），如 INTEGER[]、TEXT[][]
+	for g.peekSymbol("[") && isSymbol(g.peekAt(1), "]") {
+		g.pos += 2
+		column.Type += "[]"
+	}
+
+	if _, ok := serialTypes[column.Type]; ok && g.dialect == PostgreSQL {
+		column.AutoInc = true
+	}
+	return nil
+}
+
+// parseDefaultValue 解析 DEFAULT / ON UPDATE 之后的值，quoted 表示值是否为字符串字面量
+// 字符串返回去除引号后的内容；关键字和函数统一为大写；括号表达式保留原文
+// PostgreSQL 的类型转换（如 'active'::character varying）不影响默认值本身，会被去除
+func (g *grammar) parseDefaultValue() (value string, quoted bool, err error) {
+	if value, quoted, err = g.parseDefaultOperand(); err != nil {
+		return "", false, err
+	}
+	for g.acceptSymbol("::") {
+		if err := g.parseDataType(&Column{Name: value}); err != nil {
+			return "", false, err
+		}
+	}
+	return value, quoted, nil
+}
+
+// parseDefaultOperand 解析默认值本身（不含类型转换）
+func (g *grammar) parseDefaultOperand() (string, bool, error) {
+	tok := g.peek()
+	switch tok.Kind {
+	case TokenString:
+		g.next()
+		return tok.Value, true, nil
+	case TokenNumber:
+		g.next()
+		return tok.Value, false, nil
+	case TokenSymbol:
+		if tok.Value == "(" {
+			if _, err := g.parseParenthesized(); err != nil {
+				return "", false, err
+			}
+			return g.rawFrom(tok), false, nil
+		}
+		if (tok.Value == "-" || tok.Value == "+") && g.peekAt(1).Kind == TokenNumber {
+			g.next()
+			num := g.next()
+			return tok.Value + num.Value, false, nil
+		}
+	case TokenIdent:
+		g.next()
+		next := g.peek()
+		// b'0101'、x'FF' 等进制字面量，以及 _utf8mb4'abc' 字符集引导符
+		if next.Kind == TokenString && next.Pos == tok.End {
+			g.next()
+			if strings.HasPrefix(tok.Value, "_") {
+				return next.Value, true, nil
+			}
+			return g.rawFrom(tok), false, nil
+		}
+		value := strings.ToUpper(tok.Value)
+		if g.peekSymbol("(") {
+			args, err := g.parseParenthesized()
+			if err != nil {
+				return "", false, err
+			}
+			value += "(" + args + ")"
+		}
+		return value, false, nil
+	}
+	return "", false, g.errorf(tok, "无效的默认值")
+}
+
+// tableOptionNames MySQL 支持的表选项名称
+var tableOptionNames = map[string]bool{
+	"AUTOEXTEND_SIZE": true, "AUTO_INCREMENT": true, "AVG_ROW_LENGTH": true, "CHARACTER": true,
+	"CHARSET": true, "CHECKSUM": true, "COLLATE": true, "COMMENT": true, "COMPRESSION": true,
+	"CONNECTION": true, "DATA": true, "INDEX": true, "DELAY_KEY_WRITE": true, "ENCRYPTION": true,
+	"ENGINE": true, "ENGINE_ATTRIBUTE": true, "INSERT_METHOD": true, "KEY_BLOCK_SIZE": true,
+	"MAX_ROWS": true, "MIN_ROWS": true, "PACK_KEYS": true, "PASSWORD": true, "ROW_FORMAT": true,
+	"SECONDARY_ENGINE": true, "SECONDARY_ENGINE_ATTRIBUTE": true, "STATS_AUTO_RECALC": true,
+	"STATS_PERSISTENT": true, "STATS_SAMPLE_PAGES": true, "STORAGE": true, "TABLESPACE": true,
+	"TYPE": true, "UNION": true,
+}
+
+// parsePostgresTableOptions 解析 PostgreSQL 的建表子句，直到语句结束
+//
+//	[INHERITS (parent, ...)] [PARTITION BY ...] [USING method]
+//	[WITH (storage_parameter = value, ...) | WITHOUT OIDS]
+//	[ON COMMIT {PRESERVE ROWS | DELETE ROWS | DROP}] [TABLESPACE tablespace_name]
+func (g *grammar) parsePostgresTableOptions(schema *TableSchema) error {
+	for !g.atEOF() && !g.peekSymbol(";") {
+		tok := g.peek()
+		switch {
+		case g.acceptSeq("PARTITION", "BY"):
+			kind := g.peek()
+			if !g.acceptKeyword("RANGE", "LIST", "HASH") {
+				return g.errorf(kind, "无法识别的分区方式")
+			}
+			expr, err := g.parseParenthesized()
+			if err != nil {
+				return err
+			}
+			schema.Partitioning = &Partitioning{Type: strings.ToUpper(kind.Value), Expression: expr}
+		case g.acceptKeyword("INHERITS"), g.acceptKeyword("WITH"):
+			name := strings.ToUpper(tok.Value)
+			raw, err := g.parseParenthesized()
+			if err != nil {
+				return err
+			}
+			schema.Options[name] = raw
+		case g.acceptSeq("WITHOUT", "OIDS"):
+		case g.acceptKeyword("USING"), g.acceptKeyword("TABLESPACE"):
+			name := strings.ToUpper(tok.Value)
+			value, err := g.parseIdent()
+			if err != nil {
+				return err
+			}
+			schema.Options[name] = value
+		case g.acceptSeq("ON", "COMMIT"):
+			g.acceptKeyword("PRESERVE", "DELETE", "DROP")
+			g.acceptKeyword("ROWS")
+		default:
+			return g.errorf(tok, "无法识别的表选项")
+		}
+	}
+	return nil
+}
+
+// parseSQLiteTableOptions 解析 SQLite 的表选项：WITHOUT ROWID、STRICT（以逗号分隔）
+func (g *grammar) parseSQLiteTableOptions(schema *TableSchema) error {
+	for !g.atEOF() && !g.peekSymbol(";") {
+		tok := g.peek()
+		switch {
+		case g.acceptSymbol(","):
+		case g.acceptSeq("WITHOUT", "ROWID"):
+			schema.Options["WITHOUT ROWID"] = ""
+		case g.acceptKeyword("STRICT"):
+			schema.Options["STRICT"] = ""
+		default:
+			return g.errorf(tok, "无法识别的表选项")
+		}
+	}
+	return nil
+}
+
+// parseTableOptions 解析表选项和分区定义，直到语句结束
+func (g *grammar) parseTableOptions(schema *TableSchema) error {
+	for !g.atEOF() && !g.peekSymbol(";") {
+		if g.acceptSymbol(",") {
+			continue
+		}
+		if g.peekSeq("PARTITION", "BY") {
+			return g.parsePartitionOptions(schema)
+		}
+		if err := g.parseTableOption(schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peekTableOption 判断当前是否为 MySQL 表选项的开头
+func (g *grammar) peekTableOption() bool {
+	tok := g.peek()
+	if isKeyword(tok, "DEFAULT") {
+		tok = g.peekAt(1)
+	}
+	return tok.Kind == TokenIdent && tableOptionNames[strings.ToUpper(tok.Value)]
+}
+
+// parseTableOption 解析单个表选项：[DEFAULT] name [=] value
+func (g *grammar) parseTableOption(schema *TableSchema) error {
+	g.acceptKeyword("DEFAULT")
+	tok := g.peek()
+	if tok.Kind != TokenIdent {
+		return g.errorf(tok, "无效的表选项")
+	}
+	name := strings.ToUpper(tok.Value)
+	if !tableOptionNames[name] {
+		return g.errorf(tok, "无法识别的表选项")
+	}
+	g.next()
+	switch name {
+	case "CHARACTER":
+		if err := g.expectKeyword("SET"); err != nil {
+			return err
+		}
+		name = "CHARSET"
+	case "DATA", "INDEX":
+		if err := g.expectKeyword("DIRECTORY"); err != nil {
+			return err
+		}
+		name += " DIRECTORY"
+	}
+
+	g.acceptSymbol("=")
+	value := g.peek()
+	switch {
+	case isSymbol(value, "("):
+		raw, err := g.parseParenthesized()
+		if err != nil {
+			return err
+		}
+		schema.Options[name] = raw
+	case value.Kind == TokenEOF || value.Kind == TokenSymbol:
+		return g.errorf(value, "表选项 %s 缺少值", name)
+	default:
+		g.next()
+		schema.Options[name] = value.Value
+	}
+	return nil
+}
+
+// parsePartitionOptions 解析 MySQL 的分区定义，直到语句结束
+//
+//	PARTITION BY {[LINEAR] HASH (expr) | [LINEAR] KEY [ALGORITHM={1 | 2}] (column_list)
+//	    | RANGE {(expr) | COLUMNS (column_list)} | LIST {(expr) | COLUMNS (column_list)}}
+//	[PARTITIONS num]
+//	[SUBPARTITION BY {[LINEAR] HASH (expr) | [LINEAR] KEY [ALGORITHM={1 | 2}] (column_list)} [SUBPARTITIONS num]]
+//	[(partition_definition [, partition_definition] ...)]
+func (g *grammar) parsePartitionOptions(schema *TableSchema) error {
+	g.next()
+	g.next()
+	partitioning := &Partitioning{}
+
+	kind := g.peek()
+	linear := g.acceptKeyword("LINEAR")
+	if linear {
+		kind = g.peek()
+	}
+	switch {
+	case g.acceptKeyword("HASH"):
+		partitioning.Type = "HASH"
+	case g.acceptKeyword("KEY"):
+		partitioning.Type = "KEY"
+		if g.acceptKeyword("ALGORITHM") {
+			g.acceptSymbol("=")
+			algorithm := g.next()
+			if algorithm.Kind != TokenNumber {
+				return g.errorf(algorithm, "期望 KEY 分区的 ALGORITHM 取值")
+			}
+			partitioning.Algorithm = algorithm.Value
+		}
+	case !linear && g.acceptKeyword("RANGE", "LIST"):
+		partitioning.Type = strings.ToUpper(kind.Value)
+		if g.acceptKeyword("COLUMNS") {
+			partitioning.Type += " COLUMNS"
+		}
+	default:
+		return g.errorf(kind, "无法识别的分区方式")
+	}
+	if linear {
+		partitioning.Type = "LINEAR " + partitioning.Type
+	}
+
+	expr, err := g.parseParenthesized()
+	if err != nil {
+		return err
+	}
+	partitioning.Expression = expr
+
+	if g.acceptKeyword("PARTITIONS") {
+		count := g.next()
+		if partitioning.Count, err = strconv.Atoi(count.Value); err != nil || partitioning.Count <= 0 {
+			return g.errorf(count, "无效的分区数量")
+		}
+	}
+
+	if g.acceptSeq("SUBPARTITION", "BY") {
+		start := g.peek()
+		g.acceptKeyword("LINEAR")
+		if !g.acceptKeyword("HASH", "KEY") {
+			return g.errorf(g.peek(), "无法识别的子分区方式")
+		}
+		if g.acceptKeyword("ALGORITHM") {
+			g.acceptSymbol("=")
+			g.next()
+		}
+		if _, err := g.parseParenthesized(); err != nil {
+			return err
+		}
+		if g.acceptKeyword("SUBPARTITIONS") {
+			g.next()
+		}
+		partitioning.Subpartition = strings.Join(strings.Fields(g.rawFrom(start)), " ")
+	}
+
+	if g.acceptSymbol("(") {
+		for {
+			partition, err := g.parsePartitionDefinition()
+			if err != nil {
+				return err
+			}
+			partitioning.Partitions = append(partitioning.Partitions, partition)
+			if !g.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := g.expectSymbol(")"); err != nil {
+			return err
+		}
+	}
+
+	if !g.atEOF() && !g.peekSymbol(";") {
+		return g.errorf(g.peek(), "分区定义之后存在多余内容")
+	}
+	schema.Partitioning = partitioning
+	return nil
+}
+
+// partitionOptionNames 分区定义中可出现的选项（ENGINE 必须与表一致，不记录）
+var partitionOptionNames = map[string]bool{
+	"COMMENT": true, "DATA": true, "INDEX": true, "MAX_ROWS": true, "MIN_ROWS": true,
+	"TABLESPACE": true, "NODEGROUP": true,
+}
+
+// parsePartitionDefinition 解析单个分区定义
+//
+//	PARTITION name [VALUES {LESS THAN {(expr | value_list) | MAXVALUE} | IN (value_list)}]
+//	    [[STORAGE] ENGINE [=] engine] [COMMENT [=] 'string'] [{DATA | INDEX} DIRECTORY [=] 'dir']
+//	    [MAX_ROWS [=] n] [MIN_ROWS [=] n] [TABLESPACE [=] name] [(subpartition_definition, ...)]
+func (g *grammar) parsePartitionDefinition() (*Partition, error) {
+	if err := g.expectKeyword("PARTITION"); err != nil {
+		return nil, err
+	}
+	name, err := g.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	partition := &Partition{Name: name, Options: make(map[string]string)}
+
+	if g.acceptKeyword("VALUES") {
+		switch {
+		case g.acceptSeq("LESS", "THAN"):
+			if g.acceptKeyword("MAXVALUE") {
+				partition.Values = "LESS THAN MAXVALUE"
+				break
+			}
+			raw, err := g.parseParenthesized()
+			if err != nil {
+				return nil, err
+			}
+			partition.Values = fmt.Sprintf("LESS THAN (%s)", raw)
+		case g.acceptKeyword("IN"):
+			raw, err := g.parseParenthesized()
+			if err != nil {
+				return nil, err
+			}
+			partition.Values = fmt.Sprintf("IN (%s)", raw)
+		default:
+			return nil, g.errorf(g.peek(), "期望 LESS THAN 或 IN")
+		}
+	}
+
+	for !g.peekSymbol(",") && !g.peekSymbol(")") {
+		tok := g.peek()
+		switch {
+		case g.peekSymbol("("):
+			raw, err := g.parseParenthesized()
+			if err != nil {
+				return nil, err
+			}
+			partition.Options["SUBPARTITIONS"] = raw
+			continue
+		case g.acceptSeq("STORAGE", "ENGINE"), g.acceptKeyword("ENGINE"):
+			g.acceptSymbol("=")
+			if _, err := g.parseIdent(); err != nil {
+				return nil, err
+			}
+			continue
+		case tok.Kind != TokenIdent || !partitionOptionNames[strings.ToUpper(tok.Value)]:
+			return nil, g.errorf(tok, "无法识别的分区选项")
+		}
+
+		g.next()
+		option := strings.ToUpper(tok.Value)
+		if option == "DATA" || option == "INDEX" {
+			if err := g.expectKeyword("DIRECTORY"); err != nil {
+				return nil, err
+			}
+			option += " DIRECTORY"
+		}
+		g.acceptSymbol("=")
+		value := g.next()
+		if value.Kind == TokenEOF || value.Kind == TokenSymbol {
+			return nil, g.errorf(value, "分区选项 %s 缺少值", option)
+		}
+		partition.Options[option] = value.Value
+	}
+
+	return partition, nil
+}
@@ -0,0 +1,374 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind 词法单元类型
+type TokenKind int
+
+const (
+	TokenEOF         TokenKind = iota // 输入结束
+	TokenIdent                        // 标识符或关键字
+	TokenQuotedIdent                  // 引号包裹的标识符（MySQL 为反引号，PostgreSQL 为双引号）
+	TokenString                       // 字符串字面量
+	TokenNumber                       // 数字字面量
+	TokenSymbol                       // 符号和运算符
+	TokenComment                      // 注释
+)
+
+// String 返回词法单元类型名称
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "EOF"
+	case TokenIdent:
+		return "标识符"
+	case TokenQuotedIdent:
+		return "引用标识符"
+	case TokenString:
+		return "字符串"
+	case TokenNumber:
+		return "数字"
+	case TokenSymbol:
+		return "符号"
+	case TokenComment:
+		return "注释"
+	default:
+		return "未知"
+	}
+}
+
+// Token 词法单元
+type Token struct {
+	Kind  TokenKind // 类型
+	Value string    // 值（字符串和引用标识符为去除引号、转义后的内容）
+	Pos   int       // 在源文本中的起始偏移
+	End   int       // 在源文本中的结束偏移（不含）
+}
+
+// multiCharSymbols 多字符运算符，按长度从长到短排列
+var multiCharSymbols = []string{"->>", "<=>", "->", "<=", ">=", "<>", "!=", "||", "&&", ":=", "::", "<<", ">>"}
+
+// Lexer SQL 词法分析器
+type Lexer struct {
+	src          string
+	pos          int
+	dialect      Dialect
+	versionDepth int // 当前所处的 /*!...*/ 版本注释层数
+}
+
+// NewLexer 创建 MySQL 方言的词法分析器
+func NewLexer(src string) *Lexer {
+	return NewLexerWithDialect(src, MySQL)
+}
+
+// NewLexerWithDialect 创建指定方言的词法分析器
+func NewLexerWithDialect(src string, dialect Dialect) *Lexer {
+	return &Lexer{src: src, dialect: dialect}
+}
+
+// Tokenize 按 MySQL 方言将 SQL 文本切分为词法单元（包含注释，以 EOF 结尾）
+func Tokenize(src string) ([]Token, error) {
+	return TokenizeDialect(src, MySQL)
+}
+
+// TokenizeDialect 按指定方言将 SQL 文本切分为词法单元
+func TokenizeDialect(src string, dialect Dialect) ([]Token, error) {
+	lx := NewLexerWithDialect(src, dialect)
+	var tokens []Token
+	for {
+		tok, err := lx.Next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.Kind == TokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+// Next 读取下一个词法单元
+func (l *Lexer) Next() (Token, error) {
+	l.skipSpaceAndVersionComments()
+
+	if l.pos >= len(l.src) {
+		return Token{Kind: TokenEOF, Pos: l.pos, End: l.pos}, nil
+	}
+
+	start := l.pos
+	ch := l.src[l.pos]
+	postgres := l.dialect == PostgreSQL
+	// PostgreSQL 与 SQLite 遵循标准 SQL：双引号为标识符，字符串不处理反斜杠转义，-- 之后不要求空白
+	standard := l.dialect != MySQL
+
+	switch {
+	case ch == '-' && strings.HasPrefix(l.src[l.pos:], "--") && (standard || l.isLineCommentStart()):
+		return l.lexLineComment(start), nil
+	case ch == '#' && !standard:
+		return l.lexLineComment(start), nil
+	case ch == '/' && strings.HasPrefix(l.src[l.pos:], "/*"):
+		return l.lexBlockComment(start)
+	case ch == '`' && !postgres:
+		return l.lexQuoted(start, '`', TokenQuotedIdent, false)
+	case ch == '"' && standard:
+		return l.lexQuoted(start, '"', TokenQuotedIdent, false)
+	case ch == '[' && l.dialect == SQLite:
+		return l.lexBracketed(start)
+	case ch == '\'' || ch == '"':
+		// PostgreSQL 的普通字符串不处理反斜杠转义（standard_conforming_strings）
+		return l.lexQuoted(start, ch, TokenString, !standard)
+	case postgres && (ch == 'E' || ch == 'e') && strings.HasPrefix(l.src[l.pos+1:], "'"):
+		l.pos++
+		tok, err := l.lexQuoted(l.pos, '\'', TokenString, true)
+		tok.Pos = start
+		return tok, err
+	case postgres && ch == '$':
+		if tok, ok, err := l.lexDollarQuoted(start); ok {
+			return tok, err
+		}
+	case isDigit(ch) || (ch == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		return l.lexNumber(start), nil
+	case isIdentStart(l.src[l.pos:]):
+		return l.lexIdent(start), nil
+	}
+
+	for _, sym := range multiCharSymbols {
+		if strings.HasPrefix(l.src[l.pos:], sym) {
+			l.pos += len(sym)
+			return Token{Kind: TokenSymbol, Value: sym, Pos: start, End: l.pos}, nil
+		}
+	}
+
+	l.pos++
+	return Token{Kind: TokenSymbol, Value: string(ch), Pos: start, End: l.pos}, nil
+}
+
+// skipSpaceAndVersionComments 跳过空白以及 MySQL 版本注释的起止标记
+// /*!40101 ... */ 中的内容会被 MySQL 执行，因此按普通 SQL 继续词法分析
+func (l *Lexer) skipSpaceAndVersionComments() {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		switch {
+		case unicode.IsSpace(r):
+			l.pos += size
+		case strings.HasPrefix(l.src[l.pos:], "/*!"):
+			l.pos += 3
+			l.versionDepth++
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		case l.versionDepth > 0 && strings.HasPrefix(l.src[l.pos:], "*/"):
+			l.pos += 2
+			l.versionDepth--
+		default:
+			return
+		}
+	}
+}
+
+// isLineCommentStart 判断 -- 是否构成注释（MySQL 要求其后跟空白或行尾）
+func (l *Lexer) isLineCommentStart() bool {
+	if l.pos+2 >= len(l.src) {
+		return true
+	}
+	next := l.src[l.pos+2]
+	return next == ' ' || next == '\t' || next == '\n' || next == '\r'
+}
+
+// lexLineComment 读取单行注释
+func (l *Lexer) lexLineComment(start int) Token {
+	end := strings.IndexByte(l.src[l.pos:], '\n')
+	if end == -1 {
+		l.pos = len(l.src)
+	} else {
+		l.pos += end
+	}
+	return Token{Kind: TokenComment, Value: l.src[start:l.pos], Pos: start, End: l.pos}
+}
+
+// lexBlockComment 读取块注释
+func (l *Lexer) lexBlockComment(start int) (Token, error) {
+	end := strings.Index(l.src[l.pos+2:], "*/")
+	if end == -1 {
+		return Token{}, l.errorf(start, "注释未闭合")
+	}
+	l.pos += 2 + end + 2
+	return Token{Kind: TokenComment, Value: l.src[start:l.pos], Pos: start, End: l.pos}, nil
+}
+
+// lexQuoted 读取引号包裹的字符串或标识符，处理重复引号以及（escapes 为 true 时）反斜杠转义
+func (l *Lexer) lexQuoted(start int, quote byte, kind TokenKind, escapes bool) (Token, error) {
+	var value strings.Builder
+	l.pos++
+	for l.pos < len(l.src) {
+		ch := l.src[l.pos]
+		switch {
+		case ch == quote:
+			if l.pos+1 < len(l.src) && l.src[l.pos+1] == quote {
+				value.WriteByte(quote)
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			return Token{Kind: kind, Value: value.String(), Pos: start, End: l.pos}, nil
+		case ch == '\\' && escapes && l.pos+1 < len(l.src):
+			value.WriteString(unescape(l.src[l.pos+1]))
+			l.pos += 2
+		default:
+			value.WriteByte(ch)
+			l.pos++
+		}
+	}
+	if kind == TokenString {
+		return Token{}, l.errorf(start, "字符串未闭合")
+	}
+	return Token{}, l.errorf(start, "标识符未闭合")
+}
+
+// lexBracketed 读取 SQLite 兼容 SQL Server 写法的 [标识符]（不支持转义）
+func (l *Lexer) lexBracketed(start int) (Token, error) {
+	end := strings.IndexByte(l.src[l.pos:], ']')
+	if end == -1 {
+		return Token{}, l.errorf(start, "标识符未闭合")
+	}
+	l.pos += end + 1
+	return Token{Kind: TokenQuotedIdent, Value: l.src[start+1 : l.pos-1], Pos: start, End: l.pos}, nil
+}
+
+// lexDollarQuoted 读取 PostgreSQL 的美元符号引用字符串（$$...$$ 或 $tag$...$tag$）
+// 不构成美元引用时返回 ok 为 false
+func (l *Lexer) lexDollarQuoted(start int) (Token, bool, error) {
+	end := start + 1
+	for end < len(l.src) && l.src[end] != '$' {
+		r, size := utf8.DecodeRuneInString(l.src[end:])
+		if !isIdentRune(r) || r == '$' || (end == start+1 && unicode.IsDigit(r)) {
+			return Token{}, false, nil
+		}
+		end += size
+	}
+	if end >= len(l.src) {
+		return Token{}, false, nil
+	}
+	delim := l.src[start : end+1]
+	body := strings.Index(l.src[end+1:], delim)
+	if body == -1 {
+		return Token{}, true, l.errorf(start, "字符串未闭合")
+	}
+	value := l.src[end+1 : end+1+body]
+	l.pos = end + 1 + body + len(delim)
+	return Token{Kind: TokenString, Value: value, Pos: start, End: l.pos}, true, nil
+}
+
+// unescape 处理 MySQL 字符串中的反斜杠转义
+func unescape(ch byte) string {
+	switch ch {
+	case '0':
+		return "\x00"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case 'b':
+		return "\b"
+	case 'Z':
+		return "\x1a"
+	case '%', '_':
+		// LIKE 通配符转义在字符串中保留反斜杠
+		return "\\" + string(ch)
+	default:
+		return string(ch)
+	}
+}
+
+// lexNumber 读取数字（整数、小数、科学计数法、十六进制）
+func (l *Lexer) lexNumber(start int) Token {
+	if strings.HasPrefix(l.src[l.pos:], "0x") || strings.HasPrefix(l.src[l.pos:], "0X") {
+		l.pos += 2
+		for l.pos < len(l.src) && isHexDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		return Token{Kind: TokenNumber, Value: l.src[start:l.pos], Pos: start, End: l.pos}
+	}
+
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		exp := l.pos + 1
+		if exp < len(l.src) && (l.src[exp] == '+' || l.src[exp] == '-') {
+			exp++
+		}
+		if exp < len(l.src) && isDigit(l.src[exp]) {
+			l.pos = exp
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+	}
+
+	// 以数字开头的标识符（如 1st_col）
+	if l.pos < len(l.src) && isIdentStart(l.src[l.pos:]) {
+		l.pos = start
+		return l.lexIdent(start)
+	}
+
+	return Token{Kind: TokenNumber, Value: l.src[start:l.pos], Pos: start, End: l.pos}
+}
+
+// lexIdent 读取标识符或关键字
+func (l *Lexer) lexIdent(start int) Token {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !isIdentRune(r) {
+			break
+		}
+		l.pos += size
+	}
+	return Token{Kind: TokenIdent, Value: l.src[start:l.pos], Pos: start, End: l.pos}
+}
+
+// errorf 生成带行列号的词法错误
+func (l *Lexer) errorf(pos int, format string, args ...interface{}) error {
+	line, col := lineCol(l.src, pos)
+	return fmt.Errorf("第 %d 行第 %d 列: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// lineCol 计算偏移量对应的行列号（从 1 开始）
+func lineCol(src string, pos int) (int, int) {
+	if pos > len(src) {
+		pos = len(src)
+	}
+	line := 1 + strings.Count(src[:pos], "\n")
+	col := pos - strings.LastIndex(src[:pos], "\n")
+	return line, col
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	sql := "CREATE TABLE `t` (a INT DEFAULT -1.5e3 COMMENT 'it''s \\'x\\'', # c\n b TEXT) /* block */ -- tail"
+
+	tokens, err := Tokenize(sql)
+	if err != nil {
+		t.Fatalf("词法分析失败: %v", err)
+	}
+
+	expected := []struct {
+		kind  TokenKind
+		value string
+	}{
+		{TokenIdent, "CREATE"},
+		{TokenIdent, "TABLE"},
+		{TokenQuotedIdent, "t"},
+		{TokenSymbol, "("},
+		{TokenIdent, "a"},
+		{TokenIdent, "INT"},
+		{TokenIdent, "DEFAULT"},
+		{TokenSymbol, "-"},
+		{TokenNumber, "1.5e3"},
+		{TokenIdent, "COMMENT"},
+		{TokenString, "it's 'x'"},
+		{TokenSymbol, ","},
+		{TokenComment, "# c"},
+		{TokenIdent, "b"},
+		{TokenIdent, "TEXT"},
+		{TokenSymbol, ")"},
+		{TokenComment, "/* block */"},
+		{TokenComment, "-- tail"},
+		{TokenEOF, ""},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("词法单元数量错误，期望 %d，得到 %d: %+v", len(expected), len(tokens), tokens)
+	}
+
+	for i, exp := range expected {
+		if tokens[i].Kind != exp.kind || tokens[i].Value != exp.value {
+			t.Errorf("第 %d 个词法单元错误，期望 %s %q，得到 %s %q", i, exp.kind, exp.value, tokens[i].Kind, tokens[i].Value)
+		}
+	}
+}
+
+func TestTokenizeVersionComment(t *testing.T) {
+	tokens, err := Tokenize("/*!40101 SET NAMES utf8 */;")
+	if err != nil {
+		t.Fatalf("词法分析失败: %v", err)
+	}
+
+	var values []string
+	for _, tok := range tokens {
+		if tok.Kind != TokenEOF {
+			values = append(values, tok.Value)
+		}
+	}
+
+	if len(values) != 4 || values[0] != "SET" || values[3] != ";" {
+		t.Errorf("版本注释内容应按普通 SQL 处理，得到 %v", values)
+	}
+}
+
+func TestTokenizeUnterminated(t *testing.T) {
+	for _, sql := range []string{"'abc", "`abc", "/* abc"} {
+		if _, err := Tokenize(sql); err == nil {
+			t.Errorf("期望词法分析失败: %s", sql)
+		}
+	}
+}
+
+func TestTokenizePostgreSQL(t *testing.T) {
+	sql := `"Users" 'a\b' E'x\'y' $$it's$$ $fn$ ; $fn$ # --comment`
+
+	tokens, err := TokenizeDialect(sql, PostgreSQL)
+	if err != nil {
+		t.Fatalf("词法分析失败: %v", err)
+	}
+
+	expected := []struct {
+		kind  TokenKind
+		value string
+	}{
+		{TokenQuotedIdent, "Users"},
+		{TokenString, `a\b`},
+		{TokenString, "x'y"},
+		{TokenString, "it's"},
+		{TokenString, " ; "},
+		{TokenSymbol, "#"},
+		{TokenComment, "--comment"},
+		{TokenEOF, ""},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("词法单元数量错误，期望 %d，得到 %d: %+v", len(expected), len(tokens), tokens)
+	}
+
+	for i, exp := range expected {
+		if tokens[i].Kind != exp.kind || tokens[i].Value != exp.value {
+			t.Errorf("第 %d 个词法单元错误，期望 %s %q，得到 %s %q", i, exp.kind, exp.value, tokens[i].Kind, tokens[i].Value)
+		}
+	}
+}
+
+func TestTokenizeSQLite(t *testing.T) {
+	tokens, err := TokenizeDialect("[order] `key` \"Name\" 'a\\b'", SQLite)
+	if err != nil {
+		t.Fatalf("词法分析失败: %v", err)
+	}
+
+	expected := []struct {
+		kind  TokenKind
+		value string
+	}{
+		{TokenQuotedIdent, "order"},
+		{TokenQuotedIdent, "key"},
+		{TokenQuotedIdent, "Name"},
+		{TokenString, `a\b`},
+		{TokenEOF, ""},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("词法单元数量错误，期望 %d，得到 %d: %+v", len(expected), len(tokens), tokens)
+	}
+	for i, exp := range expected {
+		if tokens[i].Kind != exp.kind || tokens[i].Value != exp.value {
+			t.Errorf("第 %d 个词法单元错误，期望 %s %q，得到 %s %q", i, exp.kind, exp.value, tokens[i].Kind, tokens[i].Value)
+		}
+	}
+}
@@ -2,312 +2,430 @@ package parser
 
 import (
 	"fmt"
-	"regexp"
+	"maps"
+	"slices"
 	"strings"
 )
 
 // TableSchema 表结构定义
 type TableSchema struct {
-	Name        string            // 表名
-	Columns     []*Column         // 列定义列表
-	PrimaryKeys []string          // 主键列名
-	Indexes     []*Index          // 索引定义
-	Constraints []*Constraint     // 约束定义
-	Options     map[string]string // 表选项（ENGINE, CHARSET 等）
+	Name           string            // 表名
+	Columns        []*Column         // 列定义列表
+	PrimaryKeys    []string          // 主键列名
+	PrimaryKeyName string            // 主键约束名（未命名时为空；MySQL 的主键名固定为 PRIMARY，不记录）
+	Indexes        []*Index          // 索引定义
+	Constraints    []*Constraint     // 约束定义
+	Options        map[string]string // 表选项（ENGINE, CHARSET 等）
+
+	Partitioning *Partitioning // 分区方案（未分区时为空）
 }
 
 // Column 列定义
 type Column struct {
-	Name         string // 列名
-	Type         string // 数据类型
-	Length       string // 长度
-	NotNull      bool   // 是否非空
-	DefaultValue string // 默认值
-	AutoInc      bool   // 是否自增
-	Comment      string // 注释
-	Unsigned     bool   // 是否无符号
+	Name          string   // 列名
+	Type          string   // 数据类型
+	Length        string   // 长度
+	Values        []string // ENUM / SET 的成员列表（去除引号后的原值，按定义顺序）
+	NotNull       bool     // 是否非空
+	DefaultValue  string   // 默认值（字符串字面量为去除引号后的内容）
+	DefaultQuoted bool     // 默认值是否为字符串字面量（如 '007'、'NULL'），否则为数字、关键字或表达式
+	AutoInc       bool     // 是否自增
+	Comment       string   // 注释
+	Unsigned      bool     // 是否无符号
+	Zerofill      bool     // 是否补零显示（ZEROFILL，隐含 UNSIGNED）
+	Charset       string   // 字符集（小写，与表默认字符集相同时为空）
+	Collation     string   // 排序规则（MySQL 中为小写，与表默认排序规则相同时为空）
+	Binary        bool     // BINARY 属性（表字符集已知时会转换为对应的 _bin 排序规则）
+	OnUpdate      string   // ON UPDATE 的值，如 CURRENT_TIMESTAMP
+	Invisible     bool     // 是否为不可见列
+	SRID          string   // 空间列的 SRID
+	Generated     string   // 生成列的表达式（AS 之后括号内的原文），为空表示普通列
+	Stored        bool     // 生成列是否为 STORED（否则为 VIRTUAL）
+	Identity      string   // 标识列的生成方式（PostgreSQL）：ALWAYS 或 BY DEFAULT，非空时 AutoInc 为 true
+	Position      int      // 在表中的序号（从 1 开始）
+}
+
+// characterTypes 受字符集、排序规则影响的列类型
+var characterTypes = map[string]bool{
+	"CHAR": true, "VARCHAR": true, "TINYTEXT": true, "TEXT": true, "MEDIUMTEXT": true, "LONGTEXT": true,
+	"ENUM": true, "SET": true,
+}
+
+// UsesCharset 判断列是否为受字符集影响的字符列（未显式指定时使用表的默认字符集）
+func (c *Column) UsesCharset() bool {
+	return characterTypes[c.Type]
+}
+
+// PrimaryKeyConstraintName 返回主键约束名，未命名时为 PostgreSQL 的默认名称 <表名>_pkey
+func (t *TableSchema) PrimaryKeyConstraintName() string {
+	if t.PrimaryKeyName != "" {
+		return t.PrimaryKeyName
+	}
+	return t.Name + "_pkey"
+}
+
+// HasDefault 判断列是否有默认值（空字符串也是默认值）
+func (c *Column) HasDefault() bool {
+	return c.DefaultValue != "" || c.DefaultQuoted
 }
 
 // Index 索引定义
 type Index struct {
-	Name    string   // 索引名
-	Columns []string // 索引列
-	Type    string   // 索引类型：INDEX, UNIQUE, FULLTEXT
+	Name       string   // 索引名
+	Columns    []string // 索引列（函数索引的表达式带外层括号，如 (lower(email))）
+	Lengths    []int    // 各索引列的前缀长度（0 表示无前缀，nil 表示均无前缀）
+	Descending []bool   // 各索引列是否降序（nil 表示均为升序）
+	Type       string   // 索引类型：INDEX, UNIQUE, FULLTEXT, SPATIAL
+	Algorithm  string   // 索引算法（MySQL 的 USING BTREE / HASH，PostgreSQL 的访问方法如 gin），未指定时为空
+	Comment    string   // 索引注释
+	Parser     string   // 全文索引的解析器（WITH PARSER）
+	Invisible  bool     // 是否为不可见索引
 }
 
-// Constraint 约束定义
-type Constraint struct {
-	Name       string // 约束名
-	Type       string // 约束类型：PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK
-	Definition string // 约束定义
+// PrefixLength 返回第 i 个索引列的前缀长度（0 表示无前缀）
+func (idx *Index) PrefixLength(i int) int {
+	if i < len(idx.Lengths) {
+		return idx.Lengths[i]
+	}
+	return 0
 }
 
-// Parser SQL 解析器接口
-type Parser interface {
-	Parse(sql string) (*TableSchema, error)
+// IsDescending 判断第 i 个索引列是否降序
+func (idx *Index) IsDescending(i int) bool {
+	return i < len(idx.Descending) && idx.Descending[i]
 }
 
-// SimpleParser 简单的 SQL 解析器实现
-// 注意：这是一个简化版本，生产环境建议使用更完善的 SQL 解析库
-type SimpleParser struct{}
+// Expression 返回第 i 个索引列的表达式（去掉外层括号），普通列返回空字符串
+func (idx *Index) Expression(i int) string {
+	if col := idx.Columns[i]; strings.HasPrefix(col, "(") && strings.HasSuffix(col, ")") {
+		return strings.TrimSpace(col[1 : len(col)-1])
+	}
+	return ""
+}
 
-// NewParser 创建新的解析器
-func NewParser() Parser {
-	return &SimpleParser{}
+// Constraint 约束定义
+type Constraint struct {
+	Name       string   // 约束名
+	Type       string   // 约束类型：PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK
+	Definition string   // 约束定义（CHECK 约束为括号内的表达式原文）
+	Columns    []string // 约束列（外键；列级 CHECK 约束为所在列）
+	RefTable   string   // 引用的表（外键）
+	RefColumns []string // 引用的列（外键）
+	OnDelete   string   // ON DELETE 动作（外键）：CASCADE, SET NULL, RESTRICT, NO ACTION, SET DEFAULT
+	OnUpdate   string   // ON UPDATE 动作（外键）
+
+	NotEnforced bool // CHECK 约束是否不强制检查（MySQL 的 NOT ENFORCED）
 }
 
-// Parse 解析 CREATE TABLE 语句
-func (p *SimpleParser) Parse(sql string) (*TableSchema, error) {
-	sql = strings.TrimSpace(sql)
+// Partitioning 分区方案
+// PostgreSQL 的分区是独立的表（CREATE TABLE ... PARTITION OF），只记录分区方式和分区键
+type Partitioning struct {
+	Type         string       // 分区方式：RANGE、RANGE COLUMNS、LIST、LIST COLUMNS、[LINEAR] HASH、[LINEAR] KEY
+	Algorithm    string       // KEY 分区的 ALGORITHM
+	Expression   string       // 分区表达式或分区列（括号内的原文）
+	Count        int          // PARTITIONS 子句指定的分区数（0 表示未指定）
+	Subpartition string       // SUBPARTITION BY 之后的子分区方式原文
+	Partitions   []*Partition // 逐个定义的分区（按定义顺序）
+}
 
-	// 提取表名
-	tableName, err := extractTableName(sql)
-	if err != nil {
-		return nil, err
-	}
+// Partition 分区定义
+type Partition struct {
+	Name    string            // 分区名
+	Values  string            // 分区边界：LESS THAN (...)、LESS THAN MAXVALUE 或 IN (...)，HASH / KEY 分区为空
+	Options map[string]string // 分区选项（COMMENT、DATA DIRECTORY 等，不含 ENGINE），子分区定义为 SUBPARTITIONS
+}
 
-	schema := &TableSchema{
-		Name:    tableName,
-		Columns: make([]*Column, 0),
-		Options: make(map[string]string),
+// PartitionCount 返回分区数量（逐个定义的分区数，未定义时为 PARTITIONS 子句指定的数量）
+func (p *Partitioning) PartitionCount() int {
+	if len(p.Partitions) > 0 {
+		return len(p.Partitions)
 	}
+	if p.Count == 0 {
+		return 1
+	}
+	return p.Count
+}
 
-	// 提取列定义部分
-	columnsDef, err := extractColumnsDefinition(sql)
-	if err != nil {
-		return nil, err
+// Column 按名称查找列（不区分大小写），不存在时返回 nil
+func (t *TableSchema) Column(name string) *Column {
+	for _, col := range t.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col
+		}
 	}
+	return nil
+}
 
-	// 解析每一列
-	lines := splitColumnDefinitions(columnsDef)
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+// Index 按名称查找索引（不区分大小写），不存在时返回 nil
+func (t *TableSchema) Index(name string) *Index {
+	for _, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return idx
 		}
+	}
+	return nil
+}
 
-		// 解析主键
-		if strings.HasPrefix(strings.ToUpper(line), "PRIMARY KEY") {
-			keys := extractPrimaryKeys(line)
-			schema.PrimaryKeys = append(schema.PrimaryKeys, keys...)
-			continue
+// Clone 返回表结构的深拷贝，修改副本不影响原结构
+func (t *TableSchema) Clone() *TableSchema {
+	clone := *t
+	clone.Columns = make([]*Column, len(t.Columns))
+	for i, col := range t.Columns {
+		c := *col
+		c.Values = slices.Clone(col.Values)
+		clone.Columns[i] = &c
+	}
+	clone.PrimaryKeys = slices.Clone(t.PrimaryKeys)
+	clone.Indexes = make([]*Index, len(t.Indexes))
+	for i, idx := range t.Indexes {
+		c := *idx
+		c.Columns = slices.Clone(idx.Columns)
+		c.Lengths = slices.Clone(idx.Lengths)
+		c.Descending = slices.Clone(idx.Descending)
+		clone.Indexes[i] = &c
+	}
+	clone.Constraints = make([]*Constraint, len(t.Constraints))
+	for i, constraint := range t.Constraints {
+		c := *constraint
+		c.Columns = slices.Clone(constraint.Columns)
+		c.RefColumns = slices.Clone(constraint.RefColumns)
+		clone.Constraints[i] = &c
+	}
+	clone.Options = maps.Clone(t.Options)
+	if t.Partitioning != nil {
+		partitioning := *t.Partitioning
+		partitioning.Partitions = make([]*Partition, len(t.Partitioning.Partitions))
+		for i, partition := range t.Partitioning.Partitions {
+			c := *partition
+			c.Options = maps.Clone(partition.Options)
+			partitioning.Partitions[i] = &c
 		}
+		clone.Partitioning = &partitioning
+	}
+	return &clone
+}
 
-		// 解析索引
-		if isIndex(line) {
-			index := parseIndex(line)
-			if index != nil {
-				schema.Indexes = append(schema.Indexes, index)
-			}
-			continue
+// ForeignKeys 返回表中的外键约束
+func (t *TableSchema) ForeignKeys() []*Constraint {
+	var fks []*Constraint
+	for _, c := range t.Constraints {
+		if c.Type == "FOREIGN KEY" {
+			fks = append(fks, c)
 		}
+	}
+	return fks
+}
 
-		// 解析普通列
-		column := parseColumn(line)
-		if column != nil {
-			schema.Columns = append(schema.Columns, column)
-			// 检查列定义中是否包含 PRIMARY KEY
-			if strings.Contains(strings.ToUpper(line), "PRIMARY KEY") {
-				schema.PrimaryKeys = append(schema.PrimaryKeys, column.Name)
-			}
+// Checks 返回表中的 CHECK 约束
+func (t *TableSchema) Checks() []*Constraint {
+	var checks []*Constraint
+	for _, c := range t.Constraints {
+		if c.Type == "CHECK" {
+			checks = append(checks, c)
 		}
 	}
+	return checks
+}
 
-	// 提取表选项
-	schema.Options = extractTableOptions(sql)
+// Parser SQL 解析器接口
+type Parser interface {
+	// Parse 解析单条 CREATE TABLE 语句
+	Parse(sql string) (*TableSchema, error)
 
-	return schema, nil
+	// ParseScript 解析包含多条语句的 SQL 脚本（如 schema.sql、mysqldump 输出）
+	ParseScript(sql string) (*DatabaseSchema, error)
 }
 
-// extractTableName 提取表名
-func extractTableName(sql string) (string, error) {
-	re := regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + "`?" + `([a-zA-Z0-9_]+)` + "`?")
-	matches := re.FindStringSubmatch(sql)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("无法提取表名")
-	}
-	return matches[1], nil
+// DDLParser 基于词法分析和递归下降语法的 DDL 解析器
+// 支持 SHOW CREATE TABLE 的完整输出（字符串、注释、表选项、分区子句等）
+type DDLParser struct {
+	dialect Dialect // SQL 方言
+}
+
+// NewParser 创建 MySQL 方言的解析器
+func NewParser() Parser {
+	return NewParserWithDialect(MySQL)
 }
 
-// extractColumnsDefinition 提取列定义部分
-func extractColumnsDefinition(sql string) (string, error) {
-	// 找到第一个 ( 和最后一个 )
-	start := strings.Index(sql, "(")
-	end := strings.LastIndex(sql, ")")
+// NewParserWithDialect 创建指定方言的解析器
+func NewParserWithDialect(dialect Dialect) Parser {
+	return &DDLParser{dialect: dialect}
+}
 
-	if start == -1 || end == -1 || start >= end {
-		return "", fmt.Errorf("无效的 CREATE TABLE 语法")
+// Parse 解析 CREATE TABLE 语句
+func (p *DDLParser) Parse(sql string) (*TableSchema, error) {
+	g, err := newGrammar(sql, p.dialect)
+	if err != nil {
+		return nil, err
 	}
 
-	return sql[start+1 : end], nil
-}
-
-// splitColumnDefinitions 分割列定义
-func splitColumnDefinitions(def string) []string {
-	var result []string
-	var current strings.Builder
-	var parenDepth int
-
-	for _, ch := range def {
-		switch ch {
-		case '(':
-			parenDepth++
-			current.WriteRune(ch)
-		case ')':
-			parenDepth--
-			current.WriteRune(ch)
-		case ',':
-			if parenDepth == 0 {
-				result = append(result, current.String())
-				current.Reset()
-			} else {
-				current.WriteRune(ch)
-			}
-		default:
-			current.WriteRune(ch)
-		}
+	schema, err := g.parseCreateTable()
+	if err != nil {
+		return nil, err
 	}
 
-	if current.Len() > 0 {
-		result = append(result, current.String())
+	g.acceptSymbol(";")
+	if !g.atEOF() {
+		return nil, g.errorf(g.peek(), "CREATE TABLE 语句之后存在多余内容")
 	}
 
-	return result
+	return schema, nil
 }
 
-// parseColumn 解析列定义
-func parseColumn(line string) *Column {
-	line = strings.TrimSpace(line)
-	parts := strings.Fields(line)
-
-	if len(parts) < 2 {
-		return nil
-	}
-
-	column := &Column{
-		Name: strings.Trim(parts[0], "`"),
+// newTableSchema 创建空的表结构
+func newTableSchema(name string) *TableSchema {
+	return &TableSchema{
+		Name:    name,
+		Columns: make([]*Column, 0),
+		Options: make(map[string]string),
 	}
+}
 
-	// 解析数据类型和长度
-	typeStr := parts[1]
-	if strings.Contains(typeStr, "(") {
-		re := regexp.MustCompile(`([A-Z]+)\(([^)]+)\)`)
-		matches := re.FindStringSubmatch(strings.ToUpper(typeStr))
-		if len(matches) >= 3 {
-			column.Type = matches[1]
-			column.Length = matches[2]
+// ensureIndexNames 为未命名的索引补全名称
+// MySQL：取首列名，重名时追加 _2、_3；PostgreSQL：<表名>_<列名...>_key（唯一约束）或 _idx，重名时追加序号；
+// SQLite：唯一约束与 SQLite 自动创建的索引同名，即 sqlite_autoindex_<表名>_<序号>
+func ensureIndexNames(schema *TableSchema, dialect Dialect) {
+	used := make(map[string]bool)
+	for _, idx := range schema.Indexes {
+		if idx.Name != "" {
+			used[idx.Name] = true
 		}
-	} else {
-		column.Type = strings.ToUpper(typeStr)
 	}
-
-	// 解析其他属性
-	upperLine := strings.ToUpper(line)
-	column.NotNull = strings.Contains(upperLine, "NOT NULL")
-	column.AutoInc = strings.Contains(upperLine, "AUTO_INCREMENT")
-	column.Unsigned = strings.Contains(upperLine, "UNSIGNED")
-
-	// 解析默认值
-	if strings.Contains(upperLine, "DEFAULT") {
-		re := regexp.MustCompile(`(?i)DEFAULT\s+('([^']*)'|"([^"]*)"|([^\s,]+))`)
-		matches := re.FindStringSubmatch(line)
-		if len(matches) > 1 {
-			for i := 2; i < len(matches); i++ {
-				if matches[i] != "" {
-					column.DefaultValue = matches[i]
-					break
-				}
+	autoIndexes := 0
+	for _, idx := range schema.Indexes {
+		if idx.Name != "" {
+			continue
+		}
+		if dialect == SQLite && idx.Type == "UNIQUE" {
+			for idx.Name == "" || used[idx.Name] {
+				autoIndexes++
+				idx.Name = fmt.Sprintf("sqlite_autoindex_%s_%d", schema.Name, autoIndexes)
+			}
+			used[idx.Name] = true
+			continue
+		}
+		if dialect == PostgreSQL {
+			suffix := "idx"
+			if idx.Type == "UNIQUE" {
+				suffix = "key"
 			}
+			idx.Name = uniqueName(postgresConstraintName(schema.Name, idx.Columns, suffix), "%s%d", 1, used)
+			continue
+		}
+		base := "functional_index"
+		if len(idx.Columns) > 0 && !isExpressionKeyPart(idx.Columns[0]) {
+			base = idx.Columns[0]
 		}
+		idx.Name = uniqueName(base, "%s_%d", 2, used)
 	}
+}
 
-	// 解析注释
-	if strings.Contains(upperLine, "COMMENT") {
-		re := regexp.MustCompile(`(?i)COMMENT\s+'([^']*)'`)
-		matches := re.FindStringSubmatch(line)
-		if len(matches) >= 2 {
-			column.Comment = matches[1]
+// normalizeColumnCharsets 按 SHOW CREATE TABLE 的规则规范化列的字符集和排序规则：
+// BINARY 属性转换为所用字符集的 _bin 排序规则，与表默认值相同的字符集、排序规则不再单独记录
+func normalizeColumnCharsets(schema *TableSchema) {
+	tableCharset := strings.ToLower(schema.Options["CHARSET"])
+	tableCollation := strings.ToLower(schema.Options["COLLATE"])
+	for _, col := range schema.Columns {
+		charset := col.Charset
+		if charset == "" {
+			charset = tableCharset
+		}
+		if col.Binary && col.Collation == "" && charset != "" {
+			col.Collation = charset + "_bin"
+			col.Binary = false
+		}
+		if col.Charset != "" && col.Charset == tableCharset {
+			col.Charset = ""
+		}
+		if col.Collation != "" && strings.EqualFold(col.Collation, tableCollation) {
+			col.Collation = ""
 		}
 	}
-
-	return column
 }
 
-// extractPrimaryKeys 提取主键列名
-func extractPrimaryKeys(line string) []string {
-	re := regexp.MustCompile(`PRIMARY\s+KEY\s*\(([^)]+)\)`)
-	matches := re.FindStringSubmatch(strings.ToUpper(line))
-	if len(matches) < 2 {
-		return nil
+// ensureForeignKeyNames 为未命名的外键补全名称
+// MySQL 与 InnoDB 规则一致：<表名>_ibfk_<序号>；PostgreSQL：<表名>_<列名...>_fkey
+func ensureForeignKeyNames(schema *TableSchema, dialect Dialect) {
+	used := make(map[string]bool)
+	for _, c := range schema.Constraints {
+		used[c.Name] = true
 	}
-
-	keys := strings.Split(matches[1], ",")
-	result := make([]string, 0, len(keys))
-	for _, key := range keys {
-		result = append(result, strings.Trim(strings.TrimSpace(key), "`"))
+	n := 0
+	for _, fk := range schema.ForeignKeys() {
+		if fk.Name != "" {
+			continue
+		}
+		if dialect == PostgreSQL {
+			fk.Name = uniqueName(postgresConstraintName(schema.Name, fk.Columns, "fkey"), "%s%d", 1, used)
+			continue
+		}
+		for {
+			n++
+			name := fmt.Sprintf("%s_ibfk_%d", schema.Name, n)
+			if !used[name] {
+				fk.Name = name
+				used[name] = true
+				break
+			}
+		}
 	}
-	return result
-}
-
-// isIndex 判断是否是索引定义
-func isIndex(line string) bool {
-	upper := strings.ToUpper(line)
-	return strings.HasPrefix(upper, "INDEX") ||
-		strings.HasPrefix(upper, "KEY") ||
-		strings.HasPrefix(upper, "UNIQUE") ||
-		strings.HasPrefix(upper, "FULLTEXT")
 }
 
-// parseIndex 解析索引定义
-func parseIndex(line string) *Index {
-	upper := strings.ToUpper(line)
-
-	index := &Index{
-		Type: "INDEX",
+// ensureCheckNames 为未命名的 CHECK 约束补全名称
+// MySQL：<表名>_chk_<序号>；PostgreSQL：表达式只引用一列时为 <表名>_<列名>_check，否则为 <表名>_check；
+// SQLite 不记录 CHECK 约束的名称，保持为空
+func ensureCheckNames(schema *TableSchema, dialect Dialect) {
+	if dialect == SQLite {
+		return
 	}
-
-	if strings.HasPrefix(upper, "UNIQUE") {
-		index.Type = "UNIQUE"
-	} else if strings.HasPrefix(upper, "FULLTEXT") {
-		index.Type = "FULLTEXT"
+	used := make(map[string]bool)
+	for _, c := range schema.Constraints {
+		used[c.Name] = true
 	}
-
-	// 提取索引名和列
-	re := regexp.MustCompile(`(?:INDEX|KEY|UNIQUE|FULLTEXT)\s+(?:INDEX|KEY)?\s*` + "`?" + `([a-zA-Z0-9_]+)` + "`?" + `\s*\(([^)]+)\)`)
-	matches := re.FindStringSubmatch(line)
-
-	if len(matches) >= 3 {
-		index.Name = matches[1]
-		columns := strings.Split(matches[2], ",")
-		for _, col := range columns {
-			index.Columns = append(index.Columns, strings.Trim(strings.TrimSpace(col), "`"))
+	n := 0
+	for _, check := range schema.Checks() {
+		if check.Name != "" {
+			continue
 		}
+		if dialect == PostgreSQL {
+			var columns []string
+			if referenced := ExpressionColumns(check.Definition, schema, dialect); len(referenced) == 1 {
+				columns = referenced
+			}
+			check.Name = uniqueName(postgresConstraintName(schema.Name, columns, "check"), "%s%d", 1, used)
+			continue
+		}
+		for check.Name == "" || used[check.Name] {
+			n++
+			check.Name = fmt.Sprintf("%s_chk_%d", schema.Name, n)
+		}
+		used[check.Name] = true
 	}
-
-	return index
 }
 
-// extractTableOptions 提取表选项
-func extractTableOptions(sql string) map[string]string {
-	options := make(map[string]string)
-
-	// 提取 ENGINE
-	re := regexp.MustCompile(`(?i)ENGINE\s*=\s*([a-zA-Z0-9]+)`)
-	if matches := re.FindStringSubmatch(sql); len(matches) >= 2 {
-		options["ENGINE"] = matches[1]
-	}
-
-	// 提取 CHARSET
-	re = regexp.MustCompile(`(?i)(?:DEFAULT\s+)?CHARSET\s*=\s*([a-zA-Z0-9]+)`)
-	if matches := re.FindStringSubmatch(sql); len(matches) >= 2 {
-		options["CHARSET"] = matches[1]
+// uniqueName 返回未被使用的名称（重名时按 pattern 从 first 开始追加序号），并将其标记为已使用
+func uniqueName(base, pattern string, first int, used map[string]bool) string {
+	name := base
+	for i := first; used[name]; i++ {
+		name = fmt.Sprintf(pattern, base, i)
 	}
+	used[name] = true
+	return name
+}
 
-	// 提取 COLLATE
-	re = regexp.MustCompile(`(?i)COLLATE\s*=\s*([a-zA-Z0-9_]+)`)
-	if matches := re.FindStringSubmatch(sql); len(matches) >= 2 {
-		options["COLLATE"] = matches[1]
+// postgresConstraintName 按 PostgreSQL 的规则拼接约束名（表达式列记为 expr）
+func postgresConstraintName(table string, columns []string, suffix string) string {
+	parts := []string{table}
+	for _, col := range columns {
+		if isExpressionKeyPart(col) {
+			col = "expr"
+		}
+		parts = append(parts, col)
 	}
+	return strings.Join(append(parts, suffix), "_")
+}
 
-	return options
+// isExpressionKeyPart 判断索引列是否为函数索引表达式
+func isExpressionKeyPart(part string) bool {
+	return len(part) > 0 && part[0] == '('
 }
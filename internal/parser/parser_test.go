@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +39,13 @@ func TestParseSimpleTable(t *testing.T) {
 		t.Errorf("第一列类型错误，期望 INT，得到 %s", schema.Columns[0].Type)
 	}
 
+	// 检查列序号
+	for i, col := range schema.Columns {
+		if col.Position != i+1 {
+			t.Errorf("列 %s 序号错误，期望 %d，得到 %d", col.Name, i+1, col.Position)
+		}
+	}
+
 	// 检查主键
 	if len(schema.PrimaryKeys) != 1 || schema.PrimaryKeys[0] != "id" {
 		t.Errorf("主键错误，期望 [id]，得到 %v", schema.PrimaryKeys)
@@ -116,3 +126,519 @@ func TestParseComplexTable(t *testing.T) {
 		t.Errorf("CHARSET 错误，期望 utf8mb4，得到 %s", schema.Options["CHARSET"])
 	}
 }
+
+func TestParseQuotedContent(t *testing.T) {
+	sql := "CREATE TABLE `events` (\n" +
+		"  `id` bigint NOT NULL AUTO_INCREMENT,\n" +
+		"  `title` varchar(64) NOT NULL DEFAULT '(' COMMENT 'a, b',\n" +
+		"  `kind` enum('a(1)','b,2') DEFAULT NULL,\n" +
+		"  `price` decimal(10, 2) DEFAULT '0.00',\n" +
+		"  `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_title` (`title`(20)) USING BTREE COMMENT 'x, y'\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='events (partitioned)'\n" +
+		"PARTITION BY RANGE (`id`) (PARTITION p0 VALUES LESS THAN (1000), PARTITION p1 VALUES LESS THAN MAXVALUE);"
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	if len(schema.Columns) != 5 {
+		t.Fatalf("列数错误，期望 5，得到 %d", len(schema.Columns))
+	}
+
+	title := schema.Columns[1]
+	if title.DefaultValue != "(" || title.Comment != "a, b" {
+		t.Errorf("title 列解析错误，默认值 %q，注释 %q", title.DefaultValue, title.Comment)
+	}
+
+	kind := schema.Columns[2]
+	if kind.Type != "ENUM" || len(kind.Values) != 2 || kind.Values[0] != "a(1)" || kind.Values[1] != "b,2" {
+		t.Errorf("kind 列解析错误，类型 %s，成员 %q", kind.Type, kind.Values)
+	}
+
+	if schema.Columns[3].Length != "10,2" {
+		t.Errorf("price 长度错误，期望 10,2，得到 %s", schema.Columns[3].Length)
+	}
+
+	if schema.Columns[4].DefaultValue != "CURRENT_TIMESTAMP(3)" {
+		t.Errorf("created_at 默认值错误，得到 %s", schema.Columns[4].DefaultValue)
+	}
+
+	if len(schema.Indexes) != 1 || schema.Indexes[0].Columns[0] != "title" {
+		t.Errorf("索引解析错误: %+v", schema.Indexes)
+	}
+
+	if schema.Options["COMMENT"] != "events (partitioned)" || schema.Options["COLLATE"] != "utf8mb4_0900_ai_ci" {
+		t.Errorf("表选项解析错误: %v", schema.Options)
+	}
+}
+
+func TestParseSkipsConstraintsAndComments(t *testing.T) {
+	sql := `-- 订单明细
+	CREATE TABLE IF NOT EXISTS shop.order_items (
+		id INT NOT NULL, /* 主键 */
+		order_id INT NOT NULL,
+		sku VARCHAR(32) UNIQUE,
+		CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE,
+		CHECK (id > 0),
+		INDEX (order_id)
+	)`
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	if schema.Name != "order_items" {
+		t.Errorf("表名错误，期望 order_items，得到 %s", schema.Name)
+	}
+
+	if len(schema.Columns) != 3 {
+		t.Errorf("列数错误，期望 3，得到 %d", len(schema.Columns))
+	}
+
+	if len(schema.Indexes) != 2 || schema.Indexes[0].Name != "sku" || schema.Indexes[1].Name != "order_id" {
+		t.Errorf("索引解析错误: %+v", schema.Indexes)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"非建表语句", "SELECT 1"},
+		{"括号未闭合", "CREATE TABLE t (id INT"},
+		{"字符串未闭合", "CREATE TABLE t (id INT COMMENT 'abc)"},
+		{"未知属性", "CREATE TABLE t (id INT FOO)"},
+		{"多余内容", "CREATE TABLE t (id INT) ENGINE=InnoDB; SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewParser().Parse(tt.sql); err == nil {
+				t.Errorf("期望解析失败: %s", tt.sql)
+			}
+		})
+	}
+}
+
+func TestParseForeignKeys(t *testing.T) {
+	sql := "CREATE TABLE `order_items` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `order_id` int NOT NULL,\n" +
+		"  `sku_id` int DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `fk_order` (`order_id`),\n" +
+		"  CONSTRAINT `fk_order` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE,\n" +
+		"  FOREIGN KEY (`sku_id`) REFERENCES shop.skus (`id`) ON DELETE SET NULL ON UPDATE NO ACTION\n" +
+		") ENGINE=InnoDB"
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	if len(schema.Columns) != 3 {
+		t.Errorf("外键不应被解析为列，期望 3 列，得到 %d", len(schema.Columns))
+	}
+
+	fks := schema.ForeignKeys()
+	if len(fks) != 2 {
+		t.Fatalf("外键数量错误，期望 2，得到 %d", len(fks))
+	}
+
+	fk := fks[0]
+	if fk.Name != "fk_order" || fk.Columns[0] != "order_id" || fk.RefTable != "orders" ||
+		fk.RefColumns[0] != "id" || fk.OnDelete != "CASCADE" || fk.OnUpdate != "" {
+		t.Errorf("外键 fk_order 解析错误: %+v", fk)
+	}
+
+	fk = fks[1]
+	if fk.Name != "order_items_ibfk_1" || fk.RefTable != "skus" || fk.OnDelete != "SET NULL" || fk.OnUpdate != "NO ACTION" {
+		t.Errorf("未命名外键解析错误: %+v", fk)
+	}
+}
+
+func TestParseIndexPrefixAndVisibility(t *testing.T) {
+	sql := `CREATE TABLE posts (
+		id INT PRIMARY KEY,
+		title VARCHAR(255),
+		body TEXT,
+		KEY idx_title (title(20), id DESC),
+		KEY idx_id (id) INVISIBLE
+	)`
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	idx := schema.Indexes[0]
+	if idx.PrefixLength(0) != 20 || idx.PrefixLength(1) != 0 || idx.Invisible {
+		t.Errorf("索引 idx_title 解析错误: %+v", idx)
+	}
+
+	idx = schema.Indexes[1]
+	if idx.Lengths != nil || !idx.Invisible {
+		t.Errorf("索引 idx_id 解析错误: %+v", idx)
+	}
+
+	if _, err := NewParser().Parse("CREATE TABLE t (a TEXT, KEY idx_a (a(x)))"); err == nil {
+		t.Error("无效的前缀长度应该报错")
+	}
+}
+
+func TestParseIndexOptions(t *testing.T) {
+	sql := "CREATE TABLE docs (\n" +
+		"  id INT PRIMARY KEY,\n" +
+		"  email VARCHAR(255),\n" +
+		"  name VARCHAR(100),\n" +
+		"  body TEXT,\n" +
+		"  geo POINT NOT NULL SRID 4326,\n" +
+		"  KEY idx_name (name(20) DESC) USING BTREE INVISIBLE COMMENT 'by name',\n" +
+		"  KEY idx_hash USING HASH (id, name DESC),\n" +
+		"  UNIQUE KEY uk_email ((lower(email))),\n" +
+		"  FULLTEXT KEY ft_body (body) WITH PARSER ngram,\n" +
+		"  SPATIAL KEY sp_geo (geo)\n" +
+		")"
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	var got []string
+	for _, idx := range schema.Indexes {
+		got = append(got, fmt.Sprintf("%s|%s|%v|%v|%v|%s|%s|%s|%v",
+			idx.Name, idx.Type, idx.Columns, idx.Lengths, idx.Descending, idx.Algorithm, idx.Comment, idx.Parser, idx.Invisible))
+	}
+	expected := []string{
+		"idx_name|INDEX|[name]|[20]|[true]|BTREE|by name||true",
+		"idx_hash|INDEX|[id name]|[]|[false true]|HASH|||false",
+		"uk_email|UNIQUE|[(lower(email))]|[]|[]||||false",
+		"ft_body|FULLTEXT|[body]|[]|[]|||ngram|false",
+		"sp_geo|SPATIAL|[geo]|[]|[]||||false",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("索引解析错误:\n%s", strings.Join(got, "\n"))
+	}
+	if expr := schema.Indexes[2].Expression(0); expr != "lower(email)" {
+		t.Errorf("函数索引的表达式错误: %q", expr)
+	}
+
+	db, err := NewParserWithDialect(PostgreSQL).ParseScript(`
+CREATE TABLE events (id int, tags text[], created_at timestamp);
+CREATE INDEX idx_tags ON events USING GIN (tags);
+CREATE INDEX idx_created ON events (created_at DESC NULLS LAST);
+`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	indexes := db.Table("events").Indexes
+	if indexes[0].Algorithm != "gin" || indexes[1].Algorithm != "" || !indexes[1].IsDescending(0) {
+		t.Errorf("PostgreSQL 索引解析错误: %+v %+v", indexes[0], indexes[1])
+	}
+}
+
+func TestParseColumnAttributes(t *testing.T) {
+	sql := `CREATE TABLE t (
+		id INT(10) UNSIGNED ZEROFILL NOT NULL,
+		code VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_BIN,
+		name VARCHAR(50) CHARSET utf8mb4,
+		tag VARCHAR(20) BINARY,
+		latin VARCHAR(20) CHARACTER SET latin1 BINARY,
+		updated_at TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+		secret INT INVISIBLE,
+		location POINT NOT NULL SRID 4326
+	) DEFAULT CHARSET=utf8mb4`
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	columns := make(map[string]*Column)
+	for _, col := range schema.Columns {
+		columns[col.Name] = col
+	}
+
+	if col := columns["id"]; !col.Unsigned || !col.Zerofill {
+		t.Errorf("id 应为 UNSIGNED ZEROFILL: %+v", col)
+	}
+	if col := columns["code"]; col.Charset != "" || col.Collation != "utf8mb4_bin" {
+		t.Errorf("code 与表相同的字符集应省略，排序规则应保留: %+v", col)
+	}
+	if col := columns["name"]; col.Charset != "" || col.Collation != "" {
+		t.Errorf("name 与表相同的字符集应省略: %+v", col)
+	}
+	if col := columns["tag"]; col.Binary || col.Collation != "utf8mb4_bin" {
+		t.Errorf("tag 的 BINARY 应转换为 utf8mb4_bin: %+v", col)
+	}
+	if col := columns["latin"]; col.Charset != "latin1" || col.Collation != "latin1_bin" {
+		t.Errorf("latin 的 BINARY 应按列字符集转换: %+v", col)
+	}
+	if col := columns["updated_at"]; col.OnUpdate != "CURRENT_TIMESTAMP(3)" {
+		t.Errorf("updated_at 的 ON UPDATE 解析错误: %+v", col)
+	}
+	if col := columns["secret"]; !col.Invisible {
+		t.Errorf("secret 应为不可见列: %+v", col)
+	}
+	if col := columns["location"]; col.SRID != "4326" || !col.NotNull {
+		t.Errorf("location 的 SRID 解析错误: %+v", col)
+	}
+}
+
+func TestParseEnumValues(t *testing.T) {
+	sql := `CREATE TABLE t (
+		status ENUM('Active', 'it''s', 'a\\b', '') NOT NULL,
+		flags SET('read','write')
+	)`
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	status := schema.Columns[0]
+	expected := []string{"Active", "it's", `a\b`, ""}
+	if len(status.Values) != len(expected) || status.Length != "" {
+		t.Fatalf("status 成员解析错误: %q", status.Values)
+	}
+	for i, value := range expected {
+		if status.Values[i] != value {
+			t.Errorf("第 %d 个成员期望 %q，实际为 %q", i+1, value, status.Values[i])
+		}
+	}
+	if !status.NotNull {
+		t.Error("成员列表之后的列属性应继续解析")
+	}
+
+	if flags := schema.Columns[1]; flags.Type != "SET" || len(flags.Values) != 2 || flags.Values[1] != "write" {
+		t.Errorf("flags 成员解析错误: %+v", flags)
+	}
+
+	if _, err := NewParser().Parse("CREATE TABLE t (a ENUM(1, 2))"); err == nil {
+		t.Error("ENUM 成员不是字符串时应该报错")
+	}
+}
+
+func TestParseGeneratedColumns(t *testing.T) {
+	sql := `CREATE TABLE t (
+		price DECIMAL(10,2),
+		qty INT,
+		total DECIMAL(12,2) AS (price * qty) STORED NOT NULL,
+		label VARCHAR(20) GENERATED ALWAYS AS (concat('#', qty)) VIRTUAL COMMENT '标签',
+		doubled INT AS ((qty + 1) * 2)
+	)`
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(schema.Columns) != 5 {
+		t.Fatalf("列数错误，期望 5，得到 %d", len(schema.Columns))
+	}
+
+	tests := []struct {
+		name      string
+		generated string
+		stored    bool
+	}{
+		{"price", "", false},
+		{"total", "price * qty", true},
+		{"label", "concat('#', qty)", false},
+		{"doubled", "(qty + 1) * 2", false},
+	}
+	columns := make(map[string]*Column)
+	for _, col := range schema.Columns {
+		columns[col.Name] = col
+	}
+	for _, tt := range tests {
+		if col := columns[tt.name]; col.Generated != tt.generated || col.Stored != tt.stored {
+			t.Errorf("列 %s 的生成表达式解析错误: %+v", tt.name, col)
+		}
+	}
+	if col := columns["total"]; !col.NotNull {
+		t.Errorf("total 应为 NOT NULL: %+v", col)
+	}
+	if col := columns["label"]; col.Comment != "标签" {
+		t.Errorf("label 的注释解析错误: %+v", col)
+	}
+}
+
+func TestParseCheckConstraints(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		sql      string
+		expected []string // 名称|表达式|列|是否不强制检查
+	}{
+		{
+			name:    "MySQL",
+			dialect: MySQL,
+			sql: `CREATE TABLE orders (
+				price INT CHECK (price > 0),
+				qty INT CONSTRAINT qty_positive CHECK (qty > 0) NOT ENFORCED,
+				CHECK (price * qty < 10000),
+				CONSTRAINT orders_chk_1 CHECK (qty < 100) ENFORCED
+			)`,
+			expected: []string{
+				"orders_chk_2|price > 0|price|false",
+				"qty_positive|qty > 0|qty|true",
+				"orders_chk_3|price * qty < 10000||false",
+				"orders_chk_1|qty < 100||false",
+			},
+		},
+		{
+			name:    "PostgreSQL",
+			dialect: PostgreSQL,
+			sql: `CREATE TABLE orders (
+				price integer CHECK (price > 0),
+				qty integer CHECK (qty > 0) NO INHERIT,
+				CHECK (price > qty),
+				CHECK ("price" < 10000)
+			)`,
+			expected: []string{
+				"orders_price_check|price > 0|price|false",
+				"orders_qty_check|qty > 0|qty|false",
+				"orders_check|price > qty||false",
+				"orders_price_check1|\"price\" < 10000||false",
+			},
+		},
+		{
+			name:    "SQLite",
+			dialect: SQLite,
+			sql: `CREATE TABLE orders (
+				price INTEGER CHECK (price > 0),
+				qty INTEGER,
+				CONSTRAINT small CHECK (price < 100)
+			)`,
+			expected: []string{
+				"|price > 0|price|false",
+				"small|price < 100||false",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewParserWithDialect(tt.dialect).Parse(tt.sql)
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			var checks []string
+			for _, check := range schema.Checks() {
+				checks = append(checks, fmt.Sprintf("%s|%s|%s|%v",
+					check.Name, check.Definition, strings.Join(check.Columns, ","), check.NotEnforced))
+			}
+			if strings.Join(checks, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("CHECK 约束解析错误:\n%s", strings.Join(checks, "\n"))
+			}
+			if len(schema.Columns) != 2 {
+				t.Errorf("CHECK 约束不应被解析为列: %d 列", len(schema.Columns))
+			}
+		})
+	}
+}
+
+func TestParsePartitions(t *testing.T) {
+	sql := "CREATE TABLE `metrics` (\n" +
+		"  `id` bigint NOT NULL,\n" +
+		"  `created_at` date NOT NULL,\n" +
+		"  PRIMARY KEY (`id`,`created_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4\n" +
+		"/*!50500 PARTITION BY RANGE  COLUMNS(created_at)\n" +
+		"(PARTITION p2023 VALUES LESS THAN ('2024-01-01') ENGINE = InnoDB,\n" +
+		" PARTITION p2024 VALUES LESS THAN ('2025-01-01') COMMENT = 'current' ENGINE = InnoDB,\n" +
+		" PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */;"
+
+	schema, err := NewParser().Parse(sql)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(schema.Columns) != 2 {
+		t.Fatalf("分区定义不应被当作列，期望 2 列，得到 %d 列", len(schema.Columns))
+	}
+
+	p := schema.Partitioning
+	if p == nil || p.Type != "RANGE COLUMNS" || p.Expression != "created_at" || len(p.Partitions) != 3 {
+		t.Fatalf("分区方案解析错误: %+v", p)
+	}
+	var got []string
+	for _, partition := range p.Partitions {
+		got = append(got, fmt.Sprintf("%s|%s|%v", partition.Name, partition.Values, partition.Options))
+	}
+	expected := []string{
+		"p2023|LESS THAN ('2024-01-01')|map[]",
+		"p2024|LESS THAN ('2025-01-01')|map[COMMENT:current]",
+		"pmax|LESS THAN MAXVALUE|map[]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("分区定义解析错误:\n%s", strings.Join(got, "\n"))
+	}
+
+	tests := []struct {
+		name     string
+		clause   string
+		expected string
+	}{
+		{"HASH 分区", "PARTITION BY HASH (id) PARTITIONS 4", "HASH||id|4||0"},
+		{"LINEAR KEY 分区", "PARTITION BY LINEAR KEY ALGORITHM=2 (id) PARTITIONS 8", "LINEAR KEY|2|id|8||0"},
+		{"LIST 分区", "PARTITION BY LIST (id) (PARTITION a VALUES IN (1, 2), PARTITION b VALUES IN (3))", "LIST||id|0||2"},
+		{"子分区", "PARTITION BY RANGE (YEAR(created_at)) SUBPARTITION BY HASH (id) SUBPARTITIONS 2 " +
+			"(PARTITION p0 VALUES LESS THAN (2024) (SUBPARTITION s0, SUBPARTITION s1))", "RANGE||YEAR(created_at)|0|HASH (id) SUBPARTITIONS 2|1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewParser().Parse("CREATE TABLE t (id INT, created_at DATE) " + tt.clause)
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			p := schema.Partitioning
+			got := fmt.Sprintf("%s|%s|%s|%d|%s|%d", p.Type, p.Algorithm, p.Expression, p.Count, p.Subpartition, len(p.Partitions))
+			if got != tt.expected {
+				t.Errorf("期望 %s，得到 %s", tt.expected, got)
+			}
+		})
+	}
+
+	schema, err = NewParserWithDialect(PostgreSQL).Parse(`CREATE TABLE events (id int, created_at date) PARTITION BY RANGE (created_at);`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if p := schema.Partitioning; p == nil || p.Type != "RANGE" || p.Expression != "created_at" {
+		t.Errorf("PostgreSQL 分区键解析错误: %+v", p)
+	}
+}
+
+func TestTableClone(t *testing.T) {
+	db, err := NewParser().ParseScript(`CREATE TABLE t (
+		id INT PRIMARY KEY,
+		status ENUM('a','b'),
+		ref INT,
+		KEY idx_status (status(1) DESC),
+		CONSTRAINT fk_ref FOREIGN KEY (ref) REFERENCES u (id)
+	) ENGINE=InnoDB PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (10) COMMENT 'x');`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	original := db.Tables[0]
+	clone := db.Clone().Tables[0]
+	if !reflect.DeepEqual(original, clone) {
+		t.Fatalf("副本与原结构不同:\n%+v\n%+v", original, clone)
+	}
+
+	clone.Columns[1].Values[0] = "z"
+	clone.PrimaryKeys[0] = "ref"
+	clone.Indexes[0].Descending[0] = false
+	clone.Constraints[0].RefColumns[0] = "uid"
+	clone.Options["ENGINE"] = "MyISAM"
+	clone.Partitioning.Partitions[0].Options["COMMENT"] = "y"
+	if original.Columns[1].Values[0] != "a" || original.PrimaryKeys[0] != "id" || !original.Indexes[0].Descending[0] ||
+		original.Constraints[0].RefColumns[0] != "id" || original.Options["ENGINE"] != "InnoDB" ||
+		original.Partitioning.Partitions[0].Options["COMMENT"] != "x" {
+		t.Errorf("修改副本影响了原结构: %+v", original)
+	}
+}
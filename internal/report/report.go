@@ -0,0 +1,333 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Bacchusgift/sql-diff/internal/ai"
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion 结构化输出的格式版本
+// 只新增字段时保持不变；删除、重命名字段或改变字段含义时必须升级
+const SchemaVersion = "1"
+
+// 支持的输出格式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Report 比对结果的结构化报告
+type Report struct {
+	SchemaVersion      string      `json:"schema_version" yaml:"schema_version"`
+	HasChanges         bool        `json:"has_changes" yaml:"has_changes"`
+	Risk               string      `json:"risk" yaml:"risk"`
+	Statistics         Statistics  `json:"statistics" yaml:"statistics"`
+	Changes            []Change    `json:"changes" yaml:"changes"`
+	Statements         []string    `json:"statements" yaml:"statements"`
+	RollbackStatements []string    `json:"rollback_statements" yaml:"rollback_statements"`
+	AIAnalysis         *AIAnalysis `json:"ai_analysis,omitempty" yaml:"ai_analysis,omitempty"`
+}
+
+// Statistics 各类变更的数量
+type Statistics struct {
+	Total         int            `json:"total" yaml:"total"`
+	ByKind        map[string]int `json:"by_kind" yaml:"by_kind"`
+	ByRisk        map[string]int `json:"by_risk" yaml:"by_risk"`
+	TablesChanged int            `json:"tables_changed" yaml:"tables_changed"`
+}
+
+// Change 单项变更
+type Change struct {
+	Kind    string      `json:"kind" yaml:"kind"`
+	Table   string      `json:"table" yaml:"table"`
+	Name    string      `json:"name" yaml:"name"`
+	From    string      `json:"from,omitempty" yaml:"from,omitempty"`
+	Risk    string      `json:"risk" yaml:"risk"`
+	Details []string    `json:"details" yaml:"details"`
+	Before  *Definition `json:"before,omitempty" yaml:"before,omitempty"`
+	After   *Definition `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// Definition 变更前后的定义
+// SQL 为定义的 SQL 片段，其余字段按对象类型填写（列、索引、外键、CHECK 约束、主键、表、分区方案或分区）
+type Definition struct {
+	SQL string `json:"sql" yaml:"sql"`
+
+	Column     *Column     `json:"column,omitempty" yaml:"column,omitempty"`
+	Index      *Index      `json:"index,omitempty" yaml:"index,omitempty"`
+	ForeignKey *ForeignKey `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+	Check      *Check      `json:"check,omitempty" yaml:"check,omitempty"`
+	Columns    []string    `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Table      *Table      `json:"table,omitempty" yaml:"table,omitempty"`
+
+	Partitioning *Partitioning `json:"partitioning,omitempty" yaml:"partitioning,omitempty"`
+	Partitions   []*Partition  `json:"partitions,omitempty" yaml:"partitions,omitempty"`
+}
+
+// Column 列定义
+type Column struct {
+	Name          string   `json:"name" yaml:"name"`
+	Type          string   `json:"type" yaml:"type"`
+	Length        string   `json:"length,omitempty" yaml:"length,omitempty"`
+	Values        []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Unsigned      bool     `json:"unsigned" yaml:"unsigned"`
+	Zerofill      bool     `json:"zerofill" yaml:"zerofill"`
+	Charset       string   `json:"charset,omitempty" yaml:"charset,omitempty"`
+	Collation     string   `json:"collation,omitempty" yaml:"collation,omitempty"`
+	Binary        bool     `json:"binary" yaml:"binary"`
+	NotNull       bool     `json:"not_null" yaml:"not_null"`
+	Default       string   `json:"default,omitempty" yaml:"default,omitempty"`
+	OnUpdate      string   `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+	AutoIncrement bool     `json:"auto_increment" yaml:"auto_increment"`
+	Comment       string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Invisible     bool     `json:"invisible" yaml:"invisible"`
+	SRID          string   `json:"srid,omitempty" yaml:"srid,omitempty"`
+	Generated     string   `json:"generated,omitempty" yaml:"generated,omitempty"`
+	Stored        bool     `json:"stored,omitempty" yaml:"stored,omitempty"`
+	Position      int      `json:"position" yaml:"position"`
+}
+
+// Index 索引定义
+type Index struct {
+	Name       string   `json:"name" yaml:"name"`
+	Type       string   `json:"type" yaml:"type"`
+	Columns    []string `json:"columns" yaml:"columns"`
+	Lengths    []int    `json:"lengths,omitempty" yaml:"lengths,omitempty"`
+	Descending []bool   `json:"descending,omitempty" yaml:"descending,omitempty"`
+	Algorithm  string   `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	Comment    string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Parser     string   `json:"parser,omitempty" yaml:"parser,omitempty"`
+	Invisible  bool     `json:"invisible" yaml:"invisible"`
+}
+
+// ForeignKey 外键定义
+type ForeignKey struct {
+	Name       string   `json:"name" yaml:"name"`
+	Columns    []string `json:"columns" yaml:"columns"`
+	RefTable   string   `json:"ref_table" yaml:"ref_table"`
+	RefColumns []string `json:"ref_columns" yaml:"ref_columns"`
+	OnDelete   string   `json:"on_delete,omitempty" yaml:"on_delete,omitempty"`
+	OnUpdate   string   `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+}
+
+// Check CHECK 约束定义
+type Check struct {
+	Name        string   `json:"name" yaml:"name"`
+	Expression  string   `json:"expression" yaml:"expression"`
+	Columns     []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	NotEnforced bool     `json:"not_enforced,omitempty" yaml:"not_enforced,omitempty"`
+}
+
+// Table 表定义概要
+type Table struct {
+	Name    string   `json:"name" yaml:"name"`
+	Columns []string `json:"columns" yaml:"columns"`
+}
+
+// Partitioning 分区方案
+type Partitioning struct {
+	Type         string       `json:"type" yaml:"type"`
+	Algorithm    string       `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	Expression   string       `json:"expression" yaml:"expression"`
+	Count        int          `json:"count,omitempty" yaml:"count,omitempty"`
+	Subpartition string       `json:"subpartition,omitempty" yaml:"subpartition,omitempty"`
+	Partitions   []*Partition `json:"partitions,omitempty" yaml:"partitions,omitempty"`
+}
+
+// Partition 分区定义
+type Partition struct {
+	Name    string            `json:"name" yaml:"name"`
+	Values  string            `json:"values,omitempty" yaml:"values,omitempty"`
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// AIAnalysis AI 分析结果
+type AIAnalysis struct {
+	Summary      string   `json:"summary" yaml:"summary"`
+	Suggestions  []string `json:"suggestions" yaml:"suggestions"`
+	Risks        []string `json:"risks" yaml:"risks"`
+	BestPractice []string `json:"best_practice" yaml:"best_practice"`
+	Error        string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// New 根据数据库差异生成报告
+func New(diff *differ.DatabaseDiff) *Report {
+	changes := diff.Changes()
+
+	r := &Report{
+		SchemaVersion: SchemaVersion,
+		HasChanges:    diff.HasChanges(),
+		Risk:          diff.Risk().String(),
+		Statistics: Statistics{
+			Total:  len(changes),
+			ByKind: make(map[string]int),
+			ByRisk: make(map[string]int),
+		},
+		Changes:            make([]Change, 0, len(changes)),
+		Statements:         diff.GenerateDDL(),
+		RollbackStatements: diff.GenerateRollbackDDL(),
+	}
+
+	tables := make(map[string]bool)
+	for _, c := range changes {
+		r.Changes = append(r.Changes, newChange(c))
+		r.Statistics.ByKind[string(c.Kind)]++
+		r.Statistics.ByRisk[c.Risk.String()]++
+		tables[c.Table] = true
+	}
+	r.Statistics.TablesChanged = len(tables)
+
+	return r
+}
+
+// SetAIAnalysis 设置 AI 分析结果（err 不为空时记录失败原因）
+func (r *Report) SetAIAnalysis(result *ai.AnalysisResult, err error) {
+	if err != nil {
+		r.AIAnalysis = &AIAnalysis{Error: err.Error()}
+		return
+	}
+	r.AIAnalysis = &AIAnalysis{
+		Summary:      result.Summary,
+		Suggestions:  result.Suggestions,
+		Risks:        result.Risks,
+		BestPractice: result.BestPractice,
+	}
+}
+
+// Marshal 按指定格式序列化报告
+func (r *Report) Marshal(format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(r)
+	default:
+		return nil, fmt.Errorf("不支持的输出格式: %s（可选 text、json、yaml）", format)
+	}
+}
+
+// newChange 转换单项变更
+func newChange(c *differ.Change) Change {
+	details := c.Details
+	if details == nil {
+		details = []string{}
+	}
+	return Change{
+		Kind:    string(c.Kind),
+		Table:   c.Table,
+		Name:    c.Name,
+		From:    c.From,
+		Risk:    c.Risk.String(),
+		Details: details,
+		Before:  newDefinition(c.Source, c.SourceSQL),
+		After:   newDefinition(c.Target, c.TargetSQL),
+	}
+}
+
+// newDefinition 根据对象类型转换定义
+func newDefinition(obj interface{}, sql string) *Definition {
+	def := &Definition{SQL: sql}
+
+	switch v := obj.(type) {
+	case *parser.Column:
+		def.Column = &Column{
+			Name:          v.Name,
+			Type:          v.Type,
+			Length:        v.Length,
+			Values:        v.Values,
+			Unsigned:      v.Unsigned,
+			Zerofill:      v.Zerofill,
+			Charset:       v.Charset,
+			Collation:     v.Collation,
+			Binary:        v.Binary,
+			NotNull:       v.NotNull,
+			Default:       v.DefaultValue,
+			OnUpdate:      v.OnUpdate,
+			AutoIncrement: v.AutoInc,
+			Comment:       v.Comment,
+			Invisible:     v.Invisible,
+			SRID:          v.SRID,
+			Generated:     v.Generated,
+			Stored:        v.Stored,
+			Position:      v.Position,
+		}
+	case *parser.Index:
+		def.Index = &Index{
+			Name:       v.Name,
+			Type:       v.Type,
+			Columns:    v.Columns,
+			Lengths:    v.Lengths,
+			Descending: v.Descending,
+			Algorithm:  v.Algorithm,
+			Comment:    v.Comment,
+			Parser:     v.Parser,
+			Invisible:  v.Invisible,
+		}
+	case *parser.Constraint:
+		if v.Type == "CHECK" {
+			def.Check = &Check{
+				Name:        v.Name,
+				Expression:  v.Definition,
+				Columns:     v.Columns,
+				NotEnforced: v.NotEnforced,
+			}
+			break
+		}
+		def.ForeignKey = &ForeignKey{
+			Name:       v.Name,
+			Columns:    v.Columns,
+			RefTable:   v.RefTable,
+			RefColumns: v.RefColumns,
+			OnDelete:   v.OnDelete,
+			OnUpdate:   v.OnUpdate,
+		}
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+		def.Columns = v
+	case *parser.TableSchema:
+		table := &Table{Name: v.Name}
+		for _, col := range v.Columns {
+			table.Columns = append(table.Columns, col.Name)
+		}
+		def.Table = table
+	case *parser.Partitioning:
+		def.Partitioning = &Partitioning{
+			Type:         v.Type,
+			Algorithm:    v.Algorithm,
+			Expression:   v.Expression,
+			Count:        v.Count,
+			Subpartition: v.Subpartition,
+			Partitions:   newPartitions(v.Partitions),
+		}
+	case []*parser.Partition:
+		def.Partitions = newPartitions(v)
+	default:
+		return nil
+	}
+
+	return def
+}
+
+// newPartitions 转换分区定义列表
+func newPartitions(partitions []*parser.Partition) []*Partition {
+	var result []*Partition
+	for _, p := range partitions {
+		partition := &Partition{Name: p.Name, Values: p.Values}
+		if len(p.Options) > 0 {
+			partition.Options = p.Options
+		}
+		result = append(result, partition)
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Bacchusgift/sql-diff/internal/differ"
+	"github.com/Bacchusgift/sql-diff/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// newTestReport 解析源和目标结构并生成报告
+func newTestReport(t *testing.T, sourceSQL, targetSQL string) *Report {
+	t.Helper()
+
+	p := parser.NewParser()
+	source, err := p.ParseScript(sourceSQL)
+	if err != nil {
+		t.Fatalf("解析源结构失败: %v", err)
+	}
+	target, err := p.ParseScript(targetSQL)
+	if err != nil {
+		t.Fatalf("解析目标结构失败: %v", err)
+	}
+	return New(differ.NewDatabaseDiffer(source, target).Compare())
+}
+
+func TestNew(t *testing.T) {
+	r := newTestReport(t,
+		`CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(20), age INT);
+		CREATE TABLE legacy (id INT);`,
+		`CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50), email VARCHAR(100), UNIQUE KEY uk_email (email));`)
+
+	if r.SchemaVersion != SchemaVersion {
+		t.Errorf("期望 schema_version 为 %s，实际为 %s", SchemaVersion, r.SchemaVersion)
+	}
+	if !r.HasChanges {
+		t.Error("期望 has_changes 为 true")
+	}
+	if r.Risk != "destructive" {
+		t.Errorf("期望风险等级为 destructive，实际为 %s", r.Risk)
+	}
+
+	expectedKinds := map[string]int{
+		"add_column":    1,
+		"modify_column": 1,
+		"drop_column":   1,
+		"add_index":     1,
+		"drop_table":    1,
+	}
+	if r.Statistics.Total != 5 {
+		t.Errorf("期望 5 项变更，实际为 %d", r.Statistics.Total)
+	}
+	for kind, count := range expectedKinds {
+		if r.Statistics.ByKind[kind] != count {
+			t.Errorf("期望 %s 为 %d 项，实际为 %d", kind, count, r.Statistics.ByKind[kind])
+		}
+	}
+	if r.Statistics.TablesChanged != 2 {
+		t.Errorf("期望 2 张表有变更，实际为 %d", r.Statistics.TablesChanged)
+	}
+
+	var modify *Change
+	for i := range r.Changes {
+		if r.Changes[i].Kind == "modify_column" {
+			modify = &r.Changes[i]
+		}
+	}
+	if modify == nil {
+		t.Fatal("缺少 modify_column 变更")
+	}
+	if modify.Before == nil || modify.Before.SQL != "VARCHAR(20)" || modify.Before.Column.Length != "20" {
+		t.Errorf("修改前的定义不正确: %+v", modify.Before)
+	}
+	if modify.After == nil || modify.After.SQL != "VARCHAR(50)" || modify.After.Column.Length != "50" {
+		t.Errorf("修改后的定义不正确: %+v", modify.After)
+	}
+	if modify.Risk != "warning" {
+		t.Errorf("期望修改列类型的风险等级为 warning，实际为 %s", modify.Risk)
+	}
+
+	if len(r.Statements) == 0 || len(r.RollbackStatements) == 0 {
+		t.Error("期望报告包含迁移和回滚语句")
+	}
+}
+
+func TestNewNoChanges(t *testing.T) {
+	sql := `CREATE TABLE users (id INT PRIMARY KEY)`
+	r := newTestReport(t, sql, sql)
+
+	if r.HasChanges || r.Risk != "none" || r.Statistics.Total != 0 {
+		t.Errorf("期望没有变更，实际为 has_changes=%v risk=%s total=%d", r.HasChanges, r.Risk, r.Statistics.Total)
+	}
+	if r.Changes == nil || r.Statements == nil {
+		t.Error("没有变更时 changes 和 statements 应为空数组而不是 null")
+	}
+}
+
+func TestNewColumnAttributes(t *testing.T) {
+	r := newTestReport(t,
+		`CREATE TABLE t (id INT PRIMARY KEY, code INT, name VARCHAR(20), flag CHAR(1), ts TIMESTAMP, pt POINT NOT NULL)`,
+		"CREATE TABLE t (id INT PRIMARY KEY, code INT(6) ZEROFILL, name VARCHAR(20) CHARACTER SET latin1 COLLATE latin1_bin, "+
+			"flag CHAR(1) BINARY, ts TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP INVISIBLE, pt POINT NOT NULL SRID 4326)")
+
+	after := make(map[string]*Column)
+	for _, c := range r.Changes {
+		if c.Kind == "modify_column" && c.After != nil {
+			after[c.Name] = c.After.Column
+		}
+	}
+	if c := after["code"]; c == nil || !c.Zerofill {
+		t.Errorf("期望 code 的 zerofill 为 true，实际为 %+v", c)
+	}
+	if c := after["name"]; c == nil || c.Charset != "latin1" || c.Collation != "latin1_bin" {
+		t.Errorf("期望 name 的字符集为 latin1、排序规则为 latin1_bin，实际为 %+v", c)
+	}
+	if c := after["flag"]; c == nil || !c.Binary {
+		t.Errorf("期望 flag 的 binary 为 true，实际为 %+v", c)
+	}
+	if c := after["ts"]; c == nil || c.OnUpdate != "CURRENT_TIMESTAMP" || !c.Invisible {
+		t.Errorf("期望 ts 的 on_update 为 CURRENT_TIMESTAMP 且不可见，实际为 %+v", c)
+	}
+	if c := after["pt"]; c == nil || c.SRID != "4326" {
+		t.Errorf("期望 pt 的 srid 为 4326，实际为 %+v", c)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	r := newTestReport(t,
+		`CREATE TABLE users (id INT PRIMARY KEY, nick VARCHAR(20))`,
+		`CREATE TABLE users (id INT PRIMARY KEY, nick VARCHAR(20), INDEX idx_nick (nick))`)
+	r.SetAIAnalysis(nil, errors.New("超时"))
+
+	data, err := r.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatalf("JSON 序列化失败: %v", err)
+	}
+	var fromJSON map[string]interface{}
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("JSON 反序列化失败: %v", err)
+	}
+	if fromJSON["schema_version"] != SchemaVersion || fromJSON["risk"] != "safe" {
+		t.Errorf("JSON 输出不正确: %s", data)
+	}
+	analysis, _ := fromJSON["ai_analysis"].(map[string]interface{})
+	if analysis["error"] != "超时" {
+		t.Errorf("期望 ai_analysis.error 为 超时，实际为 %v", analysis["error"])
+	}
+
+	data, err = r.Marshal(FormatYAML)
+	if err != nil {
+		t.Fatalf("YAML 序列化失败: %v", err)
+	}
+	var fromYAML Report
+	if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatalf("YAML 反序列化失败: %v", err)
+	}
+	if len(fromYAML.Changes) != 1 || fromYAML.Changes[0].Kind != "add_index" || fromYAML.Changes[0].After.Index.Name != "idx_nick" {
+		t.Errorf("YAML 输出不正确:\n%s", data)
+	}
+
+	if _, err := r.Marshal("xml"); err == nil {
+		t.Error("期望不支持的格式返回错误")
+	}
+}
@@ -0,0 +1,67 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Git 从本地 git 仓库的对象库读取指定版本的文件（不修改工作区）
+type Git struct {
+	Dir string // 仓库内的工作目录（为空表示当前目录），路径参数相对于该目录
+}
+
+// NewGit 创建 git 读取器
+func NewGit(dir string) *Git {
+	return &Git{Dir: dir}
+}
+
+// ReadSQLFiles 读取指定版本中 paths（文件或目录）下的所有 .sql 文件，按路径排序（数字按数值比较）
+// paths 为空时读取整个仓库
+func (g *Git) ReadSQLFiles(rev string, paths []string) ([]File, error) {
+	if _, err := g.run("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("无效的版本 %s", rev)
+	}
+
+	args := append([]string{"ls-tree", "-r", "-z", "--name-only", "--full-name", rev, "--"}, paths...)
+	out, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" && IsSQLFile(name) {
+			names = append(names, name)
+		}
+	}
+	SortPaths(names)
+
+	files := make([]File, 0, len(names))
+	for _, name := range names {
+		content, err := g.run("cat-file", "blob", rev+":"+name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: rev + ":" + name, Content: string(content)})
+	}
+	return files, nil
+}
+
+// run 执行 git 命令并返回标准输出，失败时错误信息包含 git 的标准错误输出
+func (g *Git) run(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("执行 git %s 失败: %s", args[0], msg)
+	}
+	return out, nil
+}
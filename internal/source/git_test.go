@@ -0,0 +1,94 @@
+package source
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo 创建临时 git 仓库并依次提交每个版本的文件（内容为空表示删除该文件）
+func initRepo(t *testing.T, versions ...map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("未安装 git")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s 失败: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "test")
+	for i, files := range versions {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if content == "" {
+				os.Remove(path)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", string(rune('1'+i)))
+	}
+	return dir
+}
+
+func TestGitReadSQLFiles(t *testing.T) {
+	dir := initRepo(t,
+		map[string]string{
+			"db/users.sql":  "CREATE TABLE users (id INT);",
+			"db/orders.sql": "CREATE TABLE orders (id INT);",
+			"README.md":     "schema",
+		},
+		map[string]string{
+			"db/users.sql":  "CREATE TABLE users (id INT, name VARCHAR(50));",
+			"db/orders.sql": "",
+		},
+	)
+	g := NewGit(dir)
+
+	files, err := g.ReadSQLFiles("HEAD~1", []string{"db"})
+	if err != nil {
+		t.Fatalf("读取 HEAD~1 失败: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "HEAD~1:db/orders.sql" || files[1].Name != "HEAD~1:db/users.sql" {
+		t.Fatalf("HEAD~1 的文件不正确: %+v", files)
+	}
+
+	files, err = g.ReadSQLFiles("HEAD", nil)
+	if err != nil {
+		t.Fatalf("读取 HEAD 失败: %v", err)
+	}
+	if len(files) != 1 || !strings.Contains(files[0].Content, "name VARCHAR(50)") {
+		t.Errorf("HEAD 的文件不正确: %+v", files)
+	}
+
+	if _, err := g.ReadSQLFiles("no-such-rev", nil); err == nil {
+		t.Error("期望无效的版本返回错误")
+	}
+}
+
+func TestJoinFiles(t *testing.T) {
+	script := JoinFiles([]File{
+		{Name: "a.sql", Content: "CREATE TABLE a (id INT)"},
+		{Name: "b.sql", Content: "CREATE TABLE b (id INT);"},
+	})
+	expected := "-- 文件: a.sql\nCREATE TABLE a (id INT)\n;\n-- 文件: b.sql\nCREATE TABLE b (id INT);"
+	if script != expected {
+		t.Errorf("合并结果不正确:\n%s", script)
+	}
+}
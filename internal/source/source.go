@@ -0,0 +1,76 @@
+// Package source 从文件以外的来源（git 仓库等）读取表结构 SQL
+package source
+
+import (
+	"sort"
+	"strings"
+)
+
+// File 一个 SQL 文件的名称和内容
+type File struct {
+	Name    string
+	Content string
+}
+
+// JoinFiles 将多个 SQL 文件合并为一个脚本
+// 每个文件之间补充分号，避免文件末尾缺少分号时与下一个文件的语句连在一起
+func JoinFiles(files []File) string {
+	var script strings.Builder
+	for _, file := range files {
+		if script.Len() > 0 {
+			script.WriteString("\n;\n")
+		}
+		script.WriteString("-- 文件: " + file.Name + "\n")
+		script.WriteString(file.Content)
+	}
+	return script.String()
+}
+
+// IsSQLFile 判断文件名是否为 .sql 文件
+func IsSQLFile(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".sql")
+}
+
+// SortPaths 按路径排序，路径中的数字按数值比较，
+// 使 V2__add_phone.sql 排在 V10__add_index.sql 之前，迁移脚本按版本顺序合并
+func SortPaths(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return lessPath(paths[i], paths[j])
+	})
+}
+
+// lessPath 逐段比较两个路径，连续的数字按数值比较，其余字符按字节比较
+func lessPath(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := leadingDigits(a), leadingDigits(b)
+			x, y := strings.TrimLeft(a[:na], "0"), strings.TrimLeft(b[:nb], "0")
+			if len(x) != len(y) {
+				return len(x) < len(y)
+			}
+			if x != y {
+				return x < y
+			}
+			a, b = a[na:], b[nb:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits 返回字符串开头连续数字的长度
+func leadingDigits(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,30 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortPaths(t *testing.T) {
+	paths := []string{
+		"db/V10__add_index.sql",
+		"db/V2__add_phone.sql",
+		"db/V1__init.sql",
+		"db/R__views.sql",
+		"db/V3__v100_rename.sql",
+		"db/V3__v20_rename.sql",
+	}
+	SortPaths(paths)
+
+	expected := []string{
+		"db/R__views.sql",
+		"db/V1__init.sql",
+		"db/V2__add_phone.sql",
+		"db/V3__v20_rename.sql",
+		"db/V3__v100_rename.sql",
+		"db/V10__add_index.sql",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("排序结果错误: %v", paths)
+	}
+}